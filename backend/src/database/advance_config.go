@@ -1,18 +1,334 @@
 package database
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 // AdvanceConfigProject defines advance configuration structure for projects
 type AdvanceConfigProject struct {
-	DelayMs int `json:"delayMs,omitempty"` // Response delay in milliseconds (0-120000)
+	DelayMs               int                `json:"delayMs,omitempty"`               // Response delay in milliseconds (0-120000)
+	DelayRange            *DelayRange        `json:"delayRange,omitempty"`            // Random delay per request, used instead of delayMs when set
+	ProxyResponseHeaders  *HeaderFilter      `json:"proxyResponseHeaders,omitempty"`  // Filter applied to upstream response headers in proxy/forwarder mode
+	LimitExceededResponse *CustomResponse    `json:"limitExceededResponse,omitempty"` // Response sent when a rate or concurrency limit is exceeded
+	MaxInFlight           int                `json:"maxInFlight,omitempty"`           // Concurrent requests allowed before answering 503 (0 = unlimited)
+	TokenBucket           *TokenBucketConfig `json:"tokenBucket,omitempty"`           // Project-wide request quota shared by all endpoints, 429 when exhausted
+	RateLimit             *RateLimitWindow   `json:"rateLimit,omitempty"`             // Project-wide requests per fixed window, 429 when exceeded
+	LoopDetectedResponse  *CustomResponse    `json:"loopDetectedResponse,omitempty"`  // Response sent when a proxy or redirect loop is detected (default 508)
+	ProxyHostHeader       string             `json:"proxyHostHeader,omitempty"`       // Host sent upstream: "target" (default), "original" or a literal host; a proxy target's hostHeader wins
+	InvalidModeResponse   *CustomResponse    `json:"invalidModeResponse,omitempty"`   // Response sent when the project mode is not a supported value (default 500)
+	CancelledResponse     *CustomResponse    `json:"cancelledResponse,omitempty"`     // Recorded for requests the client abandoned before a response was built (default 499)
+	NotFoundResponse      *CustomResponse    `json:"notFoundResponse,omitempty"`      // Response sent when no endpoint matches the request (default 404)
+	DateOffsetSeconds     int                `json:"dateOffsetSeconds,omitempty"`     // Shift of the Date header of mock responses from the real time (may be negative)
+	ProxyRoutes           []ProxyRoute       `json:"proxyRoutes,omitempty"`           // Content-based routing to specific proxy targets, first match wins
+	ProxyDecodeResponse   bool               `json:"proxyDecodeResponse,omitempty"`   // Decode gzip/br/deflate/zstd upstream bodies so request logs capture them readable
+	ProxyTimeoutMs        int                `json:"proxyTimeoutMs,omitempty"`        // Upstream timeout in proxy/forwarder mode, covering the whole response body (0 = 30s)
+	ProxyTLSVerify        *bool              `json:"proxyTlsVerify,omitempty"`        // Verify upstream TLS certificates; unset follows the PROXY_TLS_VERIFY system config
+	ProxyHTTPVersion      string             `json:"proxyHttpVersion,omitempty"`      // Upstream protocol: "http1" (default), "auto" (HTTP/2 when TLS negotiates it) or "http2" (h2c on http targets)
+	ProxyRetry            *ProxyRetryConfig  `json:"proxyRetry,omitempty"`            // Retry failed upstream requests with exponential backoff
+	ProxyCircuitBreaker   *CircuitBreaker    `json:"proxyCircuitBreaker,omitempty"`   // Stop forwarding to a proxy target that keeps failing, per target
+	ProxyRequestHeaders   *HeaderRewrite     `json:"proxyRequestHeaders,omitempty"`   // Headers added, overridden or removed before forwarding upstream
+	ProxyResponseRewrite  *HeaderRewrite     `json:"proxyResponseRewrite,omitempty"`  // Upstream response header edits, applied after proxyResponseHeaders
+	Record                bool               `json:"record,omitempty"`                // Save proxied requests and upstream responses as mock endpoints and responses
+	CaseInsensitivePaths  bool               `json:"caseInsensitivePaths,omitempty"`  // Match endpoint paths regardless of case, e.g. /Users to /users
+	NormalizePaths        bool               `json:"normalizePaths,omitempty"`        // Collapse repeated slashes and drop a trailing slash from request paths
+	AutoHead              *bool              `json:"autoHead,omitempty"`              // Answer HEAD with the matching GET endpoint minus the body when no HEAD endpoint exists (default true)
+	AutoOptions           *bool              `json:"autoOptions,omitempty"`           // Answer OPTIONS with 204 and an Allow header listing the path's methods when no OPTIONS endpoint exists (default true)
+	CORS                  *CORSPolicy        `json:"cors,omitempty"`                  // Project CORS handling: headers on every response and automatic preflight answers
+	Session               *SessionConfig     `json:"session,omitempty"`               // Per-client key/value state for response templates, keyed by a header or cookie
+	GRPC                  *GRPCConfig        `json:"grpc,omitempty"`                  // Mock unary gRPC calls with the message types of a protobuf descriptor set
+	ProxyFallback         *ProxyFallback     `json:"proxyFallback,omitempty"`         // Serve a mock instead of upstream answers with the listed statuses (proxy mode)
+	DebugHeader           bool               `json:"debugHeader,omitempty"`           // Explain how each response was chosen in a beo-echo-debug header; exposes internal IDs
+}
+
+// ProxyFallback replaces upstream answers in proxy mode for graceful-degradation testing. When
+// the upstream status is listed, the responses of EndpointID are matched against the request
+// as in mock mode; Response is served when no endpoint is set or none of its responses match.
+// Connection failures count as 502 and upstream timeouts as 504.
+type ProxyFallback struct {
+	StatusCodes []int           `json:"statusCodes"`          // e.g. [500, 502, 503, 504]
+	EndpointID  string          `json:"endpointId,omitempty"` // Mock endpoint of the project whose responses answer instead
+	Response    *CustomResponse `json:"response,omitempty"`   // Fixed fallback; a zero statusCode keeps the upstream status
+}
+
+// GRPCConfig enables gRPC mocking. Calls are matched against POST endpoints whose path is
+// /<package.Service>/<Method>; request messages are converted to JSON for rules and templates,
+// and JSON response bodies are encoded with the method's output type.
+type GRPCConfig struct {
+	DescriptorSet string `json:"descriptorSet"` // Base64 FileDescriptorSet, e.g. from protoc --include_imports --descriptor_set_out
+}
+
+// SessionConfig identifies the session of a request for the in-memory session store. The
+// header is checked first, then the cookie; requests carrying neither have no session.
+type SessionConfig struct {
+	Header     string `json:"header,omitempty"`     // e.g. "Authorization" or "X-Session-Id"
+	Cookie     string `json:"cookie,omitempty"`     // e.g. "session_id"
+	TTLSeconds int    `json:"ttlSeconds,omitempty"` // Idle time before a session's values are dropped (0-604800, 0 = 1800)
+}
+
+// CORSPolicy makes beo-echo answer CORS for the project instead of the server-wide CORS_ORIGIN
+// policy. Origins may be "*", an exact origin or a subdomain wildcard such as
+// "https://*.example.com". A matching origin is echoed back, except that "*" is answered as
+// "*" when credentials are not allowed.
+type CORSPolicy struct {
+	Enabled          bool     `json:"enabled"`
+	AllowOrigins     []string `json:"allowOrigins,omitempty"`     // Default ["*"]
+	AllowMethods     []string `json:"allowMethods,omitempty"`     // Default GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`     // Default: the headers the preflight asks for
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`    // Response headers readable by the browser, e.g. ["X-Request-Id"]
+	AllowCredentials bool     `json:"allowCredentials,omitempty"` // Send Access-Control-Allow-Credentials: true
+	MaxAgeSeconds    int      `json:"maxAgeSeconds,omitempty"`    // How long browsers may cache a preflight (0-86400, 0 = not sent)
+}
+
+// Delay distributions supported by DelayRange
+const (
+	DelayDistributionUniform = "uniform" // Every delay in the range is equally likely
+	DelayDistributionNormal  = "normal"  // Centered on the middle of the range, ±3 standard deviations wide
+)
+
+// DelayRange samples a delay between MinMs and MaxMs (inclusive) for every request
+type DelayRange struct {
+	MinMs        int    `json:"minMs"`
+	MaxMs        int    `json:"maxMs"`                  // 0-120000, at least MinMs
+	Distribution string `json:"distribution,omitempty"` // "uniform" (default) or "normal"
+}
+
+// LatencyProfile samples delays following latency percentiles, e.g. p50 20ms, p95 200ms, p99
+// 1s. Delays are interpolated linearly between MinMs, the given percentiles and MaxMs; omitted
+// percentiles are skipped.
+type LatencyProfile struct {
+	MinMs int `json:"minMs,omitempty"` // Fastest delay (default 0)
+	P50Ms int `json:"p50Ms"`
+	P90Ms int `json:"p90Ms,omitempty"`
+	P95Ms int `json:"p95Ms,omitempty"`
+	P99Ms int `json:"p99Ms,omitempty"`
+	MaxMs int `json:"maxMs,omitempty"` // Slowest delay (default the highest percentile given), at most 120000
+}
+
+// ProxyRetryConfig retries upstream requests that fail to connect, time out or answer 502/503/504.
+// Only idempotent methods (GET, HEAD, PUT, DELETE, OPTIONS, TRACE) are retried unless RetryNonIdempotent is set.
+type ProxyRetryConfig struct {
+	MaxAttempts        int  `json:"maxAttempts"`                  // Total attempts including the first (1-10)
+	BackoffMs          int  `json:"backoffMs,omitempty"`          // Wait before the first retry, doubled for each further one (default 100)
+	RetryNonIdempotent bool `json:"retryNonIdempotent,omitempty"` // Also retry POST, PATCH and other non-idempotent methods
+}
+
+// CircuitBreaker stops forwarding to a proxy target after FailureThreshold consecutive failed
+// answers (any 5xx, including connection failures and timeouts). While open, requests get
+// FallbackResponse without reaching the upstream; after CooldownMs a single trial request is
+// forwarded (half-open) and its outcome closes the breaker or opens it for another cooldown.
+type CircuitBreaker struct {
+	FailureThreshold int             `json:"failureThreshold"`           // Consecutive failures that open the breaker (1-1000)
+	CooldownMs       int             `json:"cooldownMs"`                 // Time open before a trial request (1-3600000)
+	FallbackResponse *CustomResponse `json:"fallbackResponse,omitempty"` // Sent while open; defaults to a 503 JSON error
+}
+
+// ProxyRoute sends matching requests to one of the project's proxy targets instead of the active one.
+// Every condition that is set must match: Header (with Value, or just present) and PathPrefix.
+type ProxyRoute struct {
+	Header     string `json:"header,omitempty"`     // e.g. "X-Tenant"
+	Value      string `json:"value,omitempty"`      // Expected header value; empty only requires the header
+	PathPrefix string `json:"pathPrefix,omitempty"` // e.g. "/v2"
+	TargetID   string `json:"targetId"`             // ID of a proxy target of the project
+}
+
+// TokenBucketConfig configures a token bucket: Burst tokens at most, refilled at Rate tokens per second
+type TokenBucketConfig struct {
+	Rate        float64 `json:"rate"`
+	Burst       int     `json:"burst"`
+	PerClientIP bool    `json:"perClientIp,omitempty"` // One bucket per client IP instead of one for the project or endpoint
+}
+
+// RateLimitWindow allows Requests per fixed window of WindowMs milliseconds
+type RateLimitWindow struct {
+	Requests    int  `json:"requests"`
+	WindowMs    int  `json:"windowMs"`              // 1-86400000 (one day)
+	PerClientIP bool `json:"perClientIp,omitempty"` // One window per client IP instead of one for the project or endpoint
+}
+
+// CustomResponse is a fixed response configured through advance config (e.g. when a limit is exceeded).
+// A zero StatusCode keeps the default status of the situation it replaces.
+type CustomResponse struct {
+	StatusCode int               `json:"statusCode,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+// HeaderFilter selects which headers pass through. When Allow is set only those headers are kept,
+// then Deny removes the listed ones. Header names are case-insensitive.
+type HeaderFilter struct {
+	Allow []string `json:"allow,omitempty"` // e.g. ["Content-Type", "Cache-Control"]
+	Deny  []string `json:"deny,omitempty"`  // e.g. ["Server", "X-Powered-By"]
+}
+
+// HeaderRewrite edits headers: Remove deletes the listed headers, Replace rewrites text within
+// values, then Set adds or overrides values. Header names are case-insensitive.
+type HeaderRewrite struct {
+	Set     map[string]string `json:"set,omitempty"`     // e.g. {"Authorization": "Bearer <token>"}
+	Remove  []string          `json:"remove,omitempty"`  // e.g. ["X-Client-Debug"]
+	Replace []HeaderReplace   `json:"replace,omitempty"` // e.g. upstream host to mock host in Location
+}
+
+// HeaderReplace replaces every occurrence of Find with Replace in each value of Header,
+// e.g. {"header": "Set-Cookie", "find": "; Domain=api.example.com", "replace": ""}
+type HeaderReplace struct {
+	Header  string `json:"header"`
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
 }
 
 // AdvanceConfigEndpoint defines advance configuration structure for endpoints
 type AdvanceConfigEndpoint struct {
-	DelayMs int `json:"delayMs,omitempty"` // Response delay in milliseconds (0-120000)
+	DelayMs               int                `json:"delayMs,omitempty"`               // Response delay in milliseconds (0-120000)
+	DelayRange            *DelayRange        `json:"delayRange,omitempty"`            // Random delay per request, used instead of delayMs when set
+	LatencyProfile        *LatencyProfile    `json:"latencyProfile,omitempty"`        // Percentile-shaped delay per request, used instead of delayMs when set
+	BodyTransforms        []BodyTransform    `json:"bodyTransforms,omitempty"`        // Request body pre-processing applied before rule matching
+	LimitExceededResponse *CustomResponse    `json:"limitExceededResponse,omitempty"` // Overrides the project limit-exceeded response for this endpoint
+	FailureInjection      *FailureInjection  `json:"failureInjection,omitempty"`      // Chaos testing: fail a share of requests before a response is selected
+	SequenceExhausted     string             `json:"sequenceExhausted,omitempty"`     // Sequence mode after the last response: "repeat_last" (default) or "gone" (410)
+	ProxyTimeoutMs        int                `json:"proxyTimeoutMs,omitempty"`        // Overrides the project upstream timeout when this endpoint proxies
+	ProxyRequestHeaders   *HeaderRewrite     `json:"proxyRequestHeaders,omitempty"`   // Applied after the project's proxyRequestHeaders when this endpoint proxies
+	TokenBucket           *TokenBucketConfig `json:"tokenBucket,omitempty"`           // Request quota of this endpoint, checked after the project's
+	RateLimit             *RateLimitWindow   `json:"rateLimit,omitempty"`             // Requests per fixed window for this endpoint, checked after the project's
+	WebSocket             *WebSocketConfig   `json:"webSocket,omitempty"`             // Accept WebSocket upgrades on this GET endpoint and echo or play scripted messages
+}
+
+// WebSocket modes supported by WebSocketConfig
+const (
+	WebSocketModeEcho   = "echo"   // Send the messages, then echo every client message until the client closes
+	WebSocketModeScript = "script" // Send the messages, then close the connection
+)
+
+// WebSocketConfig turns an endpoint into a WebSocket server. Client pings are answered with
+// pongs and a client close is answered with the same close code.
+type WebSocketConfig struct {
+	Mode           string             `json:"mode,omitempty"`           // "echo" (default) or "script"
+	Messages       []WebSocketMessage `json:"messages,omitempty"`       // Sent in order once the connection is open
+	CloseCode      int                `json:"closeCode,omitempty"`      // Close code sent when a script ends (1000-4999, default 1000)
+	CloseReason    string             `json:"closeReason,omitempty"`    // Close reason sent with closeCode, e.g. "done"
+	PingIntervalMs int                `json:"pingIntervalMs,omitempty"` // Send a ping this often while the connection is open (0 = never)
+}
+
+// WebSocketMessage is one scripted server message
+type WebSocketMessage struct {
+	Type    string `json:"type,omitempty"`    // "text" (default) or "binary" with base64 data
+	Data    string `json:"data"`              // e.g. {"event": "ready"}
+	DelayMs int    `json:"delayMs,omitempty"` // Wait before sending this message (0-120000)
+}
+
+// Failure kinds supported by FailureInjection
+const (
+	FailureKindStatus = "status" // Answer with an error response
+	FailureKindAbort  = "abort"  // Close the connection without sending a response
+)
+
+// FailureInjection fails a random share of an endpoint's requests, e.g. {"rate": 0.1} answers
+// 10% of requests with a 500, {"rate": 0.05, "kind": "abort"} drops 5% of connections
+type FailureInjection struct {
+	Rate     float64         `json:"rate"`               // Probability of failing a request (0-1)
+	Kind     string          `json:"kind,omitempty"`     // "status" (default) or "abort"
+	Response *CustomResponse `json:"response,omitempty"` // Failure response for "status"; defaults to a 500 JSON error
+}
+
+// Sequence mode policies once every response has been served
+const (
+	SequenceExhaustedRepeatLast = "repeat_last"
+	SequenceExhaustedGone       = "gone"
+)
+
+// AdvanceConfigResponse defines advance configuration structure for responses
+type AdvanceConfigResponse struct {
+	DelayBodyOnly       bool              `json:"delayBodyOnly,omitempty"`       // Send status and headers immediately, apply the delay before the body only
+	DelayRange          *DelayRange       `json:"delayRange,omitempty"`          // Random delay per request, used instead of the response delay_ms when set
+	Weight              string            `json:"weight,omitempty"`              // Weight for "weighted" endpoints: a number or request expression, e.g. "query.debug ? 10 : 1"
+	Environments        []string          `json:"environments,omitempty"`        // Environments this response is served in (X-Env header or MOCK_ENVIRONMENT); empty = all
+	Scenarios           []string          `json:"scenarios,omitempty"`           // Scenarios this response belongs to, chosen per request with the beo-echo-scenario header
+	BodySize            *BodySizeRange    `json:"bodySize,omitempty"`            // Request body size range (bytes) this response applies to
+	BodySchema          json.RawMessage   `json:"bodySchema,omitempty"`          // JSON Schema; a conforming body is generated on every request instead of Body
+	DateOffsetSeconds   int               `json:"dateOffsetSeconds,omitempty"`   // Shift of the Date header from the real time, overrides the project offset
+	JSONFormat          string            `json:"jsonFormat,omitempty"`          // "pretty" or "minify" a JSON body before sending; non-JSON bodies are unchanged
+	TimeoutAfterMs      int               `json:"timeoutAfterMs,omitempty"`      // Hang for this long, then answer 504 Gateway Timeout (0-120000)
+	TruncateBodyAt      int               `json:"truncateBodyAt,omitempty"`      // Fault injection: cut the body after this many bytes (0 = full body)
+	CompressionFault    string            `json:"compressionFault,omitempty"`    // Fault injection: "skip" declares the encoding without compressing, "corrupt" sends invalid compressed data
+	InjectSyntaxError   bool              `json:"injectSyntaxError,omitempty"`   // Fault injection: break the JSON body with a stray comma
+	FixtureSet          map[string]string `json:"fixtureSet,omitempty"`          // Fixture store writes when served; values may use {{request.body}} or {{request.body.<path>}}
+	FixtureDelete       []string          `json:"fixtureDelete,omitempty"`       // Fixture store keys removed when served
+	Templated           bool              `json:"templated,omitempty"`           // Render body and redirect URL with Go text/template over request data ({{.Path.id}}, {{.Query.q}}, {{.Body.user.name}})
+	TemplatePlaceholder string            `json:"templatePlaceholder,omitempty"` // Output for unresolved template variables (default empty)
+	SSE                 *SSEStream        `json:"sse,omitempty"`                 // Stream these Server-Sent Events as text/event-stream instead of the body
+	GRPCStream          *GRPCStream       `json:"grpcStream,omitempty"`          // Answer server-streaming gRPC calls with these messages instead of the body
+	Schedule            *ResponseSchedule `json:"schedule,omitempty"`            // When the response can be selected, e.g. a maintenance window
+}
+
+// ResponseSchedule limits when a response can be selected. Outside of it the response is never
+// chosen; inside it the response takes precedence over unscheduled ones. Start and End are
+// absolute; windows repeat daily in Timezone.
+type ResponseSchedule struct {
+	Start    string       `json:"start,omitempty"`    // RFC3339 time the response becomes selectable, e.g. 2025-01-01T00:00:00Z
+	End      string       `json:"end,omitempty"`      // RFC3339 time it stops being selectable (exclusive)
+	Windows  []TimeWindow `json:"windows,omitempty"`  // Daily windows; the response is selectable inside any of them, always when empty
+	Timezone string       `json:"timezone,omitempty"` // IANA zone windows are read in, e.g. Asia/Jakarta (default UTC)
+}
+
+// TimeWindow is a daily time range. A To earlier than From wraps past midnight, so 22:00-02:00
+// covers the late evening and the early morning after it.
+type TimeWindow struct {
+	From string   `json:"from"`           // HH:MM, inclusive
+	To   string   `json:"to"`             // HH:MM, exclusive
+	Days []string `json:"days,omitempty"` // Weekdays the window starts on (mon..sun); empty = every day
+}
+
+// SSEStream is a scripted Server-Sent Events stream. Events are sent in order, each flushed to
+// the client after its delay; the stream ends after the last event or when the client leaves.
+type SSEStream struct {
+	Events  []SSEEvent `json:"events"`
+	RetryMs int        `json:"retryMs,omitempty"` // Reconnection time sent to the client before the first event (0 = not sent)
+}
+
+// SSEEvent is one event of an SSEStream. Multi-line data is sent as several data: lines.
+type SSEEvent struct {
+	Event   string `json:"event,omitempty"`   // Event type, e.g. "progress"; empty is the default "message"
+	Data    string `json:"data"`              // e.g. {"progress": 50}
+	ID      string `json:"id,omitempty"`      // Last-Event-ID the client resumes from
+	DelayMs int    `json:"delayMs,omitempty"` // Wait before sending this event (0-120000)
+}
+
+// GRPCStream is a scripted reply to a server-streaming gRPC call. Messages are encoded with the
+// method's output type and sent in order, each after its delay; the stream then closes with
+// Status and Message in the grpc-status and grpc-message trailers.
+type GRPCStream struct {
+	Messages []GRPCStreamMessage `json:"messages"`
+	Status   int                 `json:"status,omitempty"`  // Closing gRPC status code (0-16), e.g. 14 for UNAVAILABLE; default 0 (OK)
+	Message  string              `json:"message,omitempty"` // Closing grpc-message, e.g. "server restarting"
+}
+
+// GRPCStreamMessage is one message of a GRPCStream
+type GRPCStreamMessage struct {
+	Body    json.RawMessage `json:"body"`              // JSON of the output message, e.g. {"message": "tick", "count": 1}
+	DelayMs int             `json:"delayMs,omitempty"` // Wait before sending this message (0-120000)
+}
+
+// BodySizeRange is an inclusive request body size range in bytes; a zero Max means no upper bound
+type BodySizeRange struct {
+	Min int64 `json:"min,omitempty"`
+	Max int64 `json:"max,omitempty"`
+}
+
+// Body transform types supported by BodyTransform
+const (
+	BodyTransformBase64Decode = "base64_decode" // Decode a base64 (standard or URL-safe) body
+	BodyTransformURLDecode    = "url_decode"    // Decode a percent/URL-encoded body
+	BodyTransformUnwrap       = "unwrap"        // Replace the body with a JSON field (dot notation in Field)
+)
+
+// BodyTransform is one step of the request body pre-processing pipeline, applied in order
+type BodyTransform struct {
+	Type  string `json:"type"`            // base64_decode, url_decode, unwrap
+	Field string `json:"field,omitempty"` // Envelope field for unwrap, e.g. "data.payload"
 }
 
 // Validate validates the project advance configuration
@@ -23,6 +339,360 @@ func (a *AdvanceConfigProject) Validate() error {
 	if a.DelayMs > 120000 {
 		return errors.New("delayMs cannot exceed 120000ms (2 minutes)")
 	}
+	if err := a.DelayRange.Validate(); err != nil {
+		return err
+	}
+	if a.MaxInFlight < 0 {
+		return errors.New("maxInFlight cannot be negative")
+	}
+	if err := a.TokenBucket.Validate(); err != nil {
+		return err
+	}
+	if err := a.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if err := a.LimitExceededResponse.Validate(); err != nil {
+		return errors.New("limitExceededResponse: " + err.Error())
+	}
+	if err := a.LoopDetectedResponse.Validate(); err != nil {
+		return errors.New("loopDetectedResponse: " + err.Error())
+	}
+	if err := a.InvalidModeResponse.Validate(); err != nil {
+		return errors.New("invalidModeResponse: " + err.Error())
+	}
+	if err := a.CancelledResponse.Validate(); err != nil {
+		return errors.New("cancelledResponse: " + err.Error())
+	}
+	if err := a.NotFoundResponse.Validate(); err != nil {
+		return errors.New("notFoundResponse: " + err.Error())
+	}
+	for _, route := range a.ProxyRoutes {
+		if route.TargetID == "" {
+			return errors.New("proxyRoutes entries require a targetId")
+		}
+		if route.Header == "" && route.PathPrefix == "" {
+			return errors.New("proxyRoutes entries require a header or pathPrefix condition")
+		}
+		if route.Value != "" && route.Header == "" {
+			return errors.New("proxyRoutes value requires a header")
+		}
+	}
+	if err := ValidateProxyHostHeader(a.ProxyHostHeader); err != nil {
+		return errors.New("proxyHostHeader " + err.Error())
+	}
+	if err := validateProxyTimeout(a.ProxyTimeoutMs); err != nil {
+		return err
+	}
+	if err := ValidateProxyHTTPVersion(a.ProxyHTTPVersion); err != nil {
+		return errors.New("proxyHttpVersion " + err.Error())
+	}
+	if err := a.ProxyRequestHeaders.Validate(); err != nil {
+		return errors.New("proxyRequestHeaders: " + err.Error())
+	}
+	if err := a.ProxyResponseRewrite.Validate(); err != nil {
+		return errors.New("proxyResponseRewrite: " + err.Error())
+	}
+	if err := a.CORS.Validate(); err != nil {
+		return errors.New("cors: " + err.Error())
+	}
+	if err := a.Session.Validate(); err != nil {
+		return errors.New("session: " + err.Error())
+	}
+	if err := a.GRPC.Validate(); err != nil {
+		return errors.New("grpc: " + err.Error())
+	}
+	if err := a.ProxyCircuitBreaker.Validate(); err != nil {
+		return errors.New("proxyCircuitBreaker: " + err.Error())
+	}
+	if err := a.ProxyFallback.Validate(); err != nil {
+		return errors.New("proxyFallback: " + err.Error())
+	}
+	if a.ProxyRetry != nil {
+		if a.ProxyRetry.MaxAttempts < 1 || a.ProxyRetry.MaxAttempts > 10 {
+			return errors.New("proxyRetry maxAttempts must be between 1 and 10")
+		}
+		if a.ProxyRetry.BackoffMs < 0 || a.ProxyRetry.BackoffMs > 60000 {
+			return errors.New("proxyRetry backoffMs must be between 0 and 60000")
+		}
+	}
+	return nil
+}
+
+// Proxy Host header modes; any other non-empty value is sent as the Host
+const (
+	ProxyHostTarget   = "target"
+	ProxyHostOriginal = "original"
+)
+
+// Upstream HTTP versions for proxyHttpVersion; empty means ProxyHTTP1
+const (
+	ProxyHTTP1    = "http1" // HTTP/1.1 only
+	ProxyHTTPAuto = "auto"  // HTTP/2 when the TLS handshake negotiates it, HTTP/1.1 otherwise
+	ProxyHTTP2    = "http2" // HTTP/2 only, with prior knowledge (h2c) on http targets
+)
+
+// ValidateProxyHTTPVersion checks a proxyHttpVersion setting
+func ValidateProxyHTTPVersion(value string) error {
+	switch value {
+	case "", ProxyHTTP1, ProxyHTTPAuto, ProxyHTTP2:
+		return nil
+	}
+	return errors.New("must be http1, auto or http2")
+}
+
+// ValidateProxyHostHeader checks a Host header setting: empty, a mode, or a literal host[:port]
+func ValidateProxyHostHeader(value string) error {
+	if strings.ContainsAny(value, " \t/") {
+		return errors.New("must be target, original or a host[:port] without spaces or slashes")
+	}
+	return nil
+}
+
+// Validate validates a header rewrite, nil means not configured
+func (h *HeaderRewrite) Validate() error {
+	if h == nil {
+		return nil
+	}
+	for name := range h.Set {
+		if !validHeaderName(name) {
+			return errors.New("invalid header name in set: " + name)
+		}
+	}
+	for _, name := range h.Remove {
+		if !validHeaderName(name) {
+			return errors.New("invalid header name in remove: " + name)
+		}
+	}
+	for _, replace := range h.Replace {
+		if !validHeaderName(replace.Header) {
+			return errors.New("invalid header name in replace: " + replace.Header)
+		}
+		if replace.Find == "" {
+			return errors.New("replace entries require a find value")
+		}
+	}
+	return nil
+}
+
+// validHeaderName reports whether name is a non-empty header field name
+func validHeaderName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t\r\n:")
+}
+
+// Validate validates a CORS config, nil means not configured
+func (c *CORSPolicy) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxAgeSeconds < 0 || c.MaxAgeSeconds > 86400 {
+		return errors.New("maxAgeSeconds must be between 0 and 86400")
+	}
+	for _, origin := range c.AllowOrigins {
+		if origin == "" || strings.ContainsAny(origin, " \t,") {
+			return errors.New("invalid origin in allowOrigins: " + origin)
+		}
+	}
+	for _, method := range c.AllowMethods {
+		if !validHeaderName(method) {
+			return errors.New("invalid method in allowMethods: " + method)
+		}
+	}
+	for _, name := range append(append([]string{}, c.AllowHeaders...), c.ExposeHeaders...) {
+		if !validHeaderName(name) {
+			return errors.New("invalid header name: " + name)
+		}
+	}
+	return nil
+}
+
+// Validate validates a session config, nil means not configured
+func (c *SessionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Header == "" && c.Cookie == "" {
+		return errors.New("header or cookie is required")
+	}
+	if c.Header != "" && !validHeaderName(c.Header) {
+		return errors.New("invalid header name: " + c.Header)
+	}
+	if c.TTLSeconds < 0 || c.TTLSeconds > 604800 {
+		return errors.New("ttlSeconds must be between 0 and 604800")
+	}
+	return nil
+}
+
+// Validate validates a gRPC config, nil means not configured. The descriptor set itself is
+// parsed when the first call arrives.
+func (c *GRPCConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.DescriptorSet == "" {
+		return errors.New("descriptorSet is required")
+	}
+	if _, err := base64.StdEncoding.DecodeString(c.DescriptorSet); err != nil {
+		return errors.New("descriptorSet must be base64: " + err.Error())
+	}
+	return nil
+}
+
+// Validate validates a circuit breaker, nil means not configured
+func (b *CircuitBreaker) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.FailureThreshold < 1 || b.FailureThreshold > 1000 {
+		return errors.New("failureThreshold must be between 1 and 1000")
+	}
+	if b.CooldownMs < 1 || b.CooldownMs > 3600000 {
+		return errors.New("cooldownMs must be between 1 and 3600000")
+	}
+	return b.FallbackResponse.Validate()
+}
+
+// Validate validates a proxy fallback, nil means not configured
+func (f *ProxyFallback) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if len(f.StatusCodes) == 0 {
+		return errors.New("statusCodes is required")
+	}
+	for _, code := range f.StatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("status code %d must be between 100 and 599", code)
+		}
+	}
+	if f.EndpointID == "" && f.Response == nil {
+		return errors.New("endpointId or response is required")
+	}
+	return f.Response.Validate()
+}
+
+// Validate validates a WebSocket config, nil means not configured
+func (w *WebSocketConfig) Validate() error {
+	if w == nil {
+		return nil
+	}
+	switch w.Mode {
+	case "", WebSocketModeEcho, WebSocketModeScript:
+	default:
+		return errors.New("mode must be echo or script")
+	}
+	// 1004-1006 and 1015 are reserved and never sent in a close frame
+	if w.CloseCode != 0 && (w.CloseCode < 1000 || w.CloseCode > 4999 || (w.CloseCode >= 1004 && w.CloseCode <= 1006) || w.CloseCode == 1015) {
+		return errors.New("closeCode must be a sendable close code between 1000 and 4999")
+	}
+	if len(w.CloseReason) > 123 {
+		return errors.New("closeReason cannot exceed 123 bytes")
+	}
+	if w.PingIntervalMs < 0 {
+		return errors.New("pingIntervalMs cannot be negative")
+	}
+	for i, message := range w.Messages {
+		switch message.Type {
+		case "", "text":
+		case "binary":
+			if _, err := base64.StdEncoding.DecodeString(message.Data); err != nil {
+				return fmt.Errorf("messages[%d]: binary data must be base64", i)
+			}
+		default:
+			return fmt.Errorf("messages[%d]: type must be text or binary", i)
+		}
+		if message.DelayMs < 0 || message.DelayMs > 120000 {
+			return fmt.Errorf("messages[%d]: delayMs must be between 0 and 120000", i)
+		}
+	}
+	return nil
+}
+
+// Validate validates a failure injection, nil means not configured
+func (f *FailureInjection) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if f.Rate < 0 || f.Rate > 1 {
+		return errors.New("rate must be between 0 and 1")
+	}
+	switch f.Kind {
+	case "", FailureKindStatus, FailureKindAbort:
+	default:
+		return errors.New("kind must be status or abort")
+	}
+	return f.Response.Validate()
+}
+
+// Validate validates a delay range, nil means not configured
+func (d *DelayRange) Validate() error {
+	if d == nil {
+		return nil
+	}
+	if d.MinMs < 0 || d.MaxMs > 120000 || d.MinMs > d.MaxMs {
+		return errors.New("delayRange requires 0 <= minMs <= maxMs <= 120000")
+	}
+	switch d.Distribution {
+	case "", DelayDistributionUniform, DelayDistributionNormal:
+	default:
+		return errors.New("delayRange distribution must be uniform or normal")
+	}
+	return nil
+}
+
+// Validate validates a latency profile, nil means not configured
+func (p *LatencyProfile) Validate() error {
+	if p == nil {
+		return nil
+	}
+	previous := 0
+	for _, point := range []struct {
+		name string
+		ms   int
+	}{{"minMs", p.MinMs}, {"p50Ms", p.P50Ms}, {"p90Ms", p.P90Ms}, {"p95Ms", p.P95Ms}, {"p99Ms", p.P99Ms}, {"maxMs", p.MaxMs}} {
+		if point.ms < 0 || point.ms > 120000 {
+			return fmt.Errorf("%s must be between 0 and 120000", point.name)
+		}
+		if point.name != "minMs" && point.name != "p50Ms" && point.ms == 0 {
+			continue // Omitted
+		}
+		if point.ms < previous {
+			return fmt.Errorf("%s cannot be lower than the percentiles before it", point.name)
+		}
+		previous = point.ms
+	}
+	return nil
+}
+
+// Validate validates a token bucket, nil means not configured
+func (b *TokenBucketConfig) Validate() error {
+	if b != nil && (b.Rate < 0 || b.Burst < 1) {
+		return errors.New("tokenBucket requires a non-negative rate and a burst of at least 1")
+	}
+	return nil
+}
+
+// Validate validates a fixed window rate limit, nil means not configured
+func (w *RateLimitWindow) Validate() error {
+	if w == nil {
+		return nil
+	}
+	if w.Requests < 1 {
+		return errors.New("rateLimit requires at least 1 request per window")
+	}
+	if w.WindowMs < 1 || w.WindowMs > 86400000 {
+		return errors.New("rateLimit windowMs must be between 1 and 86400000")
+	}
+	return nil
+}
+
+// Validate validates a custom response, nil means not configured
+func (c *CustomResponse) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.StatusCode != 0 && (c.StatusCode < 100 || c.StatusCode > 599) {
+		return errors.New("statusCode must be between 100 and 599")
+	}
 	return nil
 }
 
@@ -34,6 +704,219 @@ func (a *AdvanceConfigEndpoint) Validate() error {
 	if a.DelayMs > 120000 {
 		return errors.New("delayMs cannot exceed 120000ms (2 minutes)")
 	}
+	if err := a.DelayRange.Validate(); err != nil {
+		return err
+	}
+	if err := a.LatencyProfile.Validate(); err != nil {
+		return errors.New("latencyProfile: " + err.Error())
+	}
+	if a.LatencyProfile != nil && a.DelayRange != nil {
+		return errors.New("latencyProfile and delayRange cannot both be set")
+	}
+	for _, transform := range a.BodyTransforms {
+		switch transform.Type {
+		case BodyTransformBase64Decode, BodyTransformURLDecode:
+		case BodyTransformUnwrap:
+			if transform.Field == "" {
+				return errors.New("bodyTransforms unwrap requires a field")
+			}
+		default:
+			return errors.New("bodyTransforms type must be one of: base64_decode, url_decode, unwrap")
+		}
+	}
+	if err := a.TokenBucket.Validate(); err != nil {
+		return err
+	}
+	if err := a.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if err := a.LimitExceededResponse.Validate(); err != nil {
+		return errors.New("limitExceededResponse: " + err.Error())
+	}
+	if err := a.FailureInjection.Validate(); err != nil {
+		return errors.New("failureInjection: " + err.Error())
+	}
+	if err := a.WebSocket.Validate(); err != nil {
+		return errors.New("webSocket: " + err.Error())
+	}
+	switch a.SequenceExhausted {
+	case "", SequenceExhaustedRepeatLast, SequenceExhaustedGone:
+	default:
+		return errors.New("sequenceExhausted must be repeat_last or gone")
+	}
+	if err := validateProxyTimeout(a.ProxyTimeoutMs); err != nil {
+		return err
+	}
+	if err := a.ProxyRequestHeaders.Validate(); err != nil {
+		return errors.New("proxyRequestHeaders: " + err.Error())
+	}
+	return nil
+}
+
+// validateProxyTimeout checks a proxyTimeoutMs value, 0 meaning the default
+func validateProxyTimeout(timeoutMs int) error {
+	if timeoutMs < 0 {
+		return errors.New("proxyTimeoutMs cannot be negative")
+	}
+	if timeoutMs > 600000 {
+		return errors.New("proxyTimeoutMs cannot exceed 600000ms (10 minutes)")
+	}
+	return nil
+}
+
+// Validate validates the response advance configuration
+func (a *AdvanceConfigResponse) Validate() error {
+	if a.TimeoutAfterMs < 0 {
+		return errors.New("timeoutAfterMs cannot be negative")
+	}
+	if a.TimeoutAfterMs > 120000 {
+		return errors.New("timeoutAfterMs cannot exceed 120000ms (2 minutes)")
+	}
+	if err := a.DelayRange.Validate(); err != nil {
+		return err
+	}
+	switch a.JSONFormat {
+	case "", "pretty", "minify":
+	default:
+		return errors.New("jsonFormat must be pretty or minify")
+	}
+	switch a.CompressionFault {
+	case "", "skip", "corrupt":
+	default:
+		return errors.New("compressionFault must be skip or corrupt")
+	}
+	if a.TruncateBodyAt < 0 {
+		return errors.New("truncateBodyAt cannot be negative")
+	}
+	for key := range a.FixtureSet {
+		if key == "" {
+			return errors.New("fixtureSet keys cannot be empty")
+		}
+	}
+	for _, key := range a.FixtureDelete {
+		if key == "" {
+			return errors.New("fixtureDelete keys cannot be empty")
+		}
+	}
+	if len(a.BodySchema) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(a.BodySchema, &schema); err != nil {
+			return errors.New("bodySchema must be a JSON Schema object")
+		}
+	}
+	if a.BodySize != nil && (a.BodySize.Min < 0 || a.BodySize.Max < 0 || (a.BodySize.Max > 0 && a.BodySize.Max < a.BodySize.Min)) {
+		return errors.New("bodySize requires non-negative bounds with max >= min")
+	}
+	if err := a.SSE.Validate(); err != nil {
+		return errors.New("sse: " + err.Error())
+	}
+	if err := a.GRPCStream.Validate(); err != nil {
+		return errors.New("grpcStream: " + err.Error())
+	}
+	if err := a.Schedule.Validate(); err != nil {
+		return errors.New("schedule: " + err.Error())
+	}
+	return nil
+}
+
+// Validate validates a response schedule, nil means not configured
+func (s *ResponseSchedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+	var start, end time.Time
+	var err error
+	if s.Start != "" {
+		if start, err = time.Parse(time.RFC3339, s.Start); err != nil {
+			return errors.New("start must be an RFC3339 time")
+		}
+	}
+	if s.End != "" {
+		if end, err = time.Parse(time.RFC3339, s.End); err != nil {
+			return errors.New("end must be an RFC3339 time")
+		}
+	}
+	if s.Start != "" && s.End != "" && !end.After(start) {
+		return errors.New("end must be after start")
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", s.Timezone)
+		}
+	}
+	for i, window := range s.Windows {
+		from, errFrom := time.Parse("15:04", window.From)
+		to, errTo := time.Parse("15:04", window.To)
+		if errFrom != nil || errTo != nil {
+			return fmt.Errorf("windows[%d]: from and to must be HH:MM times", i)
+		}
+		if from.Equal(to) {
+			return fmt.Errorf("windows[%d]: from and to cannot be equal", i)
+		}
+		for _, day := range window.Days {
+			if _, ok := ParseWeekday(day); !ok {
+				return fmt.Errorf("windows[%d]: unknown day %q, must be mon..sun", i, day)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseWeekday reads a weekday given by its name or three-letter abbreviation, case-insensitively
+func ParseWeekday(day string) (time.Weekday, bool) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if day == name || day == name[:3] {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// Validate validates an SSE stream, nil means not configured
+func (s *SSEStream) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if len(s.Events) == 0 {
+		return errors.New("events are required")
+	}
+	if s.RetryMs < 0 {
+		return errors.New("retryMs cannot be negative")
+	}
+	for i, event := range s.Events {
+		if event.DelayMs < 0 || event.DelayMs > 120000 {
+			return fmt.Errorf("events[%d]: delayMs must be between 0 and 120000", i)
+		}
+		if strings.ContainsAny(event.Event, "\r\n") || strings.ContainsAny(event.ID, "\r\n") {
+			return fmt.Errorf("events[%d]: event and id cannot contain line breaks", i)
+		}
+	}
+	return nil
+}
+
+// Validate validates a gRPC stream, nil means not configured. A stream without messages must
+// close with an error status.
+func (g *GRPCStream) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Status < 0 || g.Status > 16 {
+		return errors.New("status must be a gRPC status code between 0 and 16")
+	}
+	if len(g.Messages) == 0 && g.Status == 0 {
+		return errors.New("messages are required unless the stream closes with an error status")
+	}
+	for i, message := range g.Messages {
+		if message.DelayMs < 0 || message.DelayMs > 120000 {
+			return fmt.Errorf("messages[%d]: delayMs must be between 0 and 120000", i)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(message.Body, &body); err != nil {
+			return fmt.Errorf("messages[%d]: body must be a JSON object", i)
+		}
+	}
 	return nil
 }
 
@@ -73,28 +956,58 @@ func ParseEndpointAdvanceConfig(configJSON string) (*AdvanceConfigEndpoint, erro
 	return &config, nil
 }
 
-// ToJSON converts AdvanceConfigProject to JSON string
-func (a *AdvanceConfigProject) ToJSON() (string, error) {
-	if a.DelayMs == 0 {
-		return "", nil
+// ParseResponseAdvanceConfig parses JSON string to AdvanceConfigResponse struct
+func ParseResponseAdvanceConfig(configJSON string) (*AdvanceConfigResponse, error) {
+	if configJSON == "" {
+		return &AdvanceConfigResponse{}, nil
+	}
+
+	var config AdvanceConfigResponse
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return nil, errors.New("invalid JSON format in advance_config")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
+	return &config, nil
+}
+
+// ToJSON converts AdvanceConfigProject to JSON string
+func (a *AdvanceConfigProject) ToJSON() (string, error) {
 	data, err := json.Marshal(a)
 	if err != nil {
 		return "", err
 	}
+	// Every field is omitempty, so an empty object means nothing is configured
+	if string(data) == "{}" {
+		return "", nil
+	}
 	return string(data), nil
 }
 
 // ToJSON converts AdvanceConfigEndpoint to JSON string
 func (a *AdvanceConfigEndpoint) ToJSON() (string, error) {
-	if a.DelayMs == 0 {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	// Every field is omitempty, so an empty object means nothing is configured
+	if string(data) == "{}" {
 		return "", nil
 	}
+	return string(data), nil
+}
 
+// ToJSON converts AdvanceConfigResponse to JSON string
+func (a *AdvanceConfigResponse) ToJSON() (string, error) {
 	data, err := json.Marshal(a)
 	if err != nil {
 		return "", err
 	}
+	if string(data) == "{}" {
+		return "", nil
+	}
 	return string(data), nil
 }
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,6 +64,219 @@ func TestAdvanceConfigProject_Validate(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "delayMs cannot exceed 120000ms")
 	})
+
+	t.Run("Invalid failureInjection", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigEndpoint{FailureInjection: &FailureInjection{Rate: 0.5, Kind: "abort"}}).Validate())
+
+		err := (&AdvanceConfigEndpoint{FailureInjection: &FailureInjection{Rate: 1.5}}).Validate()
+		assert.ErrorContains(t, err, "failureInjection: rate")
+
+		err = (&AdvanceConfigEndpoint{FailureInjection: &FailureInjection{Rate: 0.1, Kind: "timeout"}}).Validate()
+		assert.ErrorContains(t, err, "kind must be status or abort")
+	})
+
+	t.Run("Invalid sse", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigResponse{SSE: &SSEStream{Events: []SSEEvent{{Data: "hi", DelayMs: 100}}}}).Validate())
+
+		err := (&AdvanceConfigResponse{SSE: &SSEStream{}}).Validate()
+		assert.ErrorContains(t, err, "sse: events are required")
+
+		err = (&AdvanceConfigResponse{SSE: &SSEStream{Events: []SSEEvent{{Data: "hi", DelayMs: 120001}}}}).Validate()
+		assert.ErrorContains(t, err, "events[0]: delayMs")
+
+		err = (&AdvanceConfigResponse{SSE: &SSEStream{Events: []SSEEvent{{Event: "a\nb"}}}}).Validate()
+		assert.ErrorContains(t, err, "line breaks")
+	})
+
+	t.Run("Invalid grpcStream", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigResponse{GRPCStream: &GRPCStream{Messages: []GRPCStreamMessage{{Body: json.RawMessage(`{"message": "hi"}`), DelayMs: 100}}}}).Validate())
+		assert.NoError(t, (&AdvanceConfigResponse{GRPCStream: &GRPCStream{Status: 14, Message: "unavailable"}}).Validate())
+
+		err := (&AdvanceConfigResponse{GRPCStream: &GRPCStream{}}).Validate()
+		assert.ErrorContains(t, err, "grpcStream: messages are required")
+
+		err = (&AdvanceConfigResponse{GRPCStream: &GRPCStream{Status: 17}}).Validate()
+		assert.ErrorContains(t, err, "status must be a gRPC status code")
+
+		err = (&AdvanceConfigResponse{GRPCStream: &GRPCStream{Messages: []GRPCStreamMessage{{Body: json.RawMessage(`{}`), DelayMs: 120001}}}}).Validate()
+		assert.ErrorContains(t, err, "messages[0]: delayMs")
+
+		err = (&AdvanceConfigResponse{GRPCStream: &GRPCStream{Messages: []GRPCStreamMessage{{Body: json.RawMessage(`"hi"`)}}}}).Validate()
+		assert.ErrorContains(t, err, "messages[0]: body must be a JSON object")
+	})
+
+	t.Run("Invalid schedule", func(t *testing.T) {
+		valid := &ResponseSchedule{Start: "2025-01-01T00:00:00Z", End: "2025-02-01T00:00:00+07:00", Timezone: "Asia/Jakarta", Windows: []TimeWindow{{From: "22:00", To: "02:00", Days: []string{"sat", "Sunday"}}}}
+		assert.NoError(t, (&AdvanceConfigResponse{Schedule: valid}).Validate())
+
+		err := (&AdvanceConfigResponse{Schedule: &ResponseSchedule{Start: "tomorrow"}}).Validate()
+		assert.ErrorContains(t, err, "schedule: start must be an RFC3339 time")
+
+		err = (&AdvanceConfigResponse{Schedule: &ResponseSchedule{Start: "2025-02-01T00:00:00Z", End: "2025-01-01T00:00:00Z"}}).Validate()
+		assert.ErrorContains(t, err, "end must be after start")
+
+		err = (&AdvanceConfigResponse{Schedule: &ResponseSchedule{Timezone: "Mars/Olympus"}}).Validate()
+		assert.ErrorContains(t, err, "unknown timezone")
+
+		err = (&AdvanceConfigResponse{Schedule: &ResponseSchedule{Windows: []TimeWindow{{From: "9:00", To: "25:00"}}}}).Validate()
+		assert.ErrorContains(t, err, "windows[0]: from and to must be HH:MM times")
+
+		err = (&AdvanceConfigResponse{Schedule: &ResponseSchedule{Windows: []TimeWindow{{From: "09:00", To: "17:00", Days: []string{"someday"}}}}}).Validate()
+		assert.ErrorContains(t, err, "unknown day")
+	})
+
+	t.Run("Invalid webSocket", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigEndpoint{WebSocket: &WebSocketConfig{Mode: "script", CloseCode: 4001, Messages: []WebSocketMessage{{Type: "binary", Data: "AQI="}}}}).Validate())
+
+		err := (&AdvanceConfigEndpoint{WebSocket: &WebSocketConfig{Mode: "replay"}}).Validate()
+		assert.ErrorContains(t, err, "webSocket: mode")
+
+		err = (&AdvanceConfigEndpoint{WebSocket: &WebSocketConfig{CloseCode: 1006}}).Validate()
+		assert.ErrorContains(t, err, "closeCode")
+
+		err = (&AdvanceConfigEndpoint{WebSocket: &WebSocketConfig{Messages: []WebSocketMessage{{Type: "binary", Data: "%%"}}}}).Validate()
+		assert.ErrorContains(t, err, "messages[0]: binary data must be base64")
+	})
+
+	t.Run("Invalid delayRange", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigResponse{DelayRange: &DelayRange{MinMs: 10, MaxMs: 10, Distribution: "normal"}}).Validate())
+
+		err := (&AdvanceConfigProject{DelayRange: &DelayRange{MinMs: 50, MaxMs: 10}}).Validate()
+		assert.ErrorContains(t, err, "minMs <= maxMs")
+
+		err = (&AdvanceConfigEndpoint{DelayRange: &DelayRange{MaxMs: 10, Distribution: "poisson"}}).Validate()
+		assert.ErrorContains(t, err, "uniform or normal")
+	})
+
+	t.Run("Invalid latencyProfile", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigEndpoint{LatencyProfile: &LatencyProfile{MinMs: 5, P50Ms: 20, P95Ms: 200, P99Ms: 1000}}).Validate())
+
+		err := (&AdvanceConfigEndpoint{LatencyProfile: &LatencyProfile{P50Ms: 200, P95Ms: 100}}).Validate()
+		assert.ErrorContains(t, err, "latencyProfile: p95Ms cannot be lower")
+
+		err = (&AdvanceConfigEndpoint{LatencyProfile: &LatencyProfile{P50Ms: 20, MaxMs: 120001}}).Validate()
+		assert.ErrorContains(t, err, "maxMs must be between 0 and 120000")
+
+		err = (&AdvanceConfigEndpoint{LatencyProfile: &LatencyProfile{P50Ms: 20}, DelayRange: &DelayRange{MaxMs: 10}}).Validate()
+		assert.ErrorContains(t, err, "cannot both be set")
+	})
+
+	t.Run("Invalid rateLimit", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigEndpoint{RateLimit: &RateLimitWindow{Requests: 10, WindowMs: 1000, PerClientIP: true}}).Validate())
+
+		err := (&AdvanceConfigProject{RateLimit: &RateLimitWindow{Requests: 0, WindowMs: 1000}}).Validate()
+		assert.ErrorContains(t, err, "at least 1 request")
+
+		err = (&AdvanceConfigEndpoint{RateLimit: &RateLimitWindow{Requests: 1}}).Validate()
+		assert.ErrorContains(t, err, "windowMs")
+
+		err = (&AdvanceConfigEndpoint{TokenBucket: &TokenBucketConfig{Rate: 1}}).Validate()
+		assert.ErrorContains(t, err, "burst of at least 1")
+	})
+
+	t.Run("Invalid cors", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{CORS: &CORSPolicy{Enabled: true, AllowOrigins: []string{"https://*.example.com"}, MaxAgeSeconds: 600}}).Validate())
+
+		err := (&AdvanceConfigProject{CORS: &CORSPolicy{MaxAgeSeconds: 86401}}).Validate()
+		assert.ErrorContains(t, err, "cors: maxAgeSeconds")
+
+		err = (&AdvanceConfigProject{CORS: &CORSPolicy{AllowOrigins: []string{"https://a.com, https://b.com"}}}).Validate()
+		assert.ErrorContains(t, err, "invalid origin")
+	})
+
+	t.Run("Invalid session", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{Session: &SessionConfig{Cookie: "session_id", TTLSeconds: 60}}).Validate())
+
+		err := (&AdvanceConfigProject{Session: &SessionConfig{TTLSeconds: 60}}).Validate()
+		assert.ErrorContains(t, err, "session: header or cookie is required")
+
+		err = (&AdvanceConfigProject{Session: &SessionConfig{Header: "X-Session-Id", TTLSeconds: -1}}).Validate()
+		assert.ErrorContains(t, err, "ttlSeconds")
+	})
+
+	t.Run("Invalid grpc", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{GRPC: &GRPCConfig{DescriptorSet: "CgA="}}).Validate())
+
+		err := (&AdvanceConfigProject{GRPC: &GRPCConfig{}}).Validate()
+		assert.ErrorContains(t, err, "grpc: descriptorSet is required")
+
+		err = (&AdvanceConfigProject{GRPC: &GRPCConfig{DescriptorSet: "not base64!"}}).Validate()
+		assert.ErrorContains(t, err, "descriptorSet must be base64")
+	})
+
+	t.Run("Invalid proxyFallback", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{ProxyFallback: &ProxyFallback{StatusCodes: []int{500, 503}, EndpointID: "ep-1"}}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyFallback: &ProxyFallback{EndpointID: "ep-1"}}).Validate()
+		assert.ErrorContains(t, err, "proxyFallback: statusCodes is required")
+
+		err = (&AdvanceConfigProject{ProxyFallback: &ProxyFallback{StatusCodes: []int{700}, EndpointID: "ep-1"}}).Validate()
+		assert.ErrorContains(t, err, "status code 700 must be between 100 and 599")
+
+		err = (&AdvanceConfigProject{ProxyFallback: &ProxyFallback{StatusCodes: []int{500}}}).Validate()
+		assert.ErrorContains(t, err, "endpointId or response is required")
+	})
+
+	t.Run("Invalid proxyCircuitBreaker", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{ProxyCircuitBreaker: &CircuitBreaker{FailureThreshold: 3, CooldownMs: 30000}}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyCircuitBreaker: &CircuitBreaker{CooldownMs: 30000}}).Validate()
+		assert.ErrorContains(t, err, "proxyCircuitBreaker: failureThreshold must be between 1 and 1000")
+
+		err = (&AdvanceConfigProject{ProxyCircuitBreaker: &CircuitBreaker{FailureThreshold: 3}}).Validate()
+		assert.ErrorContains(t, err, "cooldownMs must be between 1 and 3600000")
+
+		err = (&AdvanceConfigProject{ProxyCircuitBreaker: &CircuitBreaker{FailureThreshold: 3, CooldownMs: 1, FallbackResponse: &CustomResponse{StatusCode: 42}}}).Validate()
+		assert.ErrorContains(t, err, "statusCode must be between 100 and 599")
+	})
+
+	t.Run("Invalid proxyTimeoutMs", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{ProxyTimeoutMs: 600000}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyTimeoutMs: -1}).Validate()
+		assert.ErrorContains(t, err, "proxyTimeoutMs cannot be negative")
+
+		err = (&AdvanceConfigEndpoint{ProxyTimeoutMs: 600001}).Validate()
+		assert.ErrorContains(t, err, "proxyTimeoutMs cannot exceed")
+	})
+
+	t.Run("Invalid proxyHttpVersion", func(t *testing.T) {
+		for _, version := range []string{"", ProxyHTTP1, ProxyHTTPAuto, ProxyHTTP2} {
+			assert.NoError(t, (&AdvanceConfigProject{ProxyHTTPVersion: version}).Validate())
+		}
+
+		err := (&AdvanceConfigProject{ProxyHTTPVersion: "h3"}).Validate()
+		assert.ErrorContains(t, err, "proxyHttpVersion must be http1, auto or http2")
+	})
+
+	t.Run("Invalid proxyRetry", func(t *testing.T) {
+		assert.NoError(t, (&AdvanceConfigProject{ProxyRetry: &ProxyRetryConfig{MaxAttempts: 3, BackoffMs: 200}}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyRetry: &ProxyRetryConfig{MaxAttempts: 0}}).Validate()
+		assert.ErrorContains(t, err, "maxAttempts must be between 1 and 10")
+
+		err = (&AdvanceConfigProject{ProxyRetry: &ProxyRetryConfig{MaxAttempts: 2, BackoffMs: -1}}).Validate()
+		assert.ErrorContains(t, err, "backoffMs must be between 0 and 60000")
+	})
+
+	t.Run("Invalid proxyRequestHeaders", func(t *testing.T) {
+		valid := &HeaderRewrite{Set: map[string]string{"Authorization": "Bearer token"}, Remove: []string{"X-Debug"}}
+		assert.NoError(t, (&AdvanceConfigProject{ProxyRequestHeaders: valid}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyRequestHeaders: &HeaderRewrite{Set: map[string]string{"Bad Header": "x"}}}).Validate()
+		assert.ErrorContains(t, err, "invalid header name in set")
+
+		err = (&AdvanceConfigEndpoint{ProxyRequestHeaders: &HeaderRewrite{Remove: []string{""}}}).Validate()
+		assert.ErrorContains(t, err, "invalid header name in remove")
+	})
+
+	t.Run("Invalid proxyResponseRewrite", func(t *testing.T) {
+		valid := &HeaderRewrite{Replace: []HeaderReplace{{Header: "Location", Find: "https://upstream", Replace: "http://localhost"}}}
+		assert.NoError(t, (&AdvanceConfigProject{ProxyResponseRewrite: valid}).Validate())
+
+		err := (&AdvanceConfigProject{ProxyResponseRewrite: &HeaderRewrite{Replace: []HeaderReplace{{Header: "Location"}}}}).Validate()
+		assert.ErrorContains(t, err, "replace entries require a find value")
+	})
 }
 
 func TestAdvanceConfigEndpoint_Validate(t *testing.T) {
@@ -116,6 +116,8 @@ func CheckAndHandle() error {
 		&MockResponse{},
 		&MockRule{},
 		&RequestLog{},
+		&FixtureEntry{},
+		&RoundRobinState{},
 		&User{},
 		&UserIdentity{},
 		&Workspace{},
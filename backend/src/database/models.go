@@ -69,14 +69,18 @@ func (p *Project) TableName() string {
 	return "projects"
 }
 
-// ProxyTarget defines forward request destination if project mode is proxy or forwarder
+// ProxyTarget defines forward request destination if project mode is proxy or forwarder.
+// HostHeader picks the Host sent upstream: "target" (default) uses the host of URL, "original"
+// keeps the Host the client sent (for upstreams routing by virtual host), and any other value is
+// sent literally (e.g. "api.internal.example"). It takes precedence over the project's proxyHostHeader.
 type ProxyTarget struct {
-	ID        string    `gorm:"type:string;primaryKey" json:"id"`
-	ProjectID string    `gorm:"type:string" json:"project_id"`
-	Label     string    `json:"label"` // Example: "Staging", "Production"
-	URL       string    `json:"url"`   // Example: "https://staging.example.com"
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID         string    `gorm:"type:string;primaryKey" json:"id"`
+	ProjectID  string    `gorm:"type:string" json:"project_id"`
+	Label      string    `json:"label"`       // Example: "Staging", "Production"
+	URL        string    `json:"url"`         // Example: "https://staging.example.com"
+	HostHeader string    `json:"host_header"` // "target", "original" or a literal host; empty is "target"
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // BeforeCreate hook to generate UUID string
@@ -94,7 +98,7 @@ type MockEndpoint struct {
 	Method        string         `json:"method"`                                // GET, POST, PUT, DELETE, etc
 	Path          string         `json:"path"`                                  // Example: "/users/:id"
 	Enabled       bool           `json:"enabled" gorm:"default:true"`           // Whether endpoint is active or not
-	ResponseMode  string         `json:"response_mode" gorm:"default:'random'"` // "static", "random", "round_robin"
+	ResponseMode  string         `json:"response_mode" gorm:"default:'random'"` // "static", "random", "round_robin", "weighted", "shuffle", "sequence"
 	Documentation string         `gorm:"type:text" json:"documentation"`        // Documentation URL or text
 	AdvanceConfig string         `gorm:"type:text" json:"advance_config"`       // Advanced configuration (e.g. timeout) as JSON string
 	Responses     []MockResponse `gorm:"foreignKey:EndpointID;constraint:OnDelete:CASCADE;" json:"responses"`
@@ -116,20 +120,25 @@ func (me *MockEndpoint) BeforeCreate(tx *gorm.DB) error {
 
 // MockResponse represents possible responses from an endpoint
 type MockResponse struct {
-	ID         string     `gorm:"type:string;primaryKey" json:"id"`
-	EndpointID string     `gorm:"type:string" json:"endpoint_id"`
-	StatusCode int        `json:"status_code"`                      // HTTP status code
-	Body       string     `gorm:"type:text" json:"body"`            // Response body, stored as JSON
-	Headers    string     `gorm:"type:text" json:"headers"`         // Headers stored as JSON
-	Priority   int        `json:"priority"`                         // Priority if ResponseMode = static
-	DelayMS    int        `json:"delay_ms"`                         // Delay before response (milliseconds)
-	Stream     bool       `json:"stream"`                           // True if response is stream (e.g. SSE, chunked)
-	Note       string     `gorm:"type:text" json:"note"`            // Optional note for the response
-	Enabled    bool       `json:"enabled" gorm:"default:true"`      // Whether enabled or not
-	IsFallback bool       `json:"is_fallback" gorm:"default:false"` // Whether this is a fallback response
-	Rules      []MockRule `gorm:"foreignKey:ResponseID;constraint:OnDelete:CASCADE" json:"rules"`
-	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID            string     `gorm:"type:string;primaryKey" json:"id"`
+	EndpointID    string     `gorm:"type:string" json:"endpoint_id"`
+	StatusCode    int        `json:"status_code"`                      // HTTP status code
+	Body          string     `gorm:"type:text" json:"body"`            // Response body, stored as JSON
+	BodyFilePath  string     `gorm:"type:text" json:"body_file_path"`  // Serve the body from this file (relative to RESPONSE_FILES_DIR) instead of Body
+	Headers       string     `gorm:"type:text" json:"headers"`         // Headers stored as JSON
+	Priority      int        `json:"priority"`                         // Priority if ResponseMode = static
+	DelayMS       int        `json:"delay_ms"`                         // Delay before response (milliseconds)
+	Stream        bool       `json:"stream"`                           // True if response is stream (e.g. SSE, chunked)
+	Note          string     `gorm:"type:text" json:"note"`            // Optional note for the response
+	Enabled       bool       `json:"enabled" gorm:"default:true"`      // Whether enabled or not
+	IsFallback    bool       `json:"is_fallback" gorm:"default:false"` // Whether this is a fallback response
+	RedirectURL   string     `gorm:"type:text" json:"redirect_url"`    // Location for redirect responses (non-3xx status defaults to 302)
+	AdvanceConfig string     `gorm:"type:text" json:"advance_config"`  // Advanced configuration (e.g. body-only delay) as JSON string
+	Cookies       string     `gorm:"type:text" json:"cookies"`         // Cookies stored as JSON array of ResponseCookie
+	RuleLogic     string     `gorm:"default:all" json:"rule_logic"`    // "all" (default) when every rule must match, "any" when one is enough
+	Rules         []MockRule `gorm:"foreignKey:ResponseID;constraint:OnDelete:CASCADE" json:"rules"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // BeforeCreate hook to generate UUID string
@@ -144,9 +153,9 @@ func (mr *MockResponse) BeforeCreate(tx *gorm.DB) error {
 type MockRule struct {
 	ID         string `gorm:"type:string;primaryKey" json:"id"`
 	ResponseID string `gorm:"type:string" json:"response_id"`
-	Type       string `json:"type"`     // "header", "body", "query", "path"
+	Type       string `json:"type"`     // "header", "body", "query", "path", "nth_request", "device", "query_signature", "request_line", "body_hash", "geo", "header_base64", "fixture", "xml_body", "call_index", "graphql", "cookie", "method", "ip"
 	Key        string `json:"key"`      // Example: "X-Auth", "q", "user.id"
-	Operator   string `json:"operator"` // "equals", "contains", "regex"
+	Operator   string `json:"operator"` // "equals", "contains", "not_equals", "not_contains", "regex", "cidr", "gt"/"gte"/"lt"/"lte", "empty"/"not_empty" (body only), "exists"/"not_exists" (header/query/body/cookie)
 	Value      string `json:"value"`
 }
 
@@ -158,6 +167,36 @@ func (mr *MockRule) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// RoundRobinState is the persisted round-robin counter of an endpoint, used when
+// ROUND_ROBIN_PERSISTENT is on so rotation survives restarts and is shared by replicas
+type RoundRobinState struct {
+	EndpointID string    `gorm:"type:string;primaryKey" json:"endpoint_id"`
+	Counter    int64     `gorm:"not null;default:0" json:"counter"` // Requests served so far
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// FixtureEntry is a value in a project's fixture store: durable key-value state that mock
+// responses read and write across requests and restarts (e.g. carts, sessions)
+type FixtureEntry struct {
+	ID        string    `gorm:"type:string;primaryKey" json:"id"`
+	ProjectID string    `gorm:"type:string;uniqueIndex:idx_fixture_project_key;not null" json:"project_id"`
+	Key       string    `gorm:"type:string;uniqueIndex:idx_fixture_project_key;not null" json:"key"`
+	Value     string    `gorm:"type:text" json:"value"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Association to the Project
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID string
+func (fe *FixtureEntry) BeforeCreate(tx *gorm.DB) error {
+	if fe.ID == "" {
+		fe.ID = uuid.New().String()
+	}
+	return nil
+}
+
 // SourceRequest defines the source of the request log.
 type SourceRequest string
 
@@ -185,6 +224,10 @@ type RequestLog struct {
 	Bookmark        bool   `gorm:"type:bool" json:"bookmark"`           // Optional bookmark for easy reference
 	LogsHash        string `gorm:"type:string" json:"logs_hash"`        // Hash of the response body for integrity checks + jwt signature
 
+	// ResponseBodyTruncated is true when ResponseBody holds only the start of the body
+	// (LOG_MAX_BODY_BYTES or the decode limit of compressed upstream bodies)
+	ResponseBodyTruncated bool `gorm:"default:false" json:"response_body_truncated"`
+
 	Source SourceRequest `gorm:"size:50;not null default:''" json:"source"` // Source of the request: "replay", "echo", etc.
 
 	// ExecutionMode indicates the handling logic used for this request.
@@ -192,8 +235,10 @@ type RequestLog struct {
 	ExecutionMode ProjectMode `gorm:"type:string" json:"execution_mode"`
 
 	// Matched is true if the request matched an existing mock endpoint.
-	Matched   bool      `gorm:"default:false" json:"matched"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"` // Timestamp of the request
+	Matched    bool      `gorm:"default:false" json:"matched"`
+	EndpointID string    `gorm:"type:string;index" json:"endpoint_id"` // Endpoint that answered, empty when none matched
+	ResponseID string    `gorm:"type:string" json:"response_id"`       // Mock response selected for the request, empty when proxied
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`     // Timestamp of the request
 
 	// Association to the Project
 	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
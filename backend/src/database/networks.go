@@ -0,0 +1,40 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseNetworks parses a comma-separated list of CIDRs and bare IPs (e.g. "10.0.0.0/8,
+// 192.168.1.7"), as used by cidr rules and TRUSTED_PROXIES. "*" and empty entries are skipped;
+// invalid entries are left out and reported in the error.
+func ParseNetworks(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	var invalid []string
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || entry == "*" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+
+	if len(invalid) > 0 {
+		return networks, fmt.Errorf("invalid network %s: must be an IP or CIDR", strings.Join(invalid, ", "))
+	}
+	return networks, nil
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks("10.0.0.0/8, 192.168.1.7, *, , 2001:db8::/32")
+	require.NoError(t, err)
+	require.Len(t, networks, 3)
+	assert.True(t, networks[1].Contains(net.ParseIP("192.168.1.7")))
+	assert.False(t, networks[1].Contains(net.ParseIP("192.168.1.8")))
+
+	networks, err = ParseNetworks("10.0.0.0/8, example.com")
+	assert.ErrorContains(t, err, "example.com")
+	assert.Len(t, networks, 1, "valid entries are still returned")
+}
@@ -50,18 +50,33 @@ func (r *ruleRepository) CreateRule(rule *database.MockRule) error {
 
 	// Validate rule type
 	switch rule.Type {
-	case "header", "query", "body":
+	case "header", "query", "body", "nth_request", "device", "query_signature", "request_line", "body_hash", "geo", "header_base64", "fixture", "xml_body", "call_index", "graphql", "cookie", "method", "ip":
 		// Valid types
 	default:
-		return fmt.Errorf("invalid rule type: %s, must be header, query, or body", rule.Type)
+		return fmt.Errorf("invalid rule type: %s, must be header, query, body, nth_request, device, query_signature, request_line, body_hash, geo, header_base64, fixture, xml_body, call_index, graphql, cookie, method, or ip", rule.Type)
 	}
 
 	// Validate operator
 	switch rule.Operator {
-	case "equals", "contains", "regex":
+	case "equals", "contains", "not_equals", "not_contains", "regex", "gt", "gte", "lt", "lte":
 		// Valid operators
+	case "cidr":
+		// Network membership of IP values, e.g. the client address of ip rules
+		if _, err := database.ParseNetworks(rule.Value); err != nil {
+			return err
+		}
+	case "empty", "not_empty":
+		// Presence operators, only meaningful for body rules
+		if rule.Type != "body" {
+			return fmt.Errorf("operator %s is only supported for body rules", rule.Operator)
+		}
+	case "exists", "not_exists":
+		// Presence operators for headers, query parameters, body fields and cookies
+		if rule.Type != "header" && rule.Type != "query" && rule.Type != "body" && rule.Type != "cookie" {
+			return fmt.Errorf("operator %s is only supported for header, query, body and cookie rules", rule.Operator)
+		}
 	default:
-		return fmt.Errorf("invalid operator: %s, must be equals, contains, or regex", rule.Operator)
+		return fmt.Errorf("invalid operator: %s, must be equals, contains, not_equals, not_contains, regex, cidr, gt, gte, lt, lte, empty, not_empty, exists, or not_exists", rule.Operator)
 	}
 
 	// Create rule
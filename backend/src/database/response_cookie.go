@@ -0,0 +1,91 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SameSite values accepted on a ResponseCookie
+const (
+	CookieSameSiteLax    = "lax"
+	CookieSameSiteStrict = "strict"
+	CookieSameSiteNone   = "none"
+)
+
+// ResponseCookie is a structured cookie definition serialized into a Set-Cookie header
+type ResponseCookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	MaxAge   int        `json:"maxAge,omitempty"`  // Seconds; negative deletes the cookie
+	Expires  *time.Time `json:"expires,omitempty"` // RFC 3339 timestamp
+	Secure   bool       `json:"secure,omitempty"`
+	HttpOnly bool       `json:"httpOnly,omitempty"`
+	SameSite string     `json:"sameSite,omitempty"` // lax, strict or none
+}
+
+// Validate validates a cookie definition
+func (c ResponseCookie) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("cookie name is required")
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case "", CookieSameSiteLax, CookieSameSiteStrict, CookieSameSiteNone:
+	default:
+		return fmt.Errorf("cookie %s: sameSite must be lax, strict or none", c.Name)
+	}
+
+	return nil
+}
+
+// HTTPCookie converts the definition into a net/http cookie
+func (c ResponseCookie) HTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		MaxAge:   c.MaxAge,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+	if c.Expires != nil {
+		cookie.Expires = *c.Expires
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case CookieSameSiteLax:
+		cookie.SameSite = http.SameSiteLaxMode
+	case CookieSameSiteStrict:
+		cookie.SameSite = http.SameSiteStrictMode
+	case CookieSameSiteNone:
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return cookie
+}
+
+// ParseResponseCookies parses the JSON cookie list stored on a MockResponse
+func ParseResponseCookies(cookiesJSON string) ([]ResponseCookie, error) {
+	if cookiesJSON == "" {
+		return nil, nil
+	}
+
+	var cookies []ResponseCookie
+	if err := json.Unmarshal([]byte(cookiesJSON), &cookies); err != nil {
+		return nil, fmt.Errorf("invalid cookies JSON: %w", err)
+	}
+
+	for _, cookie := range cookies {
+		if err := cookie.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cookies, nil
+}
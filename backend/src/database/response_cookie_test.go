@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResponseCookies(t *testing.T) {
+	t.Run("Empty string returns no cookies", func(t *testing.T) {
+		cookies, err := ParseResponseCookies("")
+		require.NoError(t, err)
+		assert.Empty(t, cookies)
+	})
+
+	t.Run("Valid cookie list", func(t *testing.T) {
+		cookies, err := ParseResponseCookies(`[{"name":"a","value":"1"},{"name":"b","value":"2","sameSite":"Lax"}]`)
+		require.NoError(t, err)
+		require.Len(t, cookies, 2)
+		assert.Equal(t, "b", cookies[1].Name)
+	})
+
+	t.Run("Missing name is rejected", func(t *testing.T) {
+		_, err := ParseResponseCookies(`[{"value":"1"}]`)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown sameSite is rejected", func(t *testing.T) {
+		_, err := ParseResponseCookies(`[{"name":"a","sameSite":"sometimes"}]`)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid JSON is rejected", func(t *testing.T) {
+		_, err := ParseResponseCookies(`{`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule logic of a MockResponse: whether all of its rules or any one of them must match
+const (
+	RuleLogicAll = "all"
+	RuleLogicAny = "any"
+)
+
+// ValidateRuleLogic checks the rule_logic of a response; empty is treated as all
+func ValidateRuleLogic(logic string) error {
+	switch strings.ToLower(strings.TrimSpace(logic)) {
+	case "", RuleLogicAll, RuleLogicAny:
+		return nil
+	}
+	return fmt.Errorf("invalid rule_logic %q: must be all or any", logic)
+}
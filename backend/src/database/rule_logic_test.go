@@ -0,0 +1,14 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRuleLogic(t *testing.T) {
+	for _, logic := range []string{"", "all", "any", "ANY"} {
+		assert.NoError(t, ValidateRuleLogic(logic), logic)
+	}
+	assert.Error(t, ValidateRuleLogic("or"))
+}
@@ -10,10 +10,14 @@ import (
 
 // Gin context keys
 const (
-	KeyProjectID     = "projectID"
-	KeyExecutionMode = "executionMode"
-	KeyMatched       = "matched"
-	KeyPath          = "path"
+	KeyProjectID             = "projectID"
+	KeyExecutionMode         = "executionMode"
+	KeyMatched               = "matched"
+	KeyPath                  = "path"
+	KeyResponseBody          = "responseBody"          // Decoded body to log instead of the bytes written
+	KeyResponseBodyTruncated = "responseBodyTruncated" // The decoded body is cut short, see services.DecodedBody
+	KeyEndpointID            = "endpointID"            // Endpoint that answered the request
+	KeyResponseID            = "responseID"            // Mock response selected for the request
 )
 
 var mockService *services.MockService
@@ -37,6 +41,12 @@ func EnsureMockService() {
 	}
 }
 
+// GetMockService returns the shared mock service, initializing it when needed
+func GetMockService() *services.MockService {
+	EnsureMockService()
+	return mockService
+}
+
 // GetProjectURL returns the URL for accessing a project's API
 // It handles different URL formats based on PROXY_MODE configuration
 func GetProjectURL(scheme, host string, project database.Project) string {
@@ -0,0 +1,68 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/services"
+)
+
+// ResetEndpointStateHandler resets an endpoint's runtime state (request counter, round-robin, shuffle and sequence position).
+// An optional call_count seeds the request counter, e.g. 3 makes the next call match call_index 3.
+//
+// Sample curl:
+// curl -X POST "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/endpoints/{id}/reset-state" -H "Authorization: Bearer {token}"
+// curl -X POST "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/endpoints/{id}/reset-state" -H "Authorization: Bearer {token}" -H "Content-Type: application/json" -d '{"call_count": 3}'
+func ResetEndpointStateHandler(c *gin.Context) {
+	projectId := c.Param("projectId")
+	endpointID := c.Param("id")
+	if projectId == "" || endpointID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and endpoint ID are required",
+		})
+		return
+	}
+
+	var req struct {
+		CallCount *int64 `json:"call_count"` // Calls the endpoint is treated as having received
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+	if req.CallCount != nil && *req.CallCount < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "call_count must not be negative",
+		})
+		return
+	}
+
+	var endpoint database.MockEndpoint
+	result := database.GetDB().Where("id = ? AND project_id = ?", endpointID, projectId).First(&endpoint)
+	if result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Endpoint not found",
+		})
+		return
+	}
+
+	services.ResetEndpointState(endpoint.ID)
+	if req.CallCount != nil {
+		services.SeedEndpointCallCount(endpoint.ID, *req.CallCount)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Endpoint state reset successfully",
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/services"
+)
+
+// grpcProjectHeader is the metadata key naming the project of a gRPC call
+const grpcProjectHeader = "beo-echo-project"
+
+// handleGRPCRequest answers a unary or server-streaming gRPC call with the project's mocks
+//
+// Sample grpcurl:
+// grpcurl -plaintext -H "beo-echo-project: my-project" -d '{"name":"Ada"}' localhost:3600 helloworld.Greeter/SayHello
+func handleGRPCRequest(c *gin.Context) {
+	projectAlias, path := grpcProjectAndPath(c)
+	if projectAlias == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Project not specified",
+		})
+		return
+	}
+
+	req, matchInfo := services.WithMatchInfo(c.Request)
+	resp, err, projectID, mode, matched := mockService.HandleGRPCRequest(req.Context(), projectAlias, path, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Error processing gRPC request: " + err.Error(),
+		})
+		return
+	}
+
+	c.Set(KeyProjectID, projectID)
+	c.Set(KeyExecutionMode, string(mode))
+	c.Set(KeyMatched, matched)
+	c.Set(KeyPath, path)
+	setMatchInfo(c, matchInfo)
+
+	writeMockResponse(c, resp)
+}
+
+// grpcProjectAndPath returns the project alias and /<package.Service>/<Method> path of a gRPC
+// call. gRPC clients can't add a path prefix, so the project comes from the beo-echo-project
+// metadata or the subdomain; a /<alias>/<package.Service>/<Method> path is accepted as well.
+func grpcProjectAndPath(c *gin.Context) (string, string) {
+	if alias := c.GetHeader(grpcProjectHeader); alias != "" {
+		return alias, c.Request.URL.Path
+	}
+	if parts := strings.Split(c.Request.Host, "."); len(parts) > 2 {
+		return parts[0], c.Request.URL.Path
+	}
+	return c.Param("project"), c.Param("path")
+}
@@ -6,8 +6,31 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/services"
 )
 
+// MockRoutePath is the catch-all route of mock project endpoints
+const MockRoutePath = "/:project/*path"
+
+// MockCORSMiddleware applies the server-wide CORS middleware to the mock catch-all route, except
+// to cross-origin requests of projects with their own cors config, whose policy (preflight
+// answers included) applies instead. The project looked up here is reused by MockRequestHandler.
+func MockCORSMiddleware(serverCORS gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Header.Get("Origin") == "" || c.Param("project") == "" {
+			serverCORS(c)
+			return
+		}
+
+		req, projectHandlesCORS := GetMockService().ResolveProjectCORS(c.Request, c.Param("project"))
+		c.Request = req
+		if !projectHandlesCORS {
+			serverCORS(c)
+		}
+	}
+}
+
 // MockRequestHandler is a catch-all handler for mock API endpoints
 //
 // Sample curl:
@@ -24,6 +47,12 @@ func MockRequestHandler(c *gin.Context) {
 		return
 	}
 
+	// gRPC calls carry protobuf messages and report their status in trailers
+	if services.IsGRPCRequest(c.Request) {
+		handleGRPCRequest(c)
+		return
+	}
+
 	// Get project alias from route parameter first (if available)
 	projectAlias := c.Param("project")
 
@@ -45,8 +74,14 @@ func MockRequestHandler(c *gin.Context) {
 		path = "/"
 	}
 
-	// Process the request with context
-	resp, err, projectID, mode, matched := mockService.HandleRequest(c.Request.Context(), projectAlias, c.Request.Method, path, c.Request)
+	// Upgrades to endpoints with a webSocket config are served as WebSocket mocks
+	if services.IsWebSocketUpgrade(c.Request) && handleWebSocketRequest(c, projectAlias, path) {
+		return
+	}
+
+	// Process the request with context, noting the endpoint and response that answer it
+	req, matchInfo := services.WithMatchInfo(c.Request)
+	resp, err, projectID, mode, matched := mockService.HandleRequest(req.Context(), projectAlias, req.Method, path, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -60,7 +95,19 @@ func MockRequestHandler(c *gin.Context) {
 	c.Set(KeyExecutionMode, string(mode))
 	c.Set(KeyMatched, matched)
 	c.Set(KeyPath, path)
+	setMatchInfo(c, matchInfo)
 
+	writeMockResponse(c, resp)
+}
+
+// setMatchInfo stores the endpoint and response that answered the request for the request log
+func setMatchInfo(c *gin.Context, info *services.MatchInfo) {
+	c.Set(KeyEndpointID, info.EndpointID)
+	c.Set(KeyResponseID, info.ResponseID)
+}
+
+// writeMockResponse sends the status, headers, body and trailers of a service response
+func writeMockResponse(c *gin.Context, resp *http.Response) {
 	// Copy response headers
 	for key, values := range resp.Header {
 		for _, value := range values {
@@ -74,9 +121,66 @@ func MockRequestHandler(c *gin.Context) {
 	// Copy response body
 	if resp.Body != nil {
 		defer resp.Body.Close()
-		// Copy body to response writer
-		if body, err := io.ReadAll(resp.Body); err == nil {
-			c.Writer.Write(body)
+
+		// Decoded upstream bodies are logged readable while the client gets the bytes as sent
+		if decoded, ok := resp.Body.(services.DecodedBody); ok {
+			c.Set(KeyResponseBody, string(decoded.DecodedBytes()))
+			c.Set(KeyResponseBodyTruncated, decoded.DecodedTruncated())
+		}
+
+		// Injected connection failures close the connection without a response
+		if _, ok := resp.Body.(services.AbortedBody); ok {
+			abortConnection(c)
+			return
+		}
+
+		if _, ok := resp.Body.(services.StreamingBody); ok {
+			// Streaming bodies get headers on the wire first and are flushed chunk by chunk
+			writeStreamingBody(c, resp.Body)
+		} else {
+			// Copy body to response writer
+			io.Copy(c.Writer, resp.Body)
+		}
+	}
+
+	// Trailers are announced with the TrailerPrefix once the body is written; proxied ones are
+	// only known once the upstream body has been read to the end
+	for key, values := range resp.Trailer {
+		for _, value := range values {
+			c.Writer.Header().Add(http.TrailerPrefix+key, value)
+		}
+	}
+}
+
+// abortConnection closes the client connection without writing anything. Connections that
+// can't be taken over (HTTP/2) get the recorded status with an empty body instead.
+func abortConnection(c *gin.Context) {
+	if c.Request.ProtoMajor == 1 {
+		if conn, _, err := c.Writer.Hijack(); err == nil {
+			conn.Close()
+			return
+		}
+	}
+	c.Writer.WriteHeaderNow()
+}
+
+// writeStreamingBody flushes the status and headers, then copies the body flushing after each chunk.
+// Stops when the body ends or errors (e.g. the client disconnected).
+func writeStreamingBody(c *gin.Context, body io.Reader) {
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, writeErr := c.Writer.Write(buf[:n]); writeErr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			return
 		}
 	}
 }
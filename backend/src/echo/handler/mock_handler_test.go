@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMockRequestHandler_InjectedAbortClosesConnection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	database.SetupTestEnvironment(t)
+
+	setup, err := database.InitTestWorkspaceWithProject("abort@example.com", "Abort User", "Abort Workspace", "Abort Project", "abort-project")
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	endpoint, err := database.CreateTestEndpointWithConfig(setup.Project.ID, "GET", "/flaky", `{"failureInjection": {"rate": 1, "kind": "abort"}}`)
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Create(&database.MockResponse{EndpointID: endpoint.ID, StatusCode: 200, Body: "ok", Enabled: true}).Error)
+
+	InitMockService()
+	router := gin.New()
+	router.Any("/:project/*path", MockRequestHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/abort-project/flaky")
+	if err == nil {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		t.Fatalf("expected the connection to be closed, got %d %q", resp.StatusCode, body)
+	}
+	assert.Error(t, err)
+}
+
+func TestMockRequestHandler_GRPCStatusTrailers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	database.SetupTestEnvironment(t)
+
+	setup, err := database.InitTestWorkspaceWithProject("grpc@example.com", "gRPC User", "gRPC Workspace", "gRPC Project", "grpc-project")
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	InitMockService()
+	router := gin.New()
+	router.UseH2C = true
+	router.Any("/:project/*path", MockRequestHandler)
+	server := httptest.NewServer(router.Handler())
+	defer server.Close()
+
+	// Cleartext HTTP/2, as gRPC clients dial without TLS
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/helloworld.Greeter/SayHello", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("beo-echo-project", "grpc-project")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/grpc", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "12", resp.Trailer.Get("Grpc-Status"), "the project has no grpc config")
+	assert.Equal(t, "gRPC is not configured for this project", resp.Trailer.Get("Grpc-Message"))
+}
+
+// streamedBody is a services.StreamingBody over a fixed reader
+type streamedBody struct{ io.Reader }
+
+func (streamedBody) Close() error { return nil }
+func (streamedBody) Streaming()   {}
+
+func TestWriteMockResponse_StreamedTrailers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	writeMockResponse(c, &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/event-stream"}},
+		Body:       streamedBody{strings.NewReader("data: hi\n\n")},
+		Trailer:    http.Header{"Grpc-Status": {"0"}},
+	})
+
+	result := recorder.Result()
+	body, _ := io.ReadAll(result.Body)
+	assert.Equal(t, "data: hi\n\n", string(body))
+	assert.Equal(t, "0", result.Trailer.Get("Grpc-Status"))
+}
+
+func TestMockCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	database.SetupTestEnvironment(t)
+
+	setup, err := database.InitTestWorkspaceWithProject("cors@example.com", "CORS User", "CORS Workspace", "CORS Project", "cors-project")
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	InitMockService()
+	serverCORS := func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "https://dashboard.test")
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+		}
+	}
+	router := gin.New()
+	router.Any(MockRoutePath, MockCORSMiddleware(serverCORS), MockRequestHandler)
+
+	preflight := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/cors-project/items", nil)
+		req.Header.Set("Origin", "https://app.test")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("Projects without a cors config get the server policy", func(t *testing.T) {
+		assert.Equal(t, "https://dashboard.test", preflight().Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Projects with a cors config answer themselves", func(t *testing.T) {
+		setup.Project.AdvanceConfig = `{"cors": {"enabled": true, "allowOrigins": ["https://app.test"]}}`
+		require.NoError(t, database.DB.Save(setup.Project).Error)
+
+		assert.Equal(t, "https://app.test", preflight().Header().Get("Access-Control-Allow-Origin"))
+	})
+}
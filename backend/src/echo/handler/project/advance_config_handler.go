@@ -79,8 +79,8 @@ Sample curl:
 	    },
 	    "cors": {
 	      "enabled": true,
-	      "allowed_origins": ["https://example.com"],
-	      "allowed_methods": ["GET", "POST"]
+	      "allowOrigins": ["https://example.com"],
+	      "allowMethods": ["GET", "POST"]
 	    }
 	  }'
 */
@@ -0,0 +1,210 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/services"
+)
+
+// SetFixtureRequest is the body of SetFixtureHandler
+type SetFixtureRequest struct {
+	Value string `json:"value"`
+}
+
+/*
+ListFixturesHandler returns the entries of the project's fixture store
+
+Sample curl:
+
+	curl -X GET "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/fixtures" \
+	  -H "Authorization: Bearer {token}"
+*/
+func ListFixturesHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	entries, err := services.ListFixtures(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to list fixtures: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+/*
+SetFixtureHandler creates or replaces a value in the project's fixture store
+
+Sample curl:
+
+	curl -X PUT "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/fixtures/{key}" \
+	  -H "Authorization: Bearer {token}" \
+	  -H "Content-Type: application/json" \
+	  -d '{"value": "[\"sku-1\"]"}'
+*/
+func SetFixtureHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	key := c.Param("key")
+	if projectID == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and fixture key are required",
+		})
+		return
+	}
+
+	var req SetFixtureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := services.SetFixture(projectID, key, req.Value); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to set fixture: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Fixture saved successfully",
+	})
+}
+
+/*
+PatchFixtureHandler merges a JSON Merge Patch (RFC 7396) into a value of the project's fixture
+store. Members set to null are removed; a missing key starts from an empty document.
+
+Sample curl:
+
+	curl -X PATCH "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/fixtures/{key}" \
+	  -H "Authorization: Bearer {token}" \
+	  -H "Content-Type: application/merge-patch+json" \
+	  -d '{"status": "shipped", "coupon": null}'
+*/
+func PatchFixtureHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	key := c.Param("key")
+	if projectID == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and fixture key are required",
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	value, err := services.PatchFixture(projectID, key, patch)
+	if errors.Is(err, services.ErrInvalidMergePatch) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to patch fixture: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"key": key, "value": value},
+	})
+}
+
+/*
+DeleteFixtureHandler removes a key from the project's fixture store
+
+Sample curl:
+
+	curl -X DELETE "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/fixtures/{key}" \
+	  -H "Authorization: Bearer {token}"
+*/
+func DeleteFixtureHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	key := c.Param("key")
+	if projectID == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and fixture key are required",
+		})
+		return
+	}
+
+	if err := services.DeleteFixture(projectID, key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete fixture: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Fixture deleted successfully",
+	})
+}
+
+/*
+ResetFixturesHandler clears the project's fixture store
+
+Sample curl:
+
+	curl -X DELETE "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/fixtures" \
+	  -H "Authorization: Bearer {token}"
+*/
+func ResetFixturesHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	if err := services.ResetFixtures(projectID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to reset fixtures: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Fixtures reset successfully",
+	})
+}
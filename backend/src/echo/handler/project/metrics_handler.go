@@ -0,0 +1,52 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/services"
+)
+
+/*
+GetProjectMetricsHandler returns live request metrics for a project
+
+Sample curl:
+
+	curl -X GET "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/metrics" \
+	  -H "Authorization: Bearer {token}"
+*/
+func GetProjectMetricsHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	var project database.Project
+	if result := database.GetDB().Where("id = ?", projectID).First(&project); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Project not found",
+		})
+		return
+	}
+
+	maxInFlight := 0
+	if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil {
+		maxInFlight = config.MaxInFlight
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"project_id":    project.ID,
+			"in_flight":     services.ProjectInFlight(project.ID),
+			"max_in_flight": maxInFlight,
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/handler"
+)
+
+// ReplayRequestLogRequest is the optional body of ReplayRequestLogHandler
+type ReplayRequestLogRequest struct {
+	TargetURL string `json:"target_url"` // Forward the replayed request here instead of the mock
+}
+
+/*
+ReplayRequestLogHandler runs a recorded request again and returns the fresh response
+
+Sample curl:
+
+	curl -X POST "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/logs/{logId}/replay" \
+	  -H "Authorization: Bearer {token}" \
+	  -H "Content-Type: application/json" \
+	  -d '{"target_url": "https://staging.example.com"}'
+*/
+func ReplayRequestLogHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	logID := c.Param("logId")
+	if projectID == "" || logID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and log ID are required",
+		})
+		return
+	}
+
+	var req ReplayRequestLogRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	mockService := handler.GetMockService()
+	if mockService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Mock service is not available",
+		})
+		return
+	}
+
+	resp, err := mockService.ReplayRequestLog(c.Request.Context(), projectID, logID, req.TargetURL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Failed to replay request: " + err.Error(),
+		})
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   true,
+			"message": "Failed to read replayed response: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"status_code": resp.StatusCode,
+			"headers":     resp.Header,
+			"body":        string(body),
+		},
+	})
+}
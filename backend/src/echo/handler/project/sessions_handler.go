@@ -0,0 +1,86 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/services"
+)
+
+/*
+ListSessionsHandler returns the live sessions of the project's in-memory session store
+
+Sample curl:
+
+	curl -X GET "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/sessions" \
+	  -H "Authorization: Bearer {token}"
+*/
+func ListSessionsHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    services.ListSessions(projectID),
+	})
+}
+
+/*
+DeleteSessionHandler drops one session of the project
+
+Sample curl:
+
+	curl -X DELETE "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/sessions/{key}" \
+	  -H "Authorization: Bearer {token}"
+*/
+func DeleteSessionHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	key := c.Param("key")
+	if projectID == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID and session key are required",
+		})
+		return
+	}
+
+	services.DeleteSession(projectID, key)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session deleted successfully",
+	})
+}
+
+/*
+ResetSessionsHandler drops every session of the project
+
+Sample curl:
+
+	curl -X DELETE "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/sessions" \
+	  -H "Authorization: Bearer {token}"
+*/
+func ResetSessionsHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	services.ResetSessions(projectID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sessions reset successfully",
+	})
+}
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/handler"
+)
+
+/*
+ValidateProjectConfigHandler checks all endpoints, responses and rules of a project
+and returns the list of configuration problems (empty when valid)
+
+Sample curl:
+
+	curl -X GET "http://localhost:3600/api/workspaces/{workspaceID}/projects/{projectId}/validate-config" \
+	  -H "Authorization: Bearer {token}"
+*/
+func ValidateProjectConfigHandler(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Project ID is required",
+		})
+		return
+	}
+
+	mockService := handler.GetMockService()
+	if mockService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Mock service is not available",
+		})
+		return
+	}
+
+	errs, err := mockService.ValidateProjectConfig(projectID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"valid":  len(errs) == 0,
+			"errors": errs,
+		},
+	})
+}
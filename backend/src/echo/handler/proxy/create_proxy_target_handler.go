@@ -70,6 +70,14 @@ func CreateProxyTargetHandler(c *gin.Context) {
 		return
 	}
 
+	if err := database.ValidateProxyHostHeader(proxyTarget.HostHeader); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Proxy target host_header " + err.Error(),
+		})
+		return
+	}
+
 	// Assign to project
 	proxyTarget.ProjectID = project.ID
 
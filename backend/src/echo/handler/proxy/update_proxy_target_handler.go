@@ -66,8 +66,9 @@ func UpdateProxyTargetHandler(c *gin.Context) {
 
 	// Parse update data
 	var updateData struct {
-		Label string `json:"label"`
-		URL   string `json:"url"`
+		Label      string  `json:"label"`
+		URL        string  `json:"url"`
+		HostHeader *string `json:"host_header"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -87,6 +88,17 @@ func UpdateProxyTargetHandler(c *gin.Context) {
 		existingProxy.URL = updateData.URL
 	}
 
+	if updateData.HostHeader != nil {
+		if err := database.ValidateProxyHostHeader(*updateData.HostHeader); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Proxy target host_header " + err.Error(),
+			})
+			return
+		}
+		existingProxy.HostHeader = *updateData.HostHeader
+	}
+
 	// Save updates
 	result = database.GetDB().Save(&existingProxy)
 	if result.Error != nil {
@@ -77,6 +77,33 @@ func CreateResponseHandler(c *gin.Context) {
 		response.StatusCode = 200 // Default to 200 OK
 	}
 
+	// Validate advance config if provided
+	if _, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Validate cookies if provided
+	if _, err := database.ParseResponseCookies(response.Cookies); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Validate rule logic if provided
+	if err := database.ValidateRuleLogic(response.RuleLogic); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
 	// Assign to endpoint
 	response.EndpointID = endpointIDStr
 
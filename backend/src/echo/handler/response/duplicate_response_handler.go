@@ -87,16 +87,21 @@ func DuplicateResponseHandler(c *gin.Context) {
 
 	// Create a new response by copying the original
 	duplicatedResponse := database.MockResponse{
-		ID:         uuid.New().String(), // Generate new ID
-		EndpointID: originalResponse.EndpointID,
-		StatusCode: originalResponse.StatusCode,
-		Body:       originalResponse.Body,
-		Headers:    originalResponse.Headers,
-		Priority:   originalResponse.Priority,
-		DelayMS:    originalResponse.DelayMS,
-		Stream:     originalResponse.Stream,
-		Note:       originalResponse.Note + " (Copy)", // Add "(Copy)" to distinguish
-		Enabled:    originalResponse.Enabled,
+		ID:            uuid.New().String(), // Generate new ID
+		EndpointID:    originalResponse.EndpointID,
+		StatusCode:    originalResponse.StatusCode,
+		Body:          originalResponse.Body,
+		BodyFilePath:  originalResponse.BodyFilePath,
+		Headers:       originalResponse.Headers,
+		Priority:      originalResponse.Priority,
+		DelayMS:       originalResponse.DelayMS,
+		Stream:        originalResponse.Stream,
+		Note:          originalResponse.Note + " (Copy)", // Add "(Copy)" to distinguish
+		Enabled:       originalResponse.Enabled,
+		RedirectURL:   originalResponse.RedirectURL,
+		AdvanceConfig: originalResponse.AdvanceConfig,
+		Cookies:       originalResponse.Cookies,
+		RuleLogic:     originalResponse.RuleLogic,
 		// Don't copy Rules here - we'll handle them separately
 	}
 
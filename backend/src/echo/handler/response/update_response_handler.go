@@ -82,15 +82,20 @@ func UpdateResponseHandler(c *gin.Context) {
 
 	// Parse update data
 	var updateData struct {
-		StatusCode *int    `json:"status_code"`
-		Body       *string `json:"body"`
-		Headers    *string `json:"headers"` // Allow headers to be null
-		Priority   *int    `json:"priority"`
-		DelayMS    *int    `json:"delay_ms"`
-		Stream     *bool   `json:"stream"`
-		Enabled    *bool   `json:"enabled"`
-		Note       *string `json:"note"`
-		IsFallback *bool   `json:"is_fallback"`
+		StatusCode    *int    `json:"status_code"`
+		Body          *string `json:"body"`
+		BodyFilePath  *string `json:"body_file_path"`
+		Headers       *string `json:"headers"` // Allow headers to be null
+		Priority      *int    `json:"priority"`
+		DelayMS       *int    `json:"delay_ms"`
+		Stream        *bool   `json:"stream"`
+		Enabled       *bool   `json:"enabled"`
+		Note          *string `json:"note"`
+		IsFallback    *bool   `json:"is_fallback"`
+		RedirectURL   *string `json:"redirect_url"`
+		AdvanceConfig *string `json:"advance_config"` // Pointer to detect if field is provided
+		Cookies       *string `json:"cookies"`
+		RuleLogic     *string `json:"rule_logic"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -110,6 +115,10 @@ func UpdateResponseHandler(c *gin.Context) {
 		existingResponse.Body = *updateData.Body
 	}
 
+	if updateData.BodyFilePath != nil {
+		existingResponse.BodyFilePath = *updateData.BodyFilePath
+	}
+
 	if updateData.Headers != nil {
 		// Check if headers are empty
 		var headers map[string]string
@@ -152,6 +161,45 @@ func UpdateResponseHandler(c *gin.Context) {
 		existingResponse.IsFallback = *updateData.IsFallback
 	}
 
+	if updateData.RedirectURL != nil {
+		existingResponse.RedirectURL = *updateData.RedirectURL
+	}
+
+	// Handle advance_config: allow empty string to clear the config
+	if updateData.AdvanceConfig != nil {
+		if _, err := database.ParseResponseAdvanceConfig(*updateData.AdvanceConfig); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+		existingResponse.AdvanceConfig = *updateData.AdvanceConfig
+	}
+
+	// Handle cookies: allow empty string to clear them
+	if updateData.Cookies != nil {
+		if _, err := database.ParseResponseCookies(*updateData.Cookies); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+		existingResponse.Cookies = *updateData.Cookies
+	}
+
+	if updateData.RuleLogic != nil {
+		if err := database.ValidateRuleLogic(*updateData.RuleLogic); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+		existingResponse.RuleLogic = *updateData.RuleLogic
+	}
+
 	// Save updates
 	result = database.GetDB().Save(&existingResponse)
 	if result.Error != nil {
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"beo-echo/backend/src/echo/services"
+)
+
+// handleWebSocketRequest runs the endpoint's WebSocket mock on the upgraded connection. It
+// returns false when the path has no WebSocket mock so the request is served as plain HTTP.
+//
+// Sample websocat:
+// websocat "ws://localhost:8000/my-project/ws/chat"
+func handleWebSocketRequest(c *gin.Context, projectAlias, path string) bool {
+	mock, ok := mockService.FindWebSocketMock(projectAlias, path)
+	if !ok {
+		return false
+	}
+
+	c.Set(KeyProjectID, mock.ProjectID)
+	c.Set(KeyExecutionMode, string(mock.Mode))
+	c.Set(KeyMatched, true)
+	c.Set(KeyPath, path)
+	c.Set(KeyEndpointID, mock.EndpointID)
+
+	// Recorded for the request log; gin doesn't write it since the connection is hijacked
+	c.Status(http.StatusSwitchingProtocols)
+	if err := services.ServeWebSocket(c.Request.Context(), c.Writer, c.Request, mock.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "WebSocket handshake failed: " + err.Error(),
+		})
+	}
+	return true
+}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"strings"
+	"time"
+
 	"beo-echo/backend/src/database"
 
 	"gorm.io/gorm"
@@ -18,8 +21,53 @@ func NewLogRepository(db *gorm.DB) *LogRepository {
 	}
 }
 
-// GetLogs retrieves logs with pagination
-func (r *LogRepository) GetLogs(page, pageSize int, projectID string) ([]database.RequestLog, int64, error) {
+// LogFilter narrows a log listing; zero fields don't filter
+type LogFilter struct {
+	Method     string    // Exact method, e.g. "POST"
+	Path       string    // Substring of the request path
+	Status     int       // Exact response status
+	Matched    *bool     // Whether the request matched a mock endpoint
+	EndpointID string    // Endpoint that answered
+	ResponseID string    // Mock response that was selected
+	Mode       string    // Execution mode, e.g. "mock" or "proxy"
+	Since      time.Time // Logged at or after
+	Until      time.Time // Logged before
+}
+
+// apply adds the filter conditions to a request log query
+func (f LogFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Method != "" {
+		query = query.Where("method = ?", strings.ToUpper(f.Method))
+	}
+	if f.Path != "" {
+		query = query.Where("path LIKE ?", "%"+f.Path+"%")
+	}
+	if f.Status != 0 {
+		query = query.Where("response_status = ?", f.Status)
+	}
+	if f.Matched != nil {
+		query = query.Where("matched = ?", *f.Matched)
+	}
+	if f.EndpointID != "" {
+		query = query.Where("endpoint_id = ?", f.EndpointID)
+	}
+	if f.ResponseID != "" {
+		query = query.Where("response_id = ?", f.ResponseID)
+	}
+	if f.Mode != "" {
+		query = query.Where("execution_mode = ?", f.Mode)
+	}
+	if !f.Since.IsZero() {
+		query = query.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		query = query.Where("created_at < ?", f.Until)
+	}
+	return query
+}
+
+// GetLogs retrieves logs matching the filter with pagination
+func (r *LogRepository) GetLogs(page, pageSize int, projectID string, filter LogFilter) ([]database.RequestLog, int64, error) {
 	var logs []database.RequestLog
 	var total int64
 
@@ -29,6 +77,7 @@ func (r *LogRepository) GetLogs(page, pageSize int, projectID string) ([]databas
 	if projectID != "" {
 		query = query.Where("project_id = ?", projectID)
 	}
+	query = filter.apply(query)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -79,3 +128,31 @@ func (r *LogRepository) ClearNonBookmarkedLogs(projectID string) (int64, error)
 	
 	return result.RowsAffected, nil
 }
+
+// PruneLogs applies the retention limits to a project's logs: logs created before olderThan are
+// deleted (zero keeps them), then all but the newest maxEntries (0 = unlimited). Bookmarked logs
+// are never deleted.
+func (r *LogRepository) PruneLogs(projectID string, maxEntries int, olderThan time.Time) (int64, error) {
+	var deleted int64
+	notBookmarked := r.DB.Where("project_id = ? AND (bookmark = ? OR bookmark IS NULL)", projectID, false)
+
+	if !olderThan.IsZero() {
+		result := notBookmarked.Session(&gorm.Session{}).Where("created_at < ?", olderThan).Delete(&database.RequestLog{})
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+
+	if maxEntries > 0 {
+		newest := r.DB.Model(&database.RequestLog{}).Select("id").
+			Where("project_id = ?", projectID).Order("created_at DESC").Limit(maxEntries)
+		result := notBookmarked.Session(&gorm.Session{}).Where("id NOT IN (?)", newest).Delete(&database.RequestLog{})
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += result.RowsAffected
+	}
+
+	return deleted, nil
+}
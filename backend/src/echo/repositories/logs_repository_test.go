@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"beo-echo/backend/src/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLogRepositoryTest(t *testing.T, alias string) (*LogRepository, *database.Project, time.Time) {
+	database.SetupTestEnvironment(t)
+	setup, err := database.InitTestWorkspaceWithProject(alias+"@example.com", "Logs User", "Logs Workspace", "Logs Project", alias)
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	repo := NewLogRepository(database.DB)
+	t.Cleanup(func() { repo.DB.Where("project_id = ?", setup.Project.ID).Delete(&database.RequestLog{}) })
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	logs := []database.RequestLog{
+		{Method: "GET", Path: "/users", ResponseStatus: 200, Matched: true, EndpointID: "ep-users", ResponseID: "resp-ok", ExecutionMode: database.ModeMock},
+		{Method: "POST", Path: "/users", ResponseStatus: 201, Matched: true, EndpointID: "ep-create", ResponseID: "resp-created", ExecutionMode: database.ModeMock},
+		{Method: "GET", Path: "/orders/1", ResponseStatus: 502, ExecutionMode: database.ModeProxy},
+		{Method: "GET", Path: "/users/1", ResponseStatus: 404, ExecutionMode: database.ModeMock, Bookmark: true},
+	}
+	for i := range logs {
+		logs[i].ProjectID = setup.Project.ID
+		logs[i].CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, repo.DB.Create(&logs[i]).Error)
+	}
+	return repo, setup.Project, base
+}
+
+func TestLogRepository_GetLogsFilter(t *testing.T) {
+	repo, project, base := setupLogRepositoryTest(t, "logs-filter")
+	matched, notMatched := true, false
+
+	paths := func(filter LogFilter) []string {
+		logs, total, err := repo.GetLogs(1, 100, project.ID, filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(logs)), total)
+		var result []string
+		for _, log := range logs {
+			result = append(result, log.Method+" "+log.Path)
+		}
+		return result
+	}
+
+	assert.Len(t, paths(LogFilter{}), 4)
+	assert.Equal(t, []string{"GET /users/1", "GET /orders/1", "GET /users"}, paths(LogFilter{Method: "get"}), "newest first")
+	assert.Equal(t, []string{"GET /users/1", "POST /users", "GET /users"}, paths(LogFilter{Path: "users"}))
+	assert.Equal(t, []string{"GET /orders/1"}, paths(LogFilter{Status: 502}))
+	assert.Equal(t, []string{"POST /users", "GET /users"}, paths(LogFilter{Matched: &matched}))
+	assert.Equal(t, []string{"GET /users/1", "GET /orders/1"}, paths(LogFilter{Matched: &notMatched}))
+	assert.Equal(t, []string{"POST /users"}, paths(LogFilter{EndpointID: "ep-create"}))
+	assert.Equal(t, []string{"GET /users"}, paths(LogFilter{ResponseID: "resp-ok"}))
+	assert.Equal(t, []string{"GET /orders/1"}, paths(LogFilter{Mode: "proxy"}))
+	assert.Equal(t, []string{"GET /orders/1", "POST /users"}, paths(LogFilter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}))
+}
+
+func TestLogRepository_PruneLogs(t *testing.T) {
+	t.Run("Logs older than the retention are deleted", func(t *testing.T) {
+		repo, project, base := setupLogRepositoryTest(t, "logs-prune-age")
+
+		deleted, err := repo.PruneLogs(project.ID, 0, base.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		_, total, err := repo.GetLogs(1, 100, project.ID, LogFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("Only the newest entries are kept, bookmarks included", func(t *testing.T) {
+		repo, project, _ := setupLogRepositoryTest(t, "logs-prune-count")
+
+		deleted, err := repo.PruneLogs(project.ID, 2, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		logs, _, err := repo.GetLogs(1, 100, project.ID, LogFilter{})
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "/users/1", logs[0].Path)
+		assert.Equal(t, "/orders/1", logs[1].Path)
+	})
+
+	t.Run("Bookmarked logs are never deleted", func(t *testing.T) {
+		repo, project, base := setupLogRepositoryTest(t, "logs-prune-bookmark")
+
+		_, err := repo.PruneLogs(project.ID, 1, base.Add(24*time.Hour))
+		require.NoError(t, err)
+
+		logs, _, err := repo.GetLogs(1, 100, project.ID, LogFilter{})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.True(t, logs[0].Bookmark)
+	})
+}
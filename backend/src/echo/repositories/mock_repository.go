@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"beo-echo/backend/src/database"
@@ -26,15 +27,36 @@ func NewMockRepository(db *gorm.DB) *MockRepository {
 // FindProjectByAlias finds a project by its alias (slug/subdomain)
 func (r *MockRepository) FindProjectByAlias(alias string) (*database.Project, error) {
 	var project database.Project
-	result := r.DB.Preload("ActiveProxy").Where("alias = ?", alias).First(&project)
+	result := r.DB.Preload("ActiveProxy").Preload("ProxyTargets").Where("alias = ?", alias).First(&project)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &project, nil
 }
 
+// FindProjectWithConfig loads a project with all endpoints, responses and rules
+func (r *MockRepository) FindProjectWithConfig(projectID string) (*database.Project, error) {
+	var project database.Project
+	result := r.DB.Preload("Endpoints.Responses.Rules").Where("id = ?", projectID).First(&project)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &project, nil
+}
+
+// PathMatchOptions relaxes how request paths are compared with endpoint paths. The zero value is strict.
+type PathMatchOptions struct {
+	CaseInsensitive bool // Static segments and regex paths match regardless of case
+}
+
 // FindMatchingEndpoint finds an endpoint that matches the given method and path
 func (r *MockRepository) FindMatchingEndpoint(projectID string, method, path string) (*database.MockEndpoint, error) {
+	return r.FindMatchingEndpointWithOptions(projectID, method, path, PathMatchOptions{})
+}
+
+// FindMatchingEndpointWithOptions finds an endpoint that matches the given method and path,
+// comparing paths as the options allow
+func (r *MockRepository) FindMatchingEndpointWithOptions(projectID string, method, path string, opts PathMatchOptions) (*database.MockEndpoint, error) {
 	var endpoints []database.MockEndpoint
 
 	// Preload ProxyTarget for endpoints that use proxy
@@ -44,7 +66,7 @@ func (r *MockRepository) FindMatchingEndpoint(projectID string, method, path str
 	}
 
 	// Find best matching path (handle path params like /users/:id)
-	bestMatch := findBestPathMatch(endpoints, path)
+	bestMatch := findBestPathMatch(endpoints, path, opts)
 	if bestMatch == nil {
 		return nil, fmt.Errorf("no matching endpoint found")
 	}
@@ -52,7 +74,31 @@ func (r *MockRepository) FindMatchingEndpoint(projectID string, method, path str
 	return bestMatch, nil
 }
 
-// FindResponsesByEndpointID gets all responses for an endpoint
+// FindMethodsForPath lists the methods of the project's enabled endpoints matching the path, sorted
+func (r *MockRepository) FindMethodsForPath(projectID, path string, opts PathMatchOptions) ([]string, error) {
+	var endpoints []database.MockEndpoint
+	result := r.DB.Where("project_id = ? AND enabled = ?", projectID, true).Find(&endpoints)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	byMethod := map[string][]database.MockEndpoint{}
+	for _, endpoint := range endpoints {
+		method := strings.ToUpper(endpoint.Method)
+		byMethod[method] = append(byMethod[method], endpoint)
+	}
+	methods := []string{}
+	for method, candidates := range byMethod {
+		if findBestPathMatch(candidates, path, opts) != nil {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods, nil
+}
+
+// FindResponsesByEndpointID gets the enabled responses of an endpoint; disabled ones are left
+// out here so no selection path ever considers them
 func (r *MockRepository) FindResponsesByEndpointID(endpointID string) ([]database.MockResponse, error) {
 	var responses []database.MockResponse
 	result := r.DB.Preload("Rules").Where("endpoint_id = ? AND enabled = ?", endpointID, true).Find(&responses)
@@ -62,6 +108,46 @@ func (r *MockRepository) FindResponsesByEndpointID(endpointID string) ([]databas
 	return responses, nil
 }
 
+// FindAllResponsesByEndpointID gets every response of an endpoint, disabled ones included
+func (r *MockRepository) FindAllResponsesByEndpointID(endpointID string) ([]database.MockResponse, error) {
+	var responses []database.MockResponse
+	result := r.DB.Preload("Rules").Where("endpoint_id = ?", endpointID).Find(&responses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return responses, nil
+}
+
+// FindEndpointByMethodPath finds the endpoint declared with exactly this method and path
+func (r *MockRepository) FindEndpointByMethodPath(projectID, method, path string) (*database.MockEndpoint, error) {
+	var endpoint database.MockEndpoint
+	result := r.DB.Where("project_id = ? AND method = ? AND path = ?", projectID, strings.ToUpper(method), path).First(&endpoint)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &endpoint, nil
+}
+
+// FindProjectEndpoint gets an endpoint by ID, only when it belongs to the project
+func (r *MockRepository) FindProjectEndpoint(projectID, endpointID string) (*database.MockEndpoint, error) {
+	var endpoint database.MockEndpoint
+	result := r.DB.Where("project_id = ? AND id = ?", projectID, endpointID).First(&endpoint)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &endpoint, nil
+}
+
+// CreateEndpoint stores a new endpoint
+func (r *MockRepository) CreateEndpoint(endpoint *database.MockEndpoint) error {
+	return r.DB.Create(endpoint).Error
+}
+
+// CreateResponse stores a new response together with its rules
+func (r *MockRepository) CreateResponse(response *database.MockResponse) error {
+	return r.DB.Create(response).Error
+}
+
 // FindProxyTarget gets a proxy target by ID
 func (r *MockRepository) GetProxyTarget(proxyTargetID string) (*database.ProxyTarget, error) {
 	var proxyTarget database.ProxyTarget
@@ -72,22 +158,45 @@ func (r *MockRepository) GetProxyTarget(proxyTargetID string) (*database.ProxyTa
 	return &proxyTarget, nil
 }
 
+// FindRequestLog gets a request log of a project, with the project loaded
+func (r *MockRepository) FindRequestLog(projectID, logID string) (*database.RequestLog, error) {
+	var requestLog database.RequestLog
+	result := r.DB.Preload("Project").Where("project_id = ? AND id = ?", projectID, logID).First(&requestLog)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &requestLog, nil
+}
+
 // Helper functions
 
 // findBestPathMatch finds the best matching endpoint from a list of endpoints
 // Supporting various path patterns:
 // 1. Exact match: /users/123
-// 2. Path parameters: /users/:id
-// 3. Wildcard: /api/v2/customer_rooms/*/broadcast_history
+// 2. Path parameters: /users/:id or /users/{id}
+// 3. Wildcard: /api/v2/customer_rooms/*/broadcast_history (* matches exactly one segment)
 // 4. Regex: /api/v\d+/users/\d+
-func findBestPathMatch(endpoints []database.MockEndpoint, requestPath string) *database.MockEndpoint {
+// 5. Double wildcard: /assets/** (** matches any number of segments, including none)
+// A static path equal to the request path always wins, however many segments a pattern matches;
+// otherwise precedence is exact > path parameters > single wildcard > double wildcard. The query
+// string is not part of the match.
+func findBestPathMatch(endpoints []database.MockEndpoint, requestPath string, opts PathMatchOptions) *database.MockEndpoint {
+	if i := strings.IndexByte(requestPath, '?'); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	for i := range endpoints {
+		if opts.sameSegment(strings.Trim(endpoints[i].Path, "/"), strings.Trim(requestPath, "/")) {
+			return &endpoints[i]
+		}
+	}
+
 	var bestMatch *database.MockEndpoint
 	bestScore := -1
 
 	for i := range endpoints {
 		endpoint := &endpoints[i]
-		
-		if score := calculatePathMatchScore(endpoint.Path, requestPath); score > bestScore {
+
+		if score := calculatePathMatchScore(endpoint.Path, requestPath, opts); score > bestScore {
 			bestScore = score
 			bestMatch = endpoint
 		}
@@ -107,26 +216,27 @@ func findBestPathMatch(endpoints []database.MockEndpoint, requestPath string) *d
 // - Path parameters with : (score: 80)
 // - Wildcard patterns with * (score: 60)
 // - Regex patterns (score: 40)
-func calculatePathMatchScore(endpointPath, requestPath string) int {
+// - Double wildcard patterns with ** (score: 20)
+func calculatePathMatchScore(endpointPath, requestPath string, opts PathMatchOptions) int {
 	// Clean paths
 	endpointPath = strings.Trim(endpointPath, "/")
 	requestPath = strings.Trim(requestPath, "/")
 	
 	// Exact match gets highest priority
-	if endpointPath == requestPath {
+	if opts.sameSegment(endpointPath, requestPath) {
 		return 100
 	}
 	
 	// Check for regex pattern (contains regex metacharacters)
 	if isRegexPattern(endpointPath) {
-		if matchesRegex(endpointPath, requestPath) {
+		if matchesRegex(endpointPath, requestPath, opts) {
 			return 40
 		}
 		return -1
 	}
 	
 	// Check for wildcard or path parameter patterns
-	return calculateSegmentMatchScore(endpointPath, requestPath)
+	return calculateSegmentMatchScore(endpointPath, requestPath, opts)
 }
 
 // isRegexPattern checks if a path contains regex metacharacters
@@ -142,9 +252,9 @@ func isRegexPattern(path string) bool {
 }
 
 // matchesRegex tests if request path matches the regex pattern
-func matchesRegex(pattern, requestPath string) bool {
+func matchesRegex(pattern, requestPath string, opts PathMatchOptions) bool {
 	// Compile and test regex
-	regex, err := regexp.Compile("^" + pattern + "$")
+	regex, err := opts.compile(pattern)
 	if err != nil {
 		return false
 	}
@@ -152,9 +262,18 @@ func matchesRegex(pattern, requestPath string) bool {
 }
 
 // calculateSegmentMatchScore handles path parameters and wildcard matching
-func calculateSegmentMatchScore(endpointPath, requestPath string) int {
+func calculateSegmentMatchScore(endpointPath, requestPath string, opts PathMatchOptions) int {
 	endpointParts := strings.Split(endpointPath, "/")
-	requestParts := strings.Split(requestPath, "/")
+	requestParts := splitPath(requestPath)
+
+	// ** spans a variable number of segments, so it's matched separately
+	if hasDoubleWildcard(endpointParts) {
+		score, ok := matchGlobSegments(endpointParts, requestParts, opts, nil)
+		if !ok {
+			return -1
+		}
+		return 20 + score
+	}
 	
 	// If lengths don't match, this can't be a match
 	if len(endpointParts) != len(requestParts) {
@@ -170,13 +289,13 @@ func calculateSegmentMatchScore(endpointPath, requestPath string) int {
 		requestPart := requestParts[i]
 		
 		// Exact match of path part (highest score)
-		if endpointPart == requestPart {
+		if opts.sameSegment(endpointPart, requestPart) {
 			score += 10
 			continue
 		}
 		
-		// Path parameter (starts with :)
-		if strings.HasPrefix(endpointPart, ":") {
+		// Path parameter (:name or {name})
+		if _, ok := pathParamName(endpointPart); ok {
 			score += 8
 			paramCount++
 			continue
@@ -203,6 +322,131 @@ func calculateSegmentMatchScore(endpointPath, requestPath string) int {
 	return score // Should not reach here for valid matches
 }
 
+// splitPath splits a trimmed path into segments; the root path has none
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// hasDoubleWildcard reports whether any segment is **
+func hasDoubleWildcard(parts []string) bool {
+	for _, part := range parts {
+		if part == "**" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches request segments against endpoint segments where ** spans zero or
+// more segments, returning the same per-segment score as calculateSegmentMatchScore. When
+// params is non-nil it receives the values captured by :name and {name} segments.
+func matchGlobSegments(pattern, parts []string, opts PathMatchOptions, params map[string]string) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(parts); skip++ {
+			if score, ok := matchGlobSegments(pattern[1:], parts[skip:], opts, params); ok {
+				return score, true
+			}
+		}
+		return 0, false
+	}
+	if len(parts) == 0 {
+		return 0, false
+	}
+
+	var score int
+	name, isParam := pathParamName(pattern[0])
+	switch {
+	case opts.sameSegment(pattern[0], parts[0]):
+		score = 10
+	case isParam:
+		score = 8
+	case pattern[0] == "*":
+		score = 6
+	default:
+		return 0, false
+	}
+	rest, ok := matchGlobSegments(pattern[1:], parts[1:], opts, params)
+	if !ok {
+		return 0, false
+	}
+	if isParam && params != nil {
+		params[name] = parts[0]
+	}
+	return score + rest, true
+}
+
+// pathParamName returns the parameter name of a :name or {name} path segment
+func pathParamName(segment string) (string, bool) {
+	if strings.HasPrefix(segment, ":") && len(segment) > 1 {
+		return segment[1:], true
+	}
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && len(segment) > 2 {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+// ExtractPathParams returns the request path values captured by an endpoint path: the segments
+// at :name and {name} placeholders, or the named groups of a regex path such as
+// /orders/(?P<id>\d+). Paths that don't match capture nothing.
+func ExtractPathParams(endpointPath, requestPath string, opts PathMatchOptions) map[string]string {
+	params := map[string]string{}
+	endpointPath = strings.Trim(endpointPath, "/")
+	requestPath = strings.Trim(requestPath, "/")
+
+	if isRegexPattern(endpointPath) {
+		regex, err := opts.compile(endpointPath)
+		if err != nil {
+			return params
+		}
+		match := regex.FindStringSubmatch(requestPath)
+		for i, name := range regex.SubexpNames() {
+			if match != nil && name != "" {
+				params[name] = match[i]
+			}
+		}
+		return params
+	}
+
+	endpointParts := strings.Split(endpointPath, "/")
+	requestParts := splitPath(requestPath)
+	if hasDoubleWildcard(endpointParts) {
+		matchGlobSegments(endpointParts, requestParts, opts, params)
+		return params
+	}
+	if len(endpointParts) != len(requestParts) {
+		return params
+	}
+	for i, part := range endpointParts {
+		if name, ok := pathParamName(part); ok {
+			params[name] = requestParts[i]
+		}
+	}
+	return params
+}
+
+// sameSegment compares static path text
+func (o PathMatchOptions) sameSegment(endpointPart, requestPart string) bool {
+	if o.CaseInsensitive {
+		return strings.EqualFold(endpointPart, requestPart)
+	}
+	return endpointPart == requestPart
+}
+
+// compile anchors a regex endpoint path to the whole request path
+func (o PathMatchOptions) compile(pattern string) (*regexp.Regexp, error) {
+	if o.CaseInsensitive {
+		return regexp.Compile("(?i)^" + pattern + "$")
+	}
+	return regexp.Compile("^" + pattern + "$")
+}
+
 // ParseHeaders converts a JSON string to a map of headers
 func ParseHeaders(headersJSON string) (map[string]string, error) {
 	headers := make(map[string]string)
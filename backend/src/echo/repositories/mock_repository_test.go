@@ -6,6 +6,7 @@ import (
 	"beo-echo/backend/src/database"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculatePathMatchScore(t *testing.T) {
@@ -136,7 +137,7 @@ func TestCalculatePathMatchScore(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			score := calculatePathMatchScore(tt.endpointPath, tt.requestPath)
+			score := calculatePathMatchScore(tt.endpointPath, tt.requestPath, PathMatchOptions{})
 			assert.Equal(t, tt.expectedScore, score, tt.description)
 		})
 	}
@@ -228,7 +229,7 @@ func TestFindBestPathMatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			match := findBestPathMatch(endpoints, tt.requestPath)
+			match := findBestPathMatch(endpoints, tt.requestPath, PathMatchOptions{})
 			if tt.expectedEndpointID == "" {
 				assert.Nil(t, match, tt.description)
 			} else {
@@ -342,8 +343,110 @@ func TestMatchesRegex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := matchesRegex(tt.pattern, tt.requestPath)
+			result := matchesRegex(tt.pattern, tt.requestPath, PathMatchOptions{})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestFindBestPathMatch_StaticPrecedence(t *testing.T) {
+	endpoints := []database.MockEndpoint{
+		{ID: "param", Path: "/api/v2/users/:id/settings"},
+		{ID: "static", Path: "/api/v2/users/me/settings"},
+		{ID: "braces", Path: "/users/{id}/orders/{orderId}"},
+	}
+
+	// The parameterized path scores 128 against the exact match's 100, but static paths win
+	assert.Equal(t, "static", findBestPathMatch(endpoints, "/api/v2/users/me/settings", PathMatchOptions{}).ID)
+	assert.Equal(t, "param", findBestPathMatch(endpoints, "/api/v2/users/42/settings", PathMatchOptions{}).ID)
+	assert.Equal(t, "braces", findBestPathMatch(endpoints, "/users/7/orders/ord-1", PathMatchOptions{}).ID)
+}
+
+func TestExtractPathParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		endpointPath string
+		requestPath  string
+		expected     map[string]string
+	}{
+		{"colon placeholders", "/users/:id/orders/:orderId", "/users/42/orders/ord-7", map[string]string{"id": "42", "orderId": "ord-7"}},
+		{"brace placeholders", "/users/{id}/orders/{orderId}/", "/users/42/orders/ord-7", map[string]string{"id": "42", "orderId": "ord-7"}},
+		{"regex named groups", `/orders/(?P<id>\d+)`, "/orders/99", map[string]string{"id": "99"}},
+		{"static path", "/users/me", "/users/me", map[string]string{}},
+		{"different length", "/users/:id", "/users/42/orders", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExtractPathParams(tt.endpointPath, tt.requestPath, PathMatchOptions{}))
+		})
+	}
+}
+
+func TestFindBestPathMatch_Wildcards(t *testing.T) {
+	endpoints := []database.MockEndpoint{
+		{ID: "catch-all", Path: "/**"},
+		{ID: "assets-tree", Path: "/assets/**"},
+		{ID: "assets-one", Path: "/assets/*"},
+		{ID: "assets-logo", Path: "/assets/logo.png"},
+		{ID: "images-tree", Path: "/assets/images/**"},
+		{ID: "nested-tail", Path: "/files/**/download"},
+	}
+
+	tests := []struct {
+		name        string
+		requestPath string
+		expectedID  string
+	}{
+		{"exact beats wildcards", "/assets/logo.png", "assets-logo"},
+		{"single wildcard beats double", "/assets/app.js", "assets-one"},
+		{"double wildcard spans segments", "/assets/fonts/inter/regular.woff2", "assets-tree"},
+		{"longer double wildcard prefix wins", "/assets/images/icons/home.svg", "images-tree"},
+		{"double wildcard matches no segments", "/assets", "assets-tree"},
+		{"double wildcard in the middle", "/files/a/b/download", "nested-tail"},
+		{"catch-all for anything else", "/unknown/path", "catch-all"},
+		{"query string is ignored", "/assets/app.js?v=3", "assets-one"},
+		{"query string is ignored on exact paths", "/assets/logo.png?size=2x", "assets-logo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := findBestPathMatch(endpoints, tt.requestPath, PathMatchOptions{})
+			require.NotNil(t, match)
+			assert.Equal(t, tt.expectedID, match.ID)
+		})
+	}
+}
+
+func TestCalculatePathMatchScore_DoubleWildcard(t *testing.T) {
+	assert.Equal(t, 30, calculatePathMatchScore("/assets/**", "/assets/css/site.css", PathMatchOptions{}))
+	assert.Equal(t, 20, calculatePathMatchScore("/**", "/anything", PathMatchOptions{}))
+	assert.Equal(t, -1, calculatePathMatchScore("/assets/**", "/static/site.css", PathMatchOptions{}))
+	assert.Equal(t, -1, calculatePathMatchScore("/files/**/download", "/files/a/b", PathMatchOptions{}))
+	assert.Equal(t, map[string]string{"tenant": "acme"}, ExtractPathParams("/:tenant/assets/**", "/acme/assets/img/logo.png", PathMatchOptions{}))
+}
+
+func TestFindBestPathMatch_CaseInsensitive(t *testing.T) {
+	endpoints := []database.MockEndpoint{
+		{ID: "static", Path: "/users/me"},
+		{ID: "param", Path: "/users/:id/Orders"},
+		{ID: "regex", Path: `/reports/\d+`},
+		{ID: "tree", Path: "/assets/**"},
+	}
+	relaxed := PathMatchOptions{CaseInsensitive: true}
+
+	for requestPath, expectedID := range map[string]string{
+		"/Users/ME":         "static",
+		"/USERS/42/orders":  "param",
+		"/REPORTS/7":        "regex",
+		"/Assets/img/a.png": "tree",
+	} {
+		assert.Nil(t, findBestPathMatch(endpoints, requestPath, PathMatchOptions{}), requestPath)
+		match := findBestPathMatch(endpoints, requestPath, relaxed)
+		require.NotNil(t, match, requestPath)
+		assert.Equal(t, expectedID, match.ID, requestPath)
+	}
+
+	// Captured values keep the case the client sent
+	assert.Equal(t, map[string]string{"id": "AbC"}, ExtractPathParams("/users/:id/Orders", "/USERS/AbC/orders", relaxed))
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// autoMethodsFor reports whether HEAD and OPTIONS are answered for paths without such an
+// endpoint (autoHead / autoOptions, both on unless disabled)
+func autoMethodsFor(project *database.Project) (head, options bool) {
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil {
+		return true, true
+	}
+	head = config.AutoHead == nil || *config.AutoHead
+	options = config.AutoOptions == nil || *config.AutoOptions
+	return head, options
+}
+
+// handleAutoMethod answers a HEAD or OPTIONS request that matched no endpoint of its own: HEAD
+// runs the matching GET endpoint and drops the body, OPTIONS lists the methods defined for the
+// path in an Allow header. It returns nil when the request isn't auto-handled.
+func (s *MockService) handleAutoMethod(ctx context.Context, project *database.Project, method, path string, req *http.Request) (*http.Response, error, bool) {
+	autoHead, autoOptions := autoMethodsFor(project)
+
+	switch strings.ToUpper(method) {
+	case http.MethodHead:
+		if !autoHead {
+			return nil, nil, false
+		}
+		if _, err := s.findEndpoint(project, http.MethodGet, path); err != nil {
+			return nil, nil, false
+		}
+		resp, err, _, matched := s.handleMockMode(ctx, project, http.MethodGet, path, req)
+		if err != nil {
+			return nil, err, false
+		}
+		stripResponseBody(resp)
+		return resp, nil, matched
+
+	case http.MethodOptions:
+		if !autoOptions {
+			return nil, nil, false
+		}
+		methods, err := s.Repo.FindMethodsForPath(project.ID, path, pathMatchOptionsFor(project))
+		if err != nil || len(methods) == 0 {
+			return nil, nil, false
+		}
+		return createAllowResponse(methods, autoHead), nil, true
+	}
+	return nil, nil, false
+}
+
+// stripResponseBody drops the body of a GET response answered to HEAD, keeping its Content-Length
+func stripResponseBody(resp *http.Response) {
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+	if resp.ContentLength >= 0 && resp.Header.Get("Content-Length") == "" {
+		resp.Header.Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	resp.Body = http.NoBody
+}
+
+// createAllowResponse builds the 204 answer to OPTIONS. HEAD is listed alongside GET when it is
+// auto-handled, and OPTIONS itself is always allowed.
+func createAllowResponse(methods []string, autoHead bool) *http.Response {
+	allowed := map[string]bool{http.MethodOptions: true}
+	for _, method := range methods {
+		allowed[method] = true
+		if method == http.MethodGet && autoHead {
+			allowed[http.MethodHead] = true
+		}
+	}
+
+	allow := make([]string, 0, len(allowed))
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions} {
+		if allowed[method] {
+			allow = append(allow, method)
+			delete(allowed, method)
+		}
+	}
+	for _, method := range methods {
+		if allowed[method] {
+			allow = append(allow, method)
+		}
+	}
+
+	resp := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       http.NoBody,
+		Header:     make(http.Header),
+	}
+	resp.Header.Set("Allow", strings.Join(allow, ", "))
+	return resp
+}
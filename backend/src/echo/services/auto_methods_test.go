@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_AutoHeadAndOptions(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "auto-methods")
+
+	get, err := database.CreateTestEndpoint(project.ID, "GET", "/users/:id")
+	require.NoError(t, err)
+	createTestResponse(t, get.ID, database.MockResponse{StatusCode: 200, Body: `{"id":1}`, Headers: `{"Content-Type": "application/json"}`})
+	put, err := database.CreateTestEndpoint(project.ID, "PUT", "/users/:id")
+	require.NoError(t, err)
+	createTestResponse(t, put.ID, database.MockResponse{StatusCode: 204})
+	post, err := database.CreateTestEndpoint(project.ID, "POST", "/users")
+	require.NoError(t, err)
+	createTestResponse(t, post.ID, database.MockResponse{StatusCode: 201})
+
+	send := func(method, path string) (*http.Response, bool) {
+		req := httptest.NewRequest(method, "/auto-methods"+path, nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, method, path, req)
+		require.NoError(t, err)
+		return resp, matched
+	}
+
+	t.Run("HEAD runs the GET endpoint without a body", func(t *testing.T) {
+		resp, matched := send(http.MethodHead, "/users/1")
+		assert.True(t, matched)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "8", resp.Header.Get("Content-Length"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Empty(t, body)
+	})
+
+	t.Run("OPTIONS lists the path's methods", func(t *testing.T) {
+		resp, matched := send(http.MethodOptions, "/users/1")
+		assert.True(t, matched)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "GET, HEAD, PUT, OPTIONS", resp.Header.Get("Allow"))
+
+		resp, _ = send(http.MethodOptions, "/users")
+		assert.Equal(t, "POST, OPTIONS", resp.Header.Get("Allow"))
+	})
+
+	t.Run("Unknown paths are still not found", func(t *testing.T) {
+		_, matched := send(http.MethodOptions, "/orders")
+		assert.False(t, matched)
+		_, matched = send(http.MethodHead, "/users")
+		assert.False(t, matched)
+	})
+
+	t.Run("Explicit endpoints win", func(t *testing.T) {
+		options, err := database.CreateTestEndpoint(project.ID, "OPTIONS", "/users")
+		require.NoError(t, err)
+		createTestResponse(t, options.ID, database.MockResponse{StatusCode: 200, Body: "custom"})
+
+		resp, matched := send(http.MethodOptions, "/users")
+		assert.True(t, matched)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Allow"))
+	})
+
+	t.Run("Disabled per project", func(t *testing.T) {
+		project.AdvanceConfig = `{"autoHead": false, "autoOptions": false}`
+		require.NoError(t, database.DB.Save(project).Error)
+
+		_, matched := send(http.MethodHead, "/users/1")
+		assert.False(t, matched)
+		_, matched = send(http.MethodOptions, "/users/1")
+		assert.False(t, matched)
+	})
+}
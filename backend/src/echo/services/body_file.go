@@ -0,0 +1,111 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/lib"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// responseFilesDir returns the directory body_file_path values are resolved in: RESPONSE_FILES_DIR,
+// or configs/response-files when it is unset
+func responseFilesDir() string {
+	if database.DB != nil {
+		if dir, err := systemConfig.GetSystemConfigWithType[string](systemConfig.RESPONSE_FILES_DIR); err == nil && strings.TrimSpace(dir) != "" {
+			return filepath.Clean(dir)
+		}
+	}
+	return filepath.Join(lib.CONFIGS_DIR, "response-files")
+}
+
+// resolveResponseFilePath joins a body_file_path onto the response files directory, rejecting
+// absolute paths and paths that climb out of it with ".."
+func resolveResponseFilePath(bodyFilePath string) (string, error) {
+	if filepath.IsAbs(bodyFilePath) {
+		return "", fmt.Errorf("body file path %q must be relative to the response files directory", bodyFilePath)
+	}
+
+	base := responseFilesDir()
+	path := filepath.Join(base, bodyFilePath)
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("body file path %q leaves the response files directory", bodyFilePath)
+	}
+	return path, nil
+}
+
+// fileStage loads a response's body_file_path in place of its inline body. When no later stage
+// rewrites the body the file is streamed as-is with its size as Content-Length; otherwise it is
+// read into memory so schema, transforms, encodings and faults still apply. A file that cannot
+// be opened turns the response into a 500 error naming the path.
+func fileStage(b *responseBuild) error {
+	if b.mock.BodyFilePath == "" {
+		return nil
+	}
+
+	path, err := resolveResponseFilePath(b.mock.BodyFilePath)
+	if err != nil {
+		b.failBodyFile(err.Error())
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println("Error opening response body file:", err)
+		b.failBodyFile(fmt.Sprintf("Response body file not found: %s", b.mock.BodyFilePath))
+		return nil
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		b.failBodyFile(fmt.Sprintf("Response body file not found: %s", b.mock.BodyFilePath))
+		return nil
+	}
+
+	if b.streamsBodyFile() {
+		b.file = file
+		b.fileSize = info.Size()
+		return nil
+	}
+
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("reading body file %s: %w", b.mock.BodyFilePath, err)
+	}
+	b.body = string(data)
+	return nil
+}
+
+// streamsBodyFile reports whether the body file can be sent untouched: no schema, transform,
+// encoding or compression fault is configured
+func (b *responseBuild) streamsBodyFile() bool {
+	return len(b.config.BodySchema) == 0 &&
+		b.config.JSONFormat == "" &&
+		!b.config.InjectSyntaxError &&
+		b.config.TruncateBodyAt <= 0 &&
+		b.config.CompressionFault == "" &&
+		len(parseContentEncodings(b.header("Content-Encoding"))) == 0
+}
+
+// failBodyFile replaces the build with a plain 500 JSON error, dropping the response's headers,
+// cookies, redirect and advance config so nothing else is applied to it
+func (b *responseBuild) failBodyFile(message string) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error":   true,
+		"message": message,
+	})
+	b.mock.StatusCode = http.StatusInternalServerError
+	b.mock.Cookies = ""
+	b.mock.RedirectURL = ""
+	b.headers = map[string]string{"Content-Type": "application/json"}
+	b.config = &database.AdvanceConfigResponse{}
+	b.body = string(body)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// setupResponseFilesDir points RESPONSE_FILES_DIR at a temp directory holding the given files
+func setupResponseFilesDir(t *testing.T, files map[string]string) string {
+	database.SetupTestEnvironment(t)
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.RESPONSE_FILES_DIR, dir))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.RESPONSE_FILES_DIR, "") })
+	return dir
+}
+
+func TestCreateMockResponse_BodyFileStreamed(t *testing.T) {
+	content := `{"items":[1,2,3]}`
+	setupResponseFilesDir(t, map[string]string{"fixtures/items.json": content})
+
+	resp, err := createMockResponse(database.MockResponse{
+		StatusCode:   200,
+		Body:         "inline body is ignored",
+		BodyFilePath: "fixtures/items.json",
+		Headers:      `{"Content-Type": "application/json"}`,
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, int64(len(content)), resp.ContentLength)
+	_, isFile := resp.Body.(*os.File)
+	assert.True(t, isFile, "body should stream from the file")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(body))
+}
+
+func TestCreateMockResponse_BodyFileCompressed(t *testing.T) {
+	content := `{"message":"from a file"}`
+	setupResponseFilesDir(t, map[string]string{"body.json": content})
+
+	resp, err := createMockResponse(database.MockResponse{
+		StatusCode:   200,
+		BodyFilePath: "body.json",
+		Headers:      `{"Content-Type": "application/json", "Content-Encoding": "gzip"}`,
+	})
+	require.NoError(t, err)
+
+	encoded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(encoded)), resp.ContentLength)
+
+	reader, err := gzip.NewReader(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(decoded))
+}
+
+func TestCreateMockResponse_BodyFileMissing(t *testing.T) {
+	setupResponseFilesDir(t, nil)
+
+	resp, err := createMockResponse(database.MockResponse{
+		StatusCode:   200,
+		BodyFilePath: "missing.json",
+		Headers:      `{"Content-Type": "text/plain", "X-Custom": "dropped"}`,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Empty(t, resp.Header.Get("X-Custom"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, true, body["error"])
+	assert.Contains(t, body["message"], "missing.json")
+}
+
+func TestResolveResponseFilePath(t *testing.T) {
+	dir := setupResponseFilesDir(t, nil)
+
+	path, err := resolveResponseFilePath("nested/../body.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "body.json"), path)
+
+	for _, escaping := range []string{"../secret.txt", "nested/../../secret.txt", "/etc/passwd"} {
+		_, err := resolveResponseFilePath(escaping)
+		assert.Error(t, err, escaping)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// bodyHashAlgorithm returns the hash constructor for a body_hash rule key
+func bodyHashAlgorithm(name string) (func() hash.Hash, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha512":
+		return sha512.New, nil
+	case "md5":
+		return md5.New, nil
+	default:
+		return nil, fmt.Errorf("body_hash algorithm must be sha256, sha1, sha512, or md5")
+	}
+}
+
+// matchBodyHashRule hashes the raw request body with the algorithm named by the rule key
+// (sha256 by default) and compares the hex digest with the rule value, case-insensitively.
+// An empty body hashes like any other, so the digest of "" can be matched too.
+func matchBodyHashRule(rule database.MockRule, req *http.Request) bool {
+	newHash, err := bodyHashAlgorithm(rule.Key)
+	if err != nil {
+		return false
+	}
+
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil {
+		return false
+	}
+
+	digest := newHash()
+	digest.Write(bodyBytes)
+	return strings.EqualFold(hex.EncodeToString(digest.Sum(nil)), strings.TrimSpace(rule.Value))
+}
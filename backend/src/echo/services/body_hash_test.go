@@ -0,0 +1,39 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchBodyHashRule(t *testing.T) {
+	// sha256("hello") and md5("hello")
+	const sha256Hello = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	const md5Hello = "5d41402abc4b2a76b9719d911017c592"
+
+	newReq := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	}
+
+	assert.True(t, matchBodyHashRule(database.MockRule{Type: "body_hash", Value: sha256Hello}, newReq("hello")))
+	assert.True(t, matchBodyHashRule(database.MockRule{Type: "body_hash", Value: strings.ToUpper(sha256Hello)}, newReq("hello")), "digest comparison ignores case")
+	assert.True(t, matchBodyHashRule(database.MockRule{Type: "body_hash", Key: "md5", Value: md5Hello}, newReq("hello")))
+	assert.False(t, matchBodyHashRule(database.MockRule{Type: "body_hash", Value: sha256Hello}, newReq("hello!")))
+	assert.False(t, matchBodyHashRule(database.MockRule{Type: "body_hash", Key: "crc32", Value: sha256Hello}, newReq("hello")), "unknown algorithms never match")
+
+	t.Run("Body stays readable", func(t *testing.T) {
+		req := newReq("hello")
+		matchBodyHashRule(database.MockRule{Type: "body_hash", Value: sha256Hello}, req)
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+	})
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+)
+
+// maxBodySizeProbe caps how much of a body without Content-Length is read to measure it;
+// larger bodies are reported as this size
+const maxBodySizeProbe = 10 << 20 // 10MB
+
+// requestBodySize returns the request body size in bytes. Content-Length is trusted when known;
+// chunked bodies are read up to maxBodySizeProbe and restored so later reads see the full body.
+func requestBodySize(req *http.Request) int64 {
+	if req.ContentLength >= 0 {
+		return req.ContentLength
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return 0
+	}
+
+	probe, err := io.ReadAll(io.LimitReader(req.Body, maxBodySizeProbe))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(probe), req.Body), req.Body}
+	if err != nil {
+		return 0
+	}
+	return int64(len(probe))
+}
+
+// filterResponsesByBodySize drops responses whose bodySize range excludes the request body size.
+// Responses without a range always stay; when nothing fits the list is returned unchanged.
+func filterResponsesByBodySize(responses []database.MockResponse, req *http.Request) []database.MockResponse {
+	if req == nil {
+		return responses
+	}
+
+	size := int64(-1) // measured lazily, only when a response declares a range
+	var fitting []database.MockResponse
+	for _, response := range responses {
+		bodySize := responseBodySizeRange(response)
+		if bodySize == nil {
+			fitting = append(fitting, response)
+			continue
+		}
+		if size < 0 {
+			size = requestBodySize(req)
+		}
+		if size >= bodySize.Min && (bodySize.Max == 0 || size <= bodySize.Max) {
+			fitting = append(fitting, response)
+		}
+	}
+
+	if len(fitting) == 0 {
+		return responses
+	}
+	return fitting
+}
+
+// responseBodySizeRange returns the bodySize range of a response, nil when not configured
+func responseBodySizeRange(response database.MockResponse) *database.BodySizeRange {
+	if response.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err != nil {
+		return nil
+	}
+	return config.BodySize
+}
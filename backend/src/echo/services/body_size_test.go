@@ -0,0 +1,43 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestSelectResponseWithEndpoint_BodySize(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "accepted", StatusCode: 200, AdvanceConfig: `{"bodySize": {"max": 16}}`},
+		{ID: "too-large", StatusCode: 413, AdvanceConfig: `{"bodySize": {"min": 17}}`},
+	}
+
+	t.Run("Content-Length selects the range", func(t *testing.T) {
+		small := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("tiny"))
+		large := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 64)))
+
+		assert.Equal(t, "accepted", selectResponseWithEndpoint("endpoint-size", responses, "static", small).ID)
+		assert.Equal(t, "too-large", selectResponseWithEndpoint("endpoint-size", responses, "static", large).ID)
+	})
+
+	t.Run("Chunked body is measured and restored", func(t *testing.T) {
+		payload := strings.Repeat("y", 32)
+		req := httptest.NewRequest(http.MethodPost, "/upload", io.NopCloser(strings.NewReader(payload)))
+		req.ContentLength = -1
+
+		selected := selectResponseWithEndpoint("endpoint-size", responses, "static", req)
+		require.NotNil(t, selected)
+		assert.Equal(t, "too-large", selected.ID)
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(body))
+	})
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// transformRequestBody applies the endpoint's body transforms and returns the request that
+// rule matching (and response building) should see. The original request is left untouched
+// so proxying still forwards the body exactly as the client sent it.
+func transformRequestBody(endpoint *database.MockEndpoint, req *http.Request) *http.Request {
+	if endpoint == nil || endpoint.AdvanceConfig == "" || req == nil || req.Body == nil {
+		return req
+	}
+
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil || len(config.BodyTransforms) == 0 {
+		return req
+	}
+
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil {
+		return req
+	}
+
+	transformed := applyBodyTransforms(bodyBytes, config.BodyTransforms)
+
+	matchReq := req.Clone(req.Context())
+	matchReq.Body = io.NopCloser(bytes.NewReader(transformed))
+	matchReq.ContentLength = int64(len(transformed))
+	// The clone inherits the original body cache, so cache the transformed body instead
+	return withRequestBody(matchReq)
+}
+
+// applyBodyTransforms runs each transform in order. A step that fails to decode leaves
+// the body as it was so later steps (and rules) still get a usable payload.
+func applyBodyTransforms(body []byte, transforms []database.BodyTransform) []byte {
+	for _, transform := range transforms {
+		switch transform.Type {
+		case database.BodyTransformBase64Decode:
+			if decoded, ok := decodeBase64(string(body)); ok {
+				body = decoded
+			}
+		case database.BodyTransformURLDecode:
+			if decoded, err := url.QueryUnescape(string(body)); err == nil {
+				body = []byte(decoded)
+			}
+		case database.BodyTransformUnwrap:
+			var data map[string]interface{}
+			if err := json.Unmarshal(body, &data); err == nil {
+				if value := getNestedValue(data, transform.Field); value != "" {
+					body = []byte(value)
+				}
+			}
+		}
+	}
+	return body
+}
+
+// decodeBase64 accepts standard and URL-safe alphabets, with or without padding
+func decodeBase64(value string) ([]byte, bool) {
+	value = strings.TrimSpace(value)
+	encodings := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, encoding := range encodings {
+		if decoded, err := encoding.DecodeString(value); err == nil {
+			return decoded, true
+		}
+	}
+	return nil, false
+}
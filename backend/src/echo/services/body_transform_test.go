@@ -0,0 +1,61 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestApplyBodyTransforms(t *testing.T) {
+	t.Run("Base64 then unwrap envelope", func(t *testing.T) {
+		// {"data":{"user":"alice"}} base64 encoded
+		body := []byte("eyJkYXRhIjp7InVzZXIiOiJhbGljZSJ9fQ==")
+		transforms := []database.BodyTransform{
+			{Type: database.BodyTransformBase64Decode},
+			{Type: database.BodyTransformUnwrap, Field: "data"},
+		}
+
+		assert.Equal(t, `{"user":"alice"}`, string(applyBodyTransforms(body, transforms)))
+	})
+
+	t.Run("URL decode", func(t *testing.T) {
+		body := []byte("%7B%22id%22%3A7%7D")
+		transforms := []database.BodyTransform{{Type: database.BodyTransformURLDecode}}
+
+		assert.Equal(t, `{"id":7}`, string(applyBodyTransforms(body, transforms)))
+	})
+
+	t.Run("Failed step keeps previous body", func(t *testing.T) {
+		body := []byte("not base64 !!")
+		transforms := []database.BodyTransform{{Type: database.BodyTransformBase64Decode}}
+
+		assert.Equal(t, "not base64 !!", string(applyBodyTransforms(body, transforms)))
+	})
+}
+
+func TestTransformRequestBody(t *testing.T) {
+	endpoint := &database.MockEndpoint{
+		AdvanceConfig: `{"bodyTransforms":[{"type":"base64_decode"}]}`,
+	}
+	// {"user":{"role":"admin"}}
+	original := "eyJ1c2VyIjp7InJvbGUiOiJhZG1pbiJ9fQ=="
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(original))
+
+	matchReq := transformRequestBody(endpoint, req)
+
+	// Rules see the decoded body
+	rule := database.MockRule{Type: "body", Key: "user.role", Operator: "equals", Value: "admin"}
+	assert.True(t, matchBodyRule(rule, matchReq))
+
+	// The original request body is preserved for proxying
+	originalBody, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(originalBody))
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+)
+
+// statusClientClosedRequest is the non-standard 499 status (as used by nginx) for requests the
+// client abandoned before a response was produced
+const statusClientClosedRequest = 499
+
+// createCancelledResponse returns the response for a request whose context is already done,
+// nil while the request is still live. The client is gone, so the response mostly documents the
+// outcome for request logs; the project's cancelledResponse config overrides the built-in 499.
+func createCancelledResponse(ctx context.Context, project *database.Project) *http.Response {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	if project != nil && project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil && config.CancelledResponse != nil {
+			return createCustomResponse(config.CancelledResponse, statusClientClosedRequest, "Client closed request")
+		}
+	}
+
+	return createErrorResponse(statusClientClosedRequest, "Client closed request")
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// Circuit breaker states, sent in the beo-echo-circuit-state header
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// circuitBreakers holds the breaker of every proxy target a project forwarded to, keyed by
+// project ID and target. State lives in memory and starts closed after a restart.
+var circuitBreakers sync.Map
+
+// circuitBreaker tracks the consecutive failures of one proxy target
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // The half-open trial request is in flight
+}
+
+// circuitBreakerFor returns the breaker shared by requests of the project to the target
+func circuitBreakerFor(projectID string, target *database.ProxyTarget) *circuitBreaker {
+	key := projectID + "|" + target.ID
+	if target.ID == "" {
+		key = projectID + "|" + target.URL
+	}
+	breaker, _ := circuitBreakers.LoadOrStore(key, &circuitBreaker{state: circuitClosed})
+	return breaker.(*circuitBreaker)
+}
+
+// allow reports whether a request may be forwarded, and the state it is forwarded or rejected
+// in. Once the cooldown has passed one trial request goes through; the others keep getting the
+// fallback until it answers.
+func (b *circuitBreaker) allow(config *database.CircuitBreaker) (bool, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if nowFunc().Sub(b.openedAt) < time.Duration(config.CooldownMs)*time.Millisecond {
+			return false, circuitOpen
+		}
+		b.state, b.probing = circuitHalfOpen, true
+		return true, circuitHalfOpen
+	case circuitHalfOpen:
+		if b.probing {
+			return false, circuitHalfOpen
+		}
+		b.probing = true
+		return true, circuitHalfOpen
+	default:
+		return true, circuitClosed
+	}
+}
+
+// record counts the upstream status of a forwarded request and returns the new state. A
+// success closes the breaker; a failure in half-open or the threshold-th failure in a row opens it.
+func (b *circuitBreaker) record(config *database.CircuitBreaker, status int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if status < http.StatusInternalServerError {
+		b.state, b.failures, b.probing = circuitClosed, 0, false
+		return b.state
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= config.FailureThreshold {
+		b.state, b.openedAt, b.probing = circuitOpen, nowFunc(), false
+	}
+	return b.state
+}
+
+// createCircuitOpenResponse returns the configured fallback for requests the breaker rejects
+func createCircuitOpenResponse(config *database.CircuitBreaker, state string) *http.Response {
+	resp := createCustomResponse(config.FallbackResponse, http.StatusServiceUnavailable, "Circuit breaker open: upstream is failing")
+	resp.Header.Set("beo-echo-circuit-state", state)
+	return resp
+}
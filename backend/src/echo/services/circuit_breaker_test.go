@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestExecuteProxyRequest_CircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	project := &database.Project{
+		ID:            "circuit-breaker-project",
+		AdvanceConfig: `{"proxyCircuitBreaker": {"failureThreshold": 2, "cooldownMs": 1000, "fallbackResponse": {"body": "upstream down"}}}`,
+	}
+	target := &database.ProxyTarget{ID: "circuit-breaker-target", URL: upstream.URL}
+	t.Cleanup(func() { circuitBreakers.Delete(project.ID + "|" + target.ID) })
+
+	forward := func() (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		resp, err := executeProxyRequest(context.Background(), target.URL, http.MethodGet, "/orders", "", req, proxyOptionsFor(project).forTarget(target))
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, _ := forward()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "closed", resp.Header.Get("beo-echo-circuit-state"))
+
+	resp, _ = forward()
+	assert.Equal(t, "open", resp.Header.Get("beo-echo-circuit-state"), "the threshold-th failure opens the breaker")
+
+	t.Run("Open breaker answers without calling the upstream", func(t *testing.T) {
+		before := calls.Load()
+		resp, body := forward()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "upstream down", body)
+		assert.Equal(t, "open", resp.Header.Get("beo-echo-circuit-state"))
+		assert.Equal(t, before, calls.Load())
+	})
+
+	t.Run("A failed trial after the cooldown opens it again", func(t *testing.T) {
+		now = now.Add(time.Second)
+		before := calls.Load()
+		resp, _ := forward()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "open", resp.Header.Get("beo-echo-circuit-state"))
+		assert.Equal(t, before+1, calls.Load())
+
+		resp, _ = forward()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("A successful trial closes it", func(t *testing.T) {
+		now = now.Add(time.Second)
+		healthy.Store(true)
+
+		resp, body := forward()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ok", body)
+		assert.Equal(t, "closed", resp.Header.Get("beo-echo-circuit-state"))
+	})
+}
+
+func TestCircuitBreaker_SingleTrial(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+
+	config := &database.CircuitBreaker{FailureThreshold: 1, CooldownMs: 10}
+	breaker := &circuitBreaker{state: circuitClosed}
+	assert.Equal(t, circuitOpen, breaker.record(config, http.StatusBadGateway))
+
+	now = now.Add(10 * time.Millisecond)
+	allowed, state := breaker.allow(config)
+	assert.True(t, allowed)
+	assert.Equal(t, circuitHalfOpen, state)
+
+	allowed, state = breaker.allow(config)
+	assert.False(t, allowed, "only one trial runs at a time")
+	assert.Equal(t, circuitHalfOpen, state)
+
+	assert.Equal(t, circuitClosed, breaker.record(config, http.StatusNotFound), "4xx answers are not failures")
+}
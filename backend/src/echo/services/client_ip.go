@@ -0,0 +1,125 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// proxyTrusts caches the trust parsed from TRUSTED_PROXIES, keyed by the configured value
+var proxyTrusts sync.Map
+
+// proxyTrust is the set of peers allowed to name the client in forwarding headers
+type proxyTrust struct {
+	all      bool
+	networks []*net.IPNet
+}
+
+// trusts reports whether forwarding headers set by the peer are believed. Unknown peers are
+// only trusted when every peer is.
+func (t *proxyTrust) trusts(ip net.IP) bool {
+	if t.all {
+		return true
+	}
+	return ip != nil && networksContain(t.networks, ip)
+}
+
+// currentProxyTrust returns the TRUSTED_PROXIES setting, trusting every peer when it can't be read
+func currentProxyTrust() *proxyTrust {
+	value := "*"
+	if database.DB != nil {
+		if configured, err := systemConfig.GetSystemConfigWithType[string](systemConfig.TRUSTED_PROXIES); err == nil {
+			value = configured
+		}
+	}
+	if trust, ok := proxyTrusts.Load(value); ok {
+		return trust.(*proxyTrust)
+	}
+
+	trust := &proxyTrust{}
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "*" {
+			trust.all = true
+		}
+	}
+	trust.networks, _ = database.ParseNetworks(value)
+	proxyTrusts.Store(value, trust)
+	return trust
+}
+
+// clientIP returns the address of the client that sent the request. When the peer is a trusted
+// proxy (TRUSTED_PROXIES), X-Forwarded-For is read from the right, skipping trusted proxies, up
+// to the first address that isn't one; without it X-Real-IP names the client. Otherwise the
+// peer address is the client. Returns nil when none of them is a valid IP.
+func clientIP(req *http.Request) net.IP {
+	peer := peerIP(req)
+	trust := currentProxyTrust()
+	if !trust.trusts(peer) {
+		return peer
+	}
+
+	if forwarded := req.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		var client net.IP
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip
+			if !trust.trusts(ip) {
+				break
+			}
+		}
+		if client != nil {
+			return client
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); ip != nil {
+		return ip
+	}
+	return peer
+}
+
+// peerIP returns the address of the connection the request came in on
+func peerIP(req *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// matchIPRule compares the client address (see clientIP) with the rule value, e.g. with the
+// cidr operator to answer by source network. The key is ignored; unknown clients never match.
+func matchIPRule(rule database.MockRule, req *http.Request) bool {
+	ip := clientIP(req)
+	if ip == nil {
+		return false
+	}
+	return matchRuleValue(rule.Operator, ip.String(), rule.Value)
+}
+
+// matchRuleCIDR reports whether actual is an IP inside one of the comma-separated networks or
+// addresses in expected, e.g. "10.0.0.0/8, 192.168.1.7"
+func matchRuleCIDR(expected, actual string) bool {
+	ip := net.ParseIP(strings.TrimSpace(actual))
+	if ip == nil {
+		return false
+	}
+	networks, _ := database.ParseNetworks(expected)
+	return networksContain(networks, ip)
+}
+
+func networksContain(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
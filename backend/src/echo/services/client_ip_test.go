@@ -0,0 +1,77 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	database.SetupTestEnvironment(t)
+
+	newReq := func(peer, forwarded, realIP string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = peer + ":5000"
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		if realIP != "" {
+			req.Header.Set("X-Real-IP", realIP)
+		}
+		return req
+	}
+	trust := func(value string) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.TRUSTED_PROXIES, value))
+	}
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.TRUSTED_PROXIES, "*") })
+
+	t.Run("Every peer is trusted by default", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.10", clientIP(newReq("198.51.100.1", "203.0.113.10, 10.0.0.1", "")).String())
+		assert.Equal(t, "203.0.113.20", clientIP(newReq("198.51.100.1", "", "203.0.113.20")).String())
+		assert.Equal(t, "198.51.100.1", clientIP(newReq("198.51.100.1", "", "")).String())
+	})
+
+	t.Run("Untrusted peers can't name the client", func(t *testing.T) {
+		trust("10.0.0.0/8")
+		assert.Equal(t, "198.51.100.1", clientIP(newReq("198.51.100.1", "203.0.113.10", "203.0.113.20")).String())
+	})
+
+	t.Run("Trusted proxies are skipped from the right", func(t *testing.T) {
+		trust("10.0.0.0/8, 127.0.0.1")
+		assert.Equal(t, "198.51.100.7", clientIP(newReq("127.0.0.1", "203.0.113.10, 198.51.100.7, 10.0.0.2", "")).String(),
+			"a spoofed left-most entry is not believed")
+		assert.Equal(t, "203.0.113.20", clientIP(newReq("10.0.0.1", "", "203.0.113.20")).String())
+	})
+
+	t.Run("Empty trusts no one", func(t *testing.T) {
+		trust("")
+		assert.Equal(t, "127.0.0.1", clientIP(newReq("127.0.0.1", "203.0.113.10", "")).String())
+	})
+}
+
+func TestMatchIPRule(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.44:5000"
+	rule := func(operator, value string) database.MockRule {
+		return database.MockRule{Type: "ip", Operator: operator, Value: value}
+	}
+
+	assert.True(t, matchIPRule(rule("equals", "192.0.2.44"), req))
+	assert.True(t, matchIPRule(rule("cidr", "192.0.2.0/24"), req))
+	assert.True(t, matchIPRule(rule("cidr", "10.0.0.0/8, 192.0.2.44"), req), "bare IPs match exactly")
+	assert.False(t, matchIPRule(rule("cidr", "10.0.0.0/8"), req))
+
+	v6 := httptest.NewRequest(http.MethodGet, "/", nil)
+	v6.RemoteAddr = "[2001:db8::1]:5000"
+	assert.True(t, matchIPRule(rule("cidr", "2001:db8::/32"), v6))
+	assert.False(t, matchIPRule(rule("cidr", "192.0.2.0/24"), v6))
+
+	assert.True(t, matchRuleValue("cidr", "192.0.2.44", "192.0.2.0/24"), "cidr works for any rule value")
+	assert.False(t, matchRuleValue("cidr", "not-an-ip", "0.0.0.0/0"))
+}
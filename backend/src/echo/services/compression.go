@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// defaultResponseCompressionThreshold is the smallest built-in response body worth compressing;
+// below it the encoding overhead outweighs the savings
+const defaultResponseCompressionThreshold = 1024
+
+// supportedEncodings lists the content encodings beo-echo can produce, in server preference order
+var supportedEncodings = []string{"br", "gzip", "zstd", "deflate"}
+
+// compressBody encodes data with a supported content encoding
+func compressBody(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "br":
+		writer = brotli.NewWriter(&buf)
+	case "deflate":
+		// HTTP deflate is a zlib stream (RFC 9110), i.e. compress/flate data with a zlib header
+		writer = zlib.NewWriter(&buf)
+	case "zstd":
+		encoder, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		writer = encoder
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to %s compress response body: %w", encoding, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s writer: %w", encoding, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// parseContentEncodings splits a Content-Encoding header value into its encodings, in the order
+// they are applied (e.g. "gzip, br" is gzip first, then brotli). identity entries are dropped.
+func parseContentEncodings(value string) []string {
+	var encodings []string
+	for _, part := range strings.Split(value, ",") {
+		encoding := strings.ToLower(strings.TrimSpace(part))
+		if encoding != "" && encoding != "identity" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return encodings
+}
+
+// applyContentEncodings encodes data with each encoding in turn. ok is false, and data is left
+// as-is, when there are no encodings or one of them is not supported.
+func applyContentEncodings(encodings []string, data []byte) (encoded []byte, ok bool, err error) {
+	if len(encodings) == 0 {
+		return data, false, nil
+	}
+	for _, encoding := range encodings {
+		if !isSupportedEncoding(encoding) {
+			return data, false, nil
+		}
+	}
+
+	encoded = data
+	for _, encoding := range encodings {
+		if encoded, err = compressBody(encoding, encoded); err != nil {
+			return nil, false, err
+		}
+	}
+	return encoded, true, nil
+}
+
+func isSupportedEncoding(encoding string) bool {
+	for _, supported := range supportedEncodings {
+		if encoding == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedBody marks bodies of built-in responses (errors and defaults) so they can be
+// negotiated against the client's Accept-Encoding once the request is known
+type generatedBody struct {
+	*bytes.Reader
+	data []byte
+}
+
+func newGeneratedBody(data []byte) *generatedBody {
+	return &generatedBody{Reader: bytes.NewReader(data), data: data}
+}
+
+func (b *generatedBody) Close() error { return nil }
+
+// negotiateGeneratedResponse compresses a built-in response with the best encoding the client
+// accepts. Configured mock responses and proxied responses are left untouched.
+func negotiateGeneratedResponse(resp *http.Response, req *http.Request) {
+	if resp == nil || req == nil || resp.Header.Get("Content-Encoding") != "" {
+		return
+	}
+	body, ok := resp.Body.(*generatedBody)
+	if !ok || len(body.data) < defaultResponseCompressionThreshold {
+		return
+	}
+
+	encoding := negotiateEncoding(req.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		return
+	}
+	compressed, err := compressBody(encoding, body.data)
+	if err != nil || len(compressed) >= len(body.data) {
+		return
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(compressed))
+	resp.ContentLength = int64(len(compressed))
+	resp.Header.Set("Content-Encoding", encoding)
+	resp.Header.Add("Vary", "Accept-Encoding")
+}
+
+// negotiateEncoding picks the supported encoding with the highest q-value in an Accept-Encoding
+// header, using server preference order on ties. Returns "" when nothing acceptable matches.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supportedEncodings {
+		quality, listed := qualities[encoding]
+		if !listed {
+			quality, listed = qualities["*"]
+		}
+		if listed && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
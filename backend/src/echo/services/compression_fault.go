@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// Compression faults for testing client decompression error handling (fault injection only)
+const (
+	CompressionFaultSkip    = "skip"    // Declare the encoding but send the body uncompressed
+	CompressionFaultCorrupt = "corrupt" // Compress, then corrupt everything after the format header
+)
+
+// gzipHeaderSize is kept intact by the corrupt fault so clients recognise gzip and fail while inflating
+const gzipHeaderSize = 10
+
+// injectCompressionFault applies a compressionFault so the body no longer matches its
+// Content-Encoding. A gzip header is declared when the response has no Content-Encoding of its own.
+// raw is the body before encoding.
+func injectCompressionFault(fault string, resp *http.Response, raw []byte) error {
+	if fault == "" {
+		return nil
+	}
+
+	encoding := "gzip"
+	if encodings := parseContentEncodings(resp.Header.Get("Content-Encoding")); len(encodings) > 0 && isSupportedEncoding(encodings[0]) {
+		encoding = encodings[0]
+	}
+	if resp.Header.Get("Content-Encoding") == "" {
+		resp.Header.Set("Content-Encoding", encoding)
+	}
+
+	payload := raw
+	if fault == CompressionFaultCorrupt {
+		compressed, err := compressBody(encoding, raw)
+		if err != nil {
+			return err
+		}
+		payload = corruptCompressed(compressed)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(payload))
+	if resp.ContentLength >= 0 {
+		resp.ContentLength = int64(len(payload))
+	}
+	return nil
+}
+
+// corruptCompressed inverts every byte after the gzip header, leaving the header readable
+func corruptCompressed(data []byte) []byte {
+	corrupted := append([]byte(nil), data...)
+	for i := gzipHeaderSize; i < len(corrupted); i++ {
+		corrupted[i] ^= 0xFF
+	}
+	return corrupted
+}
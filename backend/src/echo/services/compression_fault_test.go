@@ -0,0 +1,51 @@
+package services
+
+import (
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_CompressionFault(t *testing.T) {
+	body := `{"message": "Hello World"}`
+
+	t.Run("Skip declares gzip without compressing", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			AdvanceConfig: `{"compressionFault": "skip"}`,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		data, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, body, string(data))
+		assert.Equal(t, int64(len(body)), resp.ContentLength)
+	})
+
+	t.Run("Corrupt keeps the gzip header but fails to inflate", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			Headers:       `{"Content-Encoding": "gzip"}`,
+			AdvanceConfig: `{"compressionFault": "corrupt"}`,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		reader, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err, "the gzip header is still valid")
+		_, err = io.ReadAll(reader)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown fault is rejected", func(t *testing.T) {
+		_, err := database.ParseResponseAdvanceConfig(`{"compressionFault": "garble"}`)
+		assert.Error(t, err)
+	})
+}
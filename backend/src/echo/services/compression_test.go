@@ -1,12 +1,18 @@
 package services
 
 import (
+	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -160,21 +166,77 @@ func TestCreateMockResponse_InvalidHeaders(t *testing.T) {
 	assert.Equal(t, mockResp.Body, string(bodyBytes))
 }
 
+func TestCreateMockResponse_DeflateCompression(t *testing.T) {
+	// Given - Mock response with deflate Content-Encoding header
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Body:       `{"message": "Hello World"}`,
+		Headers:    `{"Content-Type": "application/json", "Content-Encoding": "deflate"}`,
+	}
+
+	// When - Create HTTP response
+	resp, err := createMockResponse(mockResp)
+
+	// Then - Response should be zlib-wrapped deflate
+	require.NoError(t, err)
+	assert.Equal(t, "deflate", resp.Header.Get("Content-Encoding"))
+
+	compressedBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(compressedBytes)), resp.ContentLength)
+
+	zlibReader, err := zlib.NewReader(bytes.NewReader(compressedBytes))
+	require.NoError(t, err)
+	defer zlibReader.Close()
+
+	decompressedBytes, err := io.ReadAll(zlibReader)
+	require.NoError(t, err)
+	assert.Equal(t, mockResp.Body, string(decompressedBytes))
+}
+
+func TestCreateMockResponse_ZstdCompression(t *testing.T) {
+	// Given - Mock response with zstd Content-Encoding header
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Body:       `{"message": "Hello World"}`,
+		Headers:    `{"Content-Type": "application/json", "Content-Encoding": "zstd"}`,
+	}
+
+	// When - Create HTTP response
+	resp, err := createMockResponse(mockResp)
+
+	// Then - Response should be zstd compressed
+	require.NoError(t, err)
+	assert.Equal(t, "zstd", resp.Header.Get("Content-Encoding"))
+
+	compressedBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(compressedBytes)), resp.ContentLength)
+
+	zstdReader, err := zstd.NewReader(bytes.NewReader(compressedBytes))
+	require.NoError(t, err)
+	defer zstdReader.Close()
+
+	decompressedBytes, err := io.ReadAll(zstdReader)
+	require.NoError(t, err)
+	assert.Equal(t, mockResp.Body, string(decompressedBytes))
+}
+
 func TestCreateMockResponse_UnsupportedCompression(t *testing.T) {
 	// Given - Mock response with unsupported compression type
 	mockResp := database.MockResponse{
 		StatusCode: 200,
 		Body:       `{"message": "Hello World"}`,
-		Headers:    `{"Content-Type": "application/json", "Content-Encoding": "deflate"}`,
+		Headers:    `{"Content-Type": "application/json", "Content-Encoding": "compress"}`,
 	}
 
 	// When - Create HTTP response
 	resp, err := createMockResponse(mockResp)
 
-	// Then - Should use raw body (no compression)
+	// Then - Should use raw body (no compression) without claiming the encoding
 	require.NoError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
-	assert.Equal(t, "deflate", resp.Header.Get("Content-Encoding"))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
 	assert.Equal(t, int64(len(mockResp.Body)), resp.ContentLength)
 
 	// Read and verify body is not compressed
@@ -183,6 +245,49 @@ func TestCreateMockResponse_UnsupportedCompression(t *testing.T) {
 	assert.Equal(t, mockResp.Body, string(bodyBytes))
 }
 
+func TestCreateMockResponse_StackedEncodings(t *testing.T) {
+	// Given - Mock response declaring gzip then brotli, sent chunked
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Body:       `{"message": "Hello World"}`,
+		Headers:    `{"Content-Encoding": "gzip, br", "Transfer-Encoding": "chunked"}`,
+	}
+
+	// When - Create HTTP response
+	resp, err := createMockResponse(mockResp)
+
+	// Then - The last listed encoding is the outermost one
+	require.NoError(t, err)
+	assert.Equal(t, "gzip, br", resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	assert.Equal(t, int64(-1), resp.ContentLength)
+
+	gzipReader, err := gzip.NewReader(brotli.NewReader(resp.Body))
+	require.NoError(t, err)
+	defer gzipReader.Close()
+
+	decompressedBytes, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+	assert.Equal(t, mockResp.Body, string(decompressedBytes))
+}
+
+func TestCreateMockResponse_StackedEncodingsWithUnsupported(t *testing.T) {
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Body:       `{"message": "Hello World"}`,
+		Headers:    `{"Content-Encoding": "gzip, compress"}`,
+	}
+
+	resp, err := createMockResponse(mockResp)
+
+	// Then - Nothing is applied when any listed encoding is unsupported
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	bodyBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, mockResp.Body, string(bodyBytes))
+}
+
 func TestCreateMockResponse_EmptyBody(t *testing.T) {
 	// Given - Mock response with empty body and compression
 	mockResp := database.MockResponse{
@@ -208,3 +313,64 @@ func TestCreateMockResponse_EmptyBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", string(decompressedBytes))
 }
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"gzip":                "gzip",
+		"gzip, br":            "br",
+		"br;q=0.5, gzip":      "gzip",
+		"gzip;q=0, br;q=0":    "",
+		"*":                   "br",
+		"deflate, identity":   "deflate",
+		"zstd, deflate":       "zstd",
+		"compress, identity":  "",
+		"GZIP;q=0.8, *;q=0.1": "gzip",
+	}
+
+	for header, expected := range tests {
+		t.Run(header, func(t *testing.T) {
+			assert.Equal(t, expected, negotiateEncoding(header))
+		})
+	}
+}
+
+func TestNegotiateGeneratedResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	t.Run("Large built-in response is gzip compressed", func(t *testing.T) {
+		message := strings.Repeat("endpoint not found ", 100)
+		resp := createErrorResponse(http.StatusNotFound, message)
+
+		negotiateGeneratedResponse(resp, req)
+
+		// Then - Body is gzip encoded and decodes to the original JSON
+		require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+		reader, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(decoded, &body))
+		assert.Equal(t, message, body["message"])
+	})
+
+	t.Run("Small bodies stay uncompressed", func(t *testing.T) {
+		resp := createErrorResponse(http.StatusNotFound, "not found")
+
+		negotiateGeneratedResponse(resp, req)
+
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("Configured mock responses are not touched", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{StatusCode: 200, Body: strings.Repeat("x", 4096)})
+		require.NoError(t, err)
+
+		negotiateGeneratedResponse(resp, req)
+
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	})
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// ConfigValidationError describes one misconfiguration found by ValidateProjectConfig.
+// The IDs locate the offending endpoint, response or rule; empty IDs mean project level.
+type ConfigValidationError struct {
+	EndpointID string `json:"endpoint_id,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+	RuleID     string `json:"rule_id,omitempty"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+}
+
+// ValidateProjectConfig walks every endpoint, response and rule of a project and reports
+// configuration that would silently fail to match or be ignored at request time
+func (s *MockService) ValidateProjectConfig(projectID string) ([]ConfigValidationError, error) {
+	project, err := s.Repo.FindProjectWithConfig(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+
+	errs := []ConfigValidationError{}
+	if _, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err != nil {
+		errs = append(errs, ConfigValidationError{Field: "advance_config", Message: err.Error()})
+	}
+
+	for _, endpoint := range project.Endpoints {
+		errs = append(errs, validateEndpointConfig(endpoint)...)
+	}
+
+	return errs, nil
+}
+
+// validateEndpointConfig validates an endpoint together with its responses and rules
+func validateEndpointConfig(endpoint database.MockEndpoint) []ConfigValidationError {
+	var errs []ConfigValidationError
+	add := func(field, message string) {
+		errs = append(errs, ConfigValidationError{EndpointID: endpoint.ID, Field: field, Message: message})
+	}
+
+	if _, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig); err != nil {
+		add("advance_config", err.Error())
+	}
+	switch strings.ToLower(endpoint.ResponseMode) {
+	case "", "static", "random", "round_robin", "weighted", "shuffle", "sequence":
+	default:
+		add("response_mode", fmt.Sprintf("unknown response mode %q", endpoint.ResponseMode))
+	}
+
+	for _, response := range endpoint.Responses {
+		for _, responseErr := range validateResponseConfig(response) {
+			responseErr.EndpointID = endpoint.ID
+			errs = append(errs, responseErr)
+		}
+	}
+
+	return errs
+}
+
+// validateResponseConfig validates a response and its rules
+func validateResponseConfig(response database.MockResponse) []ConfigValidationError {
+	var errs []ConfigValidationError
+	add := func(ruleID, field, message string) {
+		errs = append(errs, ConfigValidationError{ResponseID: response.ID, RuleID: ruleID, Field: field, Message: message})
+	}
+
+	if response.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(response.Headers), &headers); err != nil {
+			add("", "headers", "headers must be a JSON object of strings")
+		}
+	}
+	if config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig); err != nil {
+		add("", "advance_config", err.Error())
+	} else if _, err := parseWeightExpression(config.Weight); err != nil {
+		add("", "advance_config.weight", err.Error())
+	}
+	if _, err := database.ParseResponseCookies(response.Cookies); err != nil {
+		add("", "cookies", err.Error())
+	}
+
+	for _, rule := range response.Rules {
+		if err := validateRule(rule); err != nil {
+			add(rule.ID, "rules", err.Error())
+		}
+	}
+
+	return errs
+}
+
+// validateRule checks that a rule can be evaluated, e.g. that its regex compiles
+func validateRule(rule database.MockRule) error {
+	if strings.ToLower(rule.Operator) == "regex" {
+		if _, err := regexp.Compile(rule.Value); err != nil {
+			return fmt.Errorf("%s rule %q: invalid regex: %v", rule.Type, rule.Key, err)
+		}
+	}
+	if strings.ToLower(rule.Operator) == "cidr" {
+		if _, err := database.ParseNetworks(rule.Value); err != nil {
+			return fmt.Errorf("%s rule %q: %v", rule.Type, rule.Key, err)
+		}
+	}
+	if rule.Type == "query_signature" {
+		if rule.Key == "" {
+			return fmt.Errorf("query_signature rule requires the signature param name as key")
+		}
+		if _, err := parseQuerySignatureConfig(rule.Value); err != nil {
+			return err
+		}
+	}
+	if rule.Type == "body_hash" {
+		if _, err := bodyHashAlgorithm(rule.Key); err != nil {
+			return err
+		}
+	}
+	if rule.Type == "geo" {
+		switch strings.ToLower(strings.TrimSpace(rule.Key)) {
+		case "", "country", "region":
+		default:
+			return fmt.Errorf("geo rule key must be country or region")
+		}
+	}
+	if rule.Type == "device" {
+		switch strings.ToLower(strings.TrimSpace(rule.Value)) {
+		case DeviceMobile, DeviceTablet, DeviceDesktop, DeviceBot:
+		default:
+			if strings.ToLower(rule.Operator) != "regex" {
+				return fmt.Errorf("device rule: unknown device class %q, must be mobile, tablet, desktop, or bot", rule.Value)
+			}
+		}
+	}
+	return nil
+}
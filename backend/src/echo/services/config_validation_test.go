@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestValidateProjectConfig(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "validate-config")
+
+	t.Run("Valid project has no errors", func(t *testing.T) {
+		endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/ok")
+		require.NoError(t, err)
+		createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+		errs, err := service.ValidateProjectConfig(project.ID)
+		require.NoError(t, err)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Problems are reported with their location", func(t *testing.T) {
+		endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/broken")
+		require.NoError(t, err)
+		require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "sequential").Error)
+
+		response := createTestResponse(t, endpoint.ID, database.MockResponse{
+			StatusCode:    200,
+			AdvanceConfig: `{"weight": "query.debug ? many : 1"}`,
+		})
+		rule := database.MockRule{ResponseID: response.ID, Type: "header", Key: "X-Id", Operator: "regex", Value: "([a-z"}
+		require.NoError(t, database.DB.Create(&rule).Error)
+
+		errs, err := service.ValidateProjectConfig(project.ID)
+		require.NoError(t, err)
+		require.Len(t, errs, 3)
+
+		fields := map[string]ConfigValidationError{}
+		for _, validationErr := range errs {
+			assert.Equal(t, endpoint.ID, validationErr.EndpointID)
+			fields[validationErr.Field] = validationErr
+		}
+		assert.Contains(t, fields, "response_mode")
+		assert.Equal(t, response.ID, fields["advance_config.weight"].ResponseID)
+		assert.Equal(t, rule.ID, fields["rules"].RuleID)
+		assert.Contains(t, fields["rules"].Message, "invalid regex")
+	})
+
+	t.Run("Unknown project returns an error", func(t *testing.T) {
+		_, err := service.ValidateProjectConfig("missing-project")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_Cookies(t *testing.T) {
+	// Given - Two cookies with attributes
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Body:       `{"ok":true}`,
+		Cookies: `[
+			{"name":"session","value":"abc123","path":"/","maxAge":3600,"secure":true,"httpOnly":true,"sameSite":"strict"},
+			{"name":"theme","value":"dark","expires":"2030-01-02T03:04:05Z","sameSite":"none","secure":true}
+		]`,
+	}
+
+	// When - Create HTTP response
+	resp, err := createMockResponse(mockResp)
+
+	// Then - Each cookie becomes its own Set-Cookie header with all attributes
+	require.NoError(t, err)
+	setCookies := resp.Header.Values("Set-Cookie")
+	require.Len(t, setCookies, 2)
+	assert.Equal(t, "session=abc123; Path=/; Max-Age=3600; HttpOnly; Secure; SameSite=Strict", setCookies[0])
+	assert.Equal(t, "theme=dark; Expires=Wed, 02 Jan 2030 03:04:05 GMT; Secure; SameSite=None", setCookies[1])
+
+	cookies := (&http.Response{Header: resp.Header}).Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "session", cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+}
+
+func TestCreateMockResponse_InvalidCookiesIgnored(t *testing.T) {
+	mockResp := database.MockResponse{
+		StatusCode: 200,
+		Cookies:    `not json`,
+	}
+
+	resp, err := createMockResponse(mockResp)
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Values("Set-Cookie"))
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// defaultCORSMethods are allowed when a project's cors config lists no methods
+var defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// corsPolicyFor returns the project's CORS policy, or nil when beo-echo doesn't handle CORS for it
+func corsPolicyFor(config *database.AdvanceConfigProject) *database.CORSPolicy {
+	if config == nil || config.CORS == nil || !config.CORS.Enabled {
+		return nil
+	}
+	return config.CORS
+}
+
+// resolvedProjectKey carries the project looked up before HandleRequest, see ResolveProjectCORS
+type resolvedProjectKey struct{}
+
+// resolvedProject is a project looked up for a request, with the alias it was looked up by
+type resolvedProject struct {
+	alias   string
+	project *database.Project
+}
+
+// ResolveProjectCORS looks up the project of a request and reports whether it answers CORS
+// itself, so the server-wide CORS middleware must leave the request alone. The returned request
+// carries the project, so HandleRequest doesn't look it up again.
+func (s *MockService) ResolveProjectCORS(req *http.Request, alias string) (*http.Request, bool) {
+	if s.Repo == nil {
+		return req, false
+	}
+	project, err := s.Repo.FindProjectByAlias(alias)
+	if err != nil {
+		return req, false
+	}
+	req = req.WithContext(context.WithValue(req.Context(), resolvedProjectKey{}, &resolvedProject{alias: alias, project: project}))
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	return req, err == nil && corsPolicyFor(config) != nil
+}
+
+// findRequestProject returns the project resolved for the request by ResolveProjectCORS, looking
+// it up by alias otherwise
+func (s *MockService) findRequestProject(req *http.Request, alias string) (*database.Project, error) {
+	if req != nil {
+		if resolved, ok := req.Context().Value(resolvedProjectKey{}).(*resolvedProject); ok && resolved.alias == alias {
+			return resolved.project, nil
+		}
+	}
+	return s.Repo.FindProjectByAlias(alias)
+}
+
+// isPreflightRequest reports whether req is a CORS preflight rather than an OPTIONS call to the API
+func isPreflightRequest(req *http.Request) bool {
+	return req != nil && req.Method == http.MethodOptions &&
+		req.Header.Get("Origin") != "" && req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// allowedCORSOrigin returns the Access-Control-Allow-Origin value for the request origin, or
+// false when the policy doesn't allow it
+func allowedCORSOrigin(policy *database.CORSPolicy, origin string) (string, bool) {
+	if origin == "" {
+		return "", false
+	}
+	origins := policy.AllowOrigins
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+	for _, allowed := range origins {
+		switch {
+		case allowed == "*":
+			if policy.AllowCredentials {
+				return origin, true // "*" is not accepted with credentials, so the origin is reflected
+			}
+			return "*", true
+		case strings.EqualFold(allowed, origin):
+			return origin, true
+		case strings.Contains(allowed, "*."):
+			prefix, suffix, _ := strings.Cut(allowed, "*")
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) && len(origin) > len(prefix)+len(suffix) {
+				return origin, true
+			}
+		}
+	}
+	return "", false
+}
+
+// applyCORSHeaders adds the CORS response headers for an allowed origin to any response of the
+// project, replacing the ones a mock response or the upstream set
+func applyCORSHeaders(policy *database.CORSPolicy, req *http.Request, resp *http.Response) {
+	if policy == nil || req == nil || resp == nil || resp.Header == nil {
+		return
+	}
+	allowOrigin, ok := allowedCORSOrigin(policy, req.Header.Get("Origin"))
+	if !ok {
+		return
+	}
+
+	resp.Header.Set("Access-Control-Allow-Origin", allowOrigin)
+	if allowOrigin != "*" {
+		addVary(resp.Header, "Origin")
+	}
+	if policy.AllowCredentials {
+		resp.Header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(policy.ExposeHeaders) > 0 && !isPreflightRequest(req) {
+		resp.Header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+	}
+}
+
+// addVary appends a header name to Vary, keeping a single comma-separated value
+func addVary(header http.Header, name string) {
+	if vary := header.Get("Vary"); vary != "" {
+		header.Set("Vary", vary+", "+name)
+		return
+	}
+	header.Set("Vary", name)
+}
+
+// createPreflightResponse answers a preflight: 204 with the allowed methods and headers, or 403
+// when the origin is not allowed. The origin headers are added by applyCORSHeaders.
+func createPreflightResponse(policy *database.CORSPolicy, req *http.Request) *http.Response {
+	if _, ok := allowedCORSOrigin(policy, req.Header.Get("Origin")); !ok {
+		return createErrorResponse(http.StatusForbidden, "CORS origin not allowed")
+	}
+
+	resp := &http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       http.NoBody,
+		Header:     make(http.Header),
+	}
+	methods := policy.AllowMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	resp.Header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	if len(policy.AllowHeaders) > 0 {
+		resp.Header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowHeaders, ", "))
+	} else if requested := req.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		resp.Header.Set("Access-Control-Allow-Headers", requested)
+		addVary(resp.Header, "Access-Control-Request-Headers")
+	}
+	if policy.MaxAgeSeconds > 0 {
+		resp.Header.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAgeSeconds))
+	}
+	return resp
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestAllowedCORSOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   database.CORSPolicy
+		origin   string
+		expected string
+		allowed  bool
+	}{
+		{"any origin by default", database.CORSPolicy{}, "https://app.test", "*", true},
+		{"wildcard reflects with credentials", database.CORSPolicy{AllowOrigins: []string{"*"}, AllowCredentials: true}, "https://app.test", "https://app.test", true},
+		{"exact origin", database.CORSPolicy{AllowOrigins: []string{"https://app.test"}}, "https://app.test", "https://app.test", true},
+		{"other origin", database.CORSPolicy{AllowOrigins: []string{"https://app.test"}}, "https://evil.test", "", false},
+		{"subdomain wildcard", database.CORSPolicy{AllowOrigins: []string{"https://*.example.com"}}, "https://dev.example.com", "https://dev.example.com", true},
+		{"subdomain wildcard needs a subdomain", database.CORSPolicy{AllowOrigins: []string{"https://*.example.com"}}, "https://example.com", "", false},
+		{"no origin", database.CORSPolicy{}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := allowedCORSOrigin(&tt.policy, tt.origin)
+			assert.Equal(t, tt.allowed, ok)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestHandleRequest_CORS(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "cors-project")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/items")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: `[]`, Headers: `{"X-Request-Id": "abc"}`})
+
+	send := func(method, origin string, headers map[string]string) *http.Response {
+		req := httptest.NewRequest(method, "/cors-project/items", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, method, "/items", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("No headers without a cors config", func(t *testing.T) {
+		resp := send(http.MethodGet, "https://app.test", nil)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+		_, handlesCORS := service.ResolveProjectCORS(httptest.NewRequest(http.MethodGet, "/items", nil), project.Alias)
+		assert.False(t, handlesCORS)
+	})
+
+	project.AdvanceConfig = `{"cors": {"enabled": true, "allowOrigins": ["https://app.test"], "allowCredentials": true, "exposeHeaders": ["X-Request-Id"], "maxAgeSeconds": 600}}`
+	require.NoError(t, database.DB.Save(project).Error)
+	resolved, handlesCORS := service.ResolveProjectCORS(httptest.NewRequest(http.MethodGet, "/items", nil), project.Alias)
+	require.True(t, handlesCORS)
+	found, err := service.findRequestProject(resolved, project.Alias)
+	require.NoError(t, err)
+	assert.Same(t, resolved.Context().Value(resolvedProjectKey{}).(*resolvedProject).project, found, "HandleRequest reuses the resolved project")
+
+	t.Run("Mock responses carry the CORS headers", func(t *testing.T) {
+		resp := send(http.MethodGet, "https://app.test", nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "https://app.test", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "X-Request-Id", resp.Header.Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", resp.Header.Get("Vary"))
+	})
+
+	t.Run("Not-found responses too", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/cors-project/missing", nil)
+		req.Header.Set("Origin", "https://app.test")
+		resp, _, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/missing", req)
+		assert.False(t, matched)
+		assert.Equal(t, "https://app.test", resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Preflight is answered", func(t *testing.T) {
+		resp := send(http.MethodOptions, "https://app.test", map[string]string{
+			"Access-Control-Request-Method":  "DELETE",
+			"Access-Control-Request-Headers": "Authorization, X-Trace",
+		})
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "https://app.test", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, X-Trace", resp.Header.Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+		assert.Equal(t, "Access-Control-Request-Headers, Origin", resp.Header.Get("Vary"))
+	})
+
+	t.Run("Disallowed origins get no CORS headers", func(t *testing.T) {
+		resp := send(http.MethodGet, "https://evil.test", nil)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp = send(http.MethodOptions, "https://evil.test", map[string]string{"Access-Control-Request-Method": "GET"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+}
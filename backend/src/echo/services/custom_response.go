@@ -0,0 +1,114 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// createCustomResponse builds a configured response through createMockResponse so it behaves
+// like any other mock (headers, encoding). Falls back to the standard error response when not configured.
+func createCustomResponse(custom *database.CustomResponse, defaultStatus int, defaultMessage string) *http.Response {
+	if custom == nil {
+		return createErrorResponse(defaultStatus, defaultMessage)
+	}
+
+	mockResp := database.MockResponse{
+		StatusCode: custom.StatusCode,
+		Body:       custom.Body,
+	}
+	if mockResp.StatusCode == 0 {
+		mockResp.StatusCode = defaultStatus
+	}
+	if len(custom.Headers) > 0 {
+		headersJSON, _ := json.Marshal(custom.Headers)
+		mockResp.Headers = string(headersJSON)
+	}
+
+	resp, err := createMockResponse(mockResp)
+	if err != nil {
+		return createErrorResponse(defaultStatus, defaultMessage)
+	}
+	return resp
+}
+
+// createLimitExceededResponse returns the response for a rate or concurrency limit being hit.
+// Endpoint config takes priority over project config, which overrides the built-in error.
+func createLimitExceededResponse(project *database.Project, endpoint *database.MockEndpoint, defaultStatus int, defaultMessage string) *http.Response {
+	if endpoint != nil && endpoint.AdvanceConfig != "" {
+		if config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig); err == nil && config.LimitExceededResponse != nil {
+			return createCustomResponse(config.LimitExceededResponse, defaultStatus, defaultMessage)
+		}
+	}
+
+	if project != nil && project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil && config.LimitExceededResponse != nil {
+			return createCustomResponse(config.LimitExceededResponse, defaultStatus, defaultMessage)
+		}
+	}
+
+	return createErrorResponse(defaultStatus, defaultMessage)
+}
+
+// createLoopDetectedResponse returns the project's configured loop-detected response,
+// defaulting to the built-in 508 error
+func createLoopDetectedResponse(project *database.Project, defaultMessage string) *http.Response {
+	if project != nil && project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil && config.LoopDetectedResponse != nil {
+			return createCustomResponse(config.LoopDetectedResponse, http.StatusLoopDetected, defaultMessage)
+		}
+	}
+
+	return createErrorResponse(http.StatusLoopDetected, defaultMessage)
+}
+
+// createInvalidModeResponse logs the unsupported mode for diagnosis and returns the project's
+// configured invalid-mode response, defaulting to the built-in 500 error
+func createInvalidModeResponse(project *database.Project) *http.Response {
+	log.Error().
+		Str("project_id", project.ID).
+		Str("project_alias", project.Alias).
+		Str("mode", string(project.Mode)).
+		Msg("project has an unsupported mode")
+
+	if project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil && config.InvalidModeResponse != nil {
+			return createCustomResponse(config.InvalidModeResponse, http.StatusInternalServerError, "Invalid project mode")
+		}
+	}
+
+	return createErrorResponse(http.StatusInternalServerError, "Invalid project mode")
+}
+
+// createNotFoundResponse returns the response for a request no endpoint matches: the project's
+// notFoundResponse when configured, otherwise the default JSON message with the
+// DEFAULT_RESPONSE_NOT_FOUND_STATUS status (404). project is nil when the alias is unknown.
+func createNotFoundResponse(project *database.Project, defaultMessage string) *http.Response {
+	statusCode := notFoundStatus()
+
+	if project != nil && project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil && config.NotFoundResponse != nil {
+			return createCustomResponse(config.NotFoundResponse, statusCode, defaultMessage)
+		}
+	}
+
+	return createDefaultJSONResponse(statusCode, defaultMessage)
+}
+
+// notFoundStatus returns the configured status of default not-found responses, falling back
+// to 404 when it is unset or not a valid HTTP status
+func notFoundStatus() int {
+	if database.DB == nil {
+		return http.StatusNotFound
+	}
+
+	status, err := systemConfig.GetSystemConfigWithType[int](systemConfig.DEFAULT_RESPONSE_NOT_FOUND_STATUS)
+	if err != nil || status < 100 || status > 599 {
+		return http.StatusNotFound
+	}
+	return status
+}
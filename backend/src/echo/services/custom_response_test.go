@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestCreateLimitExceededResponse(t *testing.T) {
+	project := &database.Project{
+		AdvanceConfig: `{"limitExceededResponse": {"body": "{\"code\":\"PROJECT_LIMIT\"}"}}`,
+	}
+	endpoint := &database.MockEndpoint{
+		AdvanceConfig: `{"limitExceededResponse": {"statusCode": 420, "body": "slow down", "headers": {"Retry-After": "30"}}}`,
+	}
+
+	t.Run("Endpoint config overrides project config", func(t *testing.T) {
+		resp := createLimitExceededResponse(project, endpoint, http.StatusTooManyRequests, "Rate limit exceeded")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, 420, resp.StatusCode)
+		assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+		assert.Equal(t, "slow down", string(body))
+	})
+
+	t.Run("Project config keeps the default status when unset", func(t *testing.T) {
+		resp := createLimitExceededResponse(project, nil, http.StatusServiceUnavailable, "Too many requests in flight")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, `{"code":"PROJECT_LIMIT"}`, string(body))
+	})
+
+	t.Run("Without config the standard error response is used", func(t *testing.T) {
+		resp := createLimitExceededResponse(&database.Project{}, nil, http.StatusTooManyRequests, "Rate limit exceeded")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.JSONEq(t, `{"error":true,"message":"Rate limit exceeded"}`, string(body))
+	})
+}
+
+func TestHandleForwarderMode_LoopDetectedResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("beo-echo-loop-detect", "true")
+
+	t.Run("Configured response replaces the built-in 508", func(t *testing.T) {
+		project := &database.Project{
+			Mode:          database.ModeForwarder,
+			ActiveProxy:   &database.ProxyTarget{URL: "http://upstream.invalid"},
+			AdvanceConfig: `{"loopDetectedResponse": {"statusCode": 502, "body": "{\"errors\":[{\"code\":\"LOOP\"}]}"}}`,
+		}
+
+		resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Equal(t, `{"errors":[{"code":"LOOP"}]}`, string(body))
+	})
+
+	t.Run("Default stays 508", func(t *testing.T) {
+		project := &database.Project{
+			Mode:        database.ModeForwarder,
+			ActiveProxy: &database.ProxyTarget{URL: "http://upstream.invalid"},
+		}
+
+		resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusLoopDetected, resp.StatusCode)
+	})
+}
+
+func TestCreateInvalidModeResponse(t *testing.T) {
+	t.Run("Configured response replaces the built-in 500", func(t *testing.T) {
+		project := &database.Project{
+			ID:            "project-1",
+			Mode:          database.ProjectMode("corrupted"),
+			AdvanceConfig: `{"invalidModeResponse": {"statusCode": 503, "body": "{\"code\":\"BAD_MODE\"}"}}`,
+		}
+
+		resp := createInvalidModeResponse(project)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, `{"code":"BAD_MODE"}`, string(body))
+	})
+
+	t.Run("Default stays 500", func(t *testing.T) {
+		resp := createInvalidModeResponse(&database.Project{ID: "project-2", Mode: database.ProjectMode("")})
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.JSONEq(t, `{"error":true,"message":"Invalid project mode"}`, string(body))
+	})
+}
+
+func TestHandleRequest_NotFoundResponse(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "not-found-status")
+
+	t.Run("Unknown endpoint answers 404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/not-found-status/missing", nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/missing", req)
+
+		require.NoError(t, err)
+		assert.False(t, matched)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.JSONEq(t, `{"message":"This is a default response from Beo Echo mock service."}`, string(body))
+	})
+
+	t.Run("Unknown project answers 404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/no-such-project/items", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), "no-such-project", http.MethodGet, "/items", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("System config restores the old status", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.DEFAULT_RESPONSE_NOT_FOUND_STATUS, "200"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.DEFAULT_RESPONSE_NOT_FOUND_STATUS, "404") })
+
+		req := httptest.NewRequest(http.MethodGet, "/not-found-status/missing", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/missing", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Project notFoundResponse overrides status and body", func(t *testing.T) {
+		project.AdvanceConfig = `{"notFoundResponse": {"statusCode": 410, "body": "{\"code\":\"GONE\"}"}}`
+		require.NoError(t, database.DB.Save(project).Error)
+
+		req := httptest.NewRequest(http.MethodGet, "/not-found-status/missing", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/missing", req)
+
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+		assert.Equal(t, `{"code":"GONE"}`, string(body))
+	})
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// nowFunc is the clock used for Date headers; tests replace it
+var nowFunc = time.Now
+
+// skewedDate formats the current time shifted by offsetSeconds as an HTTP Date header value
+func skewedDate(offsetSeconds int) string {
+	return nowFunc().Add(time.Duration(offsetSeconds) * time.Second).UTC().Format(http.TimeFormat)
+}
+
+// setResponseDate sets the Date header of a mock response shifted by its dateOffsetSeconds
+// (the real current time by default). A Date configured in the response headers is kept.
+func setResponseDate(resp *http.Response, offsetSeconds int) {
+	if resp.Header.Get("Date") != "" {
+		return
+	}
+	resp.Header.Set("Date", skewedDate(offsetSeconds))
+}
+
+// applyProjectDateSkew shifts the Date header by the project's dateOffsetSeconds when the
+// response neither sets its own offset nor configures a Date header
+func applyProjectDateSkew(resp *http.Response, project *database.Project, mockResp *database.MockResponse) {
+	if resp == nil || project == nil || project.AdvanceConfig == "" {
+		return
+	}
+	if responseDateOffset(*mockResp) != 0 || hasConfiguredHeader(mockResp.Headers, "Date") {
+		return
+	}
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil || config.DateOffsetSeconds == 0 {
+		return
+	}
+	resp.Header.Set("Date", skewedDate(config.DateOffsetSeconds))
+}
+
+// responseDateOffset returns the response's dateOffsetSeconds, 0 when not configured
+func responseDateOffset(mockResp database.MockResponse) int {
+	if mockResp.AdvanceConfig == "" {
+		return 0
+	}
+	config, err := database.ParseResponseAdvanceConfig(mockResp.AdvanceConfig)
+	if err != nil {
+		return 0
+	}
+	return config.DateOffsetSeconds
+}
+
+// hasConfiguredHeader reports whether a response headers JSON object sets the header (case-insensitive)
+func hasConfiguredHeader(headersJSON, name string) bool {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(headersJSON), &headers); err != nil {
+		return false
+	}
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
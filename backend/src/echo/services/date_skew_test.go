@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+// stubNow pins the clock used for Date headers for the duration of the test
+func stubNow(t *testing.T, now time.Time) {
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+}
+
+func TestRespondWithDelay_DateSkew(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	stubNow(t, now)
+	service := &MockService{}
+	project := &database.Project{AdvanceConfig: `{"dateOffsetSeconds": -3600}`}
+
+	respond := func(response database.MockResponse) *http.Response {
+		resp, err := service.respondWithDelay(context.Background(), project, &database.MockEndpoint{}, &response)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Project offset, or the real time without one", func(t *testing.T) {
+		resp := respond(database.MockResponse{StatusCode: 200})
+		resp2, err := createMockResponse(database.MockResponse{StatusCode: 200})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Fri, 01 Mar 2024 11:00:00 GMT", resp.Header.Get("Date"), "project offset applies")
+		assert.Equal(t, "Fri, 01 Mar 2024 12:00:00 GMT", resp2.Header.Get("Date"))
+	})
+
+	t.Run("Response offset overrides the project", func(t *testing.T) {
+		resp := respond(database.MockResponse{StatusCode: 200, AdvanceConfig: `{"dateOffsetSeconds": 90}`})
+		assert.Equal(t, "Fri, 01 Mar 2024 12:01:30 GMT", resp.Header.Get("Date"))
+	})
+
+	t.Run("Configured Date header is kept", func(t *testing.T) {
+		resp := respond(database.MockResponse{StatusCode: 200, Headers: `{"Date": "Mon, 01 Jan 2001 00:00:00 GMT"}`})
+		assert.Equal(t, "Mon, 01 Jan 2001 00:00:00 GMT", resp.Header.Get("Date"))
+	})
+}
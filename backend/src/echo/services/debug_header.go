@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// debugHeader carries the matchTrace of a response for projects with debugHeader enabled
+const debugHeader = "beo-echo-debug"
+
+// matchTrace explains how the response of a request was chosen
+type matchTrace struct {
+	Mode         string   `json:"mode"`               // Execution mode, e.g. mock or proxy
+	Endpoint     string   `json:"endpoint,omitempty"` // Method and path of the matched endpoint
+	EndpointID   string   `json:"endpointId,omitempty"`
+	ResponseMode string   `json:"responseMode,omitempty"` // How the endpoint picks among matching responses
+	Candidates   []string `json:"candidates,omitempty"`   // Active responses for the environment, scenario and body size
+	PassedRules  []string `json:"passedRules,omitempty"`  // Candidates whose rules all match the request
+	ResponseID   string   `json:"responseId,omitempty"`   // Selected response
+	Reason       string   `json:"reason"`
+}
+
+// withMatchTrace makes the request collect a matchTrace, in its MatchInfo when it has one
+func withMatchTrace(req *http.Request) (*http.Request, *matchTrace) {
+	trace := &matchTrace{}
+	if info, ok := req.Context().Value(matchInfoKey{}).(*MatchInfo); ok {
+		info.trace = trace
+		return req, trace
+	}
+	info := &MatchInfo{trace: trace}
+	return req.WithContext(context.WithValue(req.Context(), matchInfoKey{}, info)), trace
+}
+
+// matchTraceFrom returns the request's matchTrace, nil when the project doesn't debug matching
+func matchTraceFrom(req *http.Request) *matchTrace {
+	if req == nil {
+		return nil
+	}
+	if info, ok := req.Context().Value(matchInfoKey{}).(*MatchInfo); ok {
+		return info.trace
+	}
+	return nil
+}
+
+// traceResponses lists the responses considered at one step of response selection
+func traceResponses(responses []database.MockResponse) []string {
+	ids := make([]string, len(responses))
+	for i, response := range responses {
+		ids[i] = response.ID
+	}
+	return ids
+}
+
+// selectionReason describes how the response mode picks among count matching responses
+func selectionReason(mode string, count int) string {
+	switch strings.ToLower(mode) {
+	case "static":
+		return fmt.Sprintf("highest priority of %d matching responses", count)
+	case "shuffle":
+		return fmt.Sprintf("next in the shuffled order of %d matching responses", count)
+	case "weighted":
+		return fmt.Sprintf("weighted pick among %d matching responses", count)
+	case "round_robin":
+		return fmt.Sprintf("next in the rotation of %d matching responses", count)
+	case "sequence":
+		return fmt.Sprintf("next in the sequence of %d matching responses", count)
+	default:
+		return fmt.Sprintf("random pick among %d matching responses", count)
+	}
+}
+
+// setDebugHeader sends the trace as compact JSON in the beo-echo-debug header, filling in the
+// reason for requests that never reached response selection
+func setDebugHeader(resp *http.Response, trace *matchTrace, mode database.ProjectMode) {
+	if resp == nil || resp.Header == nil || trace == nil {
+		return
+	}
+	trace.Mode = string(mode)
+
+	switch {
+	case resp.Header.Get("beo-echo-response-type") == "fallback":
+		trace.Reason = "upstream answered " + resp.Header.Get("beo-echo-upstream-status") + ", served the proxy fallback"
+	case trace.ResponseID == "" && (mode == database.ModeProxy || mode == database.ModeForwarder):
+		trace.Reason = "forwarded to the proxy target"
+	case trace.EndpointID == "":
+		trace.Reason = "no endpoint matched the request"
+	case trace.Reason == "":
+		trace.Reason = "endpoint matched but no response was selected"
+	}
+
+	if encoded, err := json.Marshal(trace); err == nil {
+		resp.Header.Set(debugHeader, string(encoded))
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_DebugHeader(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "debug-header")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/users")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	admin := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "admin", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: admin.ID, Type: "query", Key: "role", Operator: "equals", Value: "admin"}).Error)
+	fallback := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "fallback", IsFallback: true})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: fallback.ID, Type: "query", Key: "role", Operator: "exists"}).Error)
+
+	call := func(target string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, req.URL.Path, req)
+		require.NoError(t, err)
+		return resp
+	}
+	trace := func(resp *http.Response) matchTrace {
+		var decoded matchTrace
+		require.NoError(t, json.Unmarshal([]byte(resp.Header.Get("beo-echo-debug")), &decoded))
+		return decoded
+	}
+
+	t.Run("Off by default", func(t *testing.T) {
+		assert.Empty(t, call("/debug-header/users?role=admin").Header.Get("beo-echo-debug"))
+	})
+
+	project.AdvanceConfig = `{"debugHeader": true}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	t.Run("Selected response and the responses passing rules", func(t *testing.T) {
+		got := trace(call("/debug-header/users?role=admin"))
+
+		assert.Equal(t, "mock", got.Mode)
+		assert.Equal(t, "GET /users", got.Endpoint)
+		assert.Equal(t, endpoint.ID, got.EndpointID)
+		assert.Equal(t, "static", got.ResponseMode)
+		assert.ElementsMatch(t, []string{admin.ID, fallback.ID}, got.Candidates)
+		assert.ElementsMatch(t, []string{admin.ID, fallback.ID}, got.PassedRules)
+		assert.Equal(t, admin.ID, got.ResponseID)
+		assert.Equal(t, "highest priority of 2 matching responses", got.Reason)
+	})
+
+	t.Run("Fallback when no rules match", func(t *testing.T) {
+		got := trace(call("/debug-header/users"))
+
+		assert.Empty(t, got.PassedRules)
+		assert.Equal(t, fallback.ID, got.ResponseID)
+		assert.Equal(t, "no response matched its rules, served the fallback response", got.Reason)
+	})
+
+	t.Run("No endpoint", func(t *testing.T) {
+		got := trace(call("/debug-header/missing"))
+
+		assert.Empty(t, got.EndpointID)
+		assert.Equal(t, "no endpoint matched the request", got.Reason)
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// debugPathPrefix is the reserved path under a project alias that returns introspection data
+// instead of a mock response, e.g. GET /my-project/__beo-echo/routes?method=GET&path=/users/1
+const debugPathPrefix = "/__beo-echo"
+
+// debugRoute describes one endpoint in the route table
+type debugRoute struct {
+	ID           string          `json:"id"`
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	Enabled      bool            `json:"enabled"`
+	ResponseMode string          `json:"response_mode"`
+	UseProxy     bool            `json:"use_proxy"`
+	Responses    []debugResponse `json:"responses"`
+}
+
+// debugResponse summarizes a response of a debug route
+type debugResponse struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"status_code"`
+	Priority   int    `json:"priority"`
+	Enabled    bool   `json:"enabled"`
+	IsFallback bool   `json:"is_fallback"`
+	Rules      int    `json:"rules"`
+	Note       string `json:"note,omitempty"`
+}
+
+// debugRoutingResult tells which endpoint a method/path pair routes to
+type debugRoutingResult struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Matched    bool   `json:"matched"`
+	EndpointID string `json:"endpoint_id,omitempty"`
+}
+
+// isDebugPath reports whether the path targets the reserved debug prefix and debug routes are enabled
+func isDebugPath(path string) bool {
+	if path != debugPathPrefix && !strings.HasPrefix(path, debugPathPrefix+"/") {
+		return false
+	}
+	if database.DB == nil {
+		return false
+	}
+	enabled, err := systemConfig.GetSystemConfigWithType[bool](systemConfig.DEBUG_ROUTES_ENABLED)
+	return err == nil && enabled
+}
+
+// handleDebugPath returns the project's route table as JSON. When method and path query
+// params are given it also reports which endpoint that request would be routed to.
+func (s *MockService) handleDebugPath(project *database.Project, req *http.Request) *http.Response {
+	fullProject, err := s.Repo.FindProjectWithConfig(project.ID)
+	if err != nil {
+		return createErrorResponse(http.StatusInternalServerError, "Failed to load project routes")
+	}
+
+	routes := make([]debugRoute, 0, len(fullProject.Endpoints))
+	for _, endpoint := range fullProject.Endpoints {
+		route := debugRoute{
+			ID:           endpoint.ID,
+			Method:       endpoint.Method,
+			Path:         endpoint.Path,
+			Enabled:      endpoint.Enabled,
+			ResponseMode: endpoint.ResponseMode,
+			UseProxy:     endpoint.UseProxy,
+			Responses:    make([]debugResponse, 0, len(endpoint.Responses)),
+		}
+		for _, response := range endpoint.Responses {
+			route.Responses = append(route.Responses, debugResponse{
+				ID:         response.ID,
+				StatusCode: response.StatusCode,
+				Priority:   response.Priority,
+				Enabled:    response.Enabled,
+				IsFallback: response.IsFallback,
+				Rules:      len(response.Rules),
+				Note:       response.Note,
+			})
+		}
+		routes = append(routes, route)
+	}
+
+	result := map[string]interface{}{
+		"project": map[string]interface{}{
+			"id":    project.ID,
+			"alias": project.Alias,
+			"mode":  project.Mode,
+		},
+		"routes": routes,
+	}
+
+	query := req.URL.Query()
+	if routePath := query.Get("path"); routePath != "" {
+		method := strings.ToUpper(query.Get("method"))
+		if method == "" {
+			method = http.MethodGet
+		}
+		routing := debugRoutingResult{Method: method, Path: routePath}
+		if endpoint, err := s.findEndpoint(project, method, routePath); err == nil {
+			routing.Matched = true
+			routing.EndpointID = endpoint.ID
+		}
+		result["routing"] = routing
+	}
+
+	jsonBody, _ := json.MarshalIndent(result, "", "  ")
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Body:          newGeneratedBody(jsonBody),
+		Header:        make(http.Header),
+		ContentLength: int64(len(jsonBody)),
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	return resp
+}
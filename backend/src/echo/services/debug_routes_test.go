@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestHandleRequest_DebugRoutes(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "debug-routes")
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/users/:id")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "user"})
+
+	call := func() *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/debug-routes/__beo-echo/routes?method=get&path=/users/42", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/__beo-echo/routes", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		resp := call()
+		body, _ := io.ReadAll(resp.Body)
+		assert.NotContains(t, string(body), `"routes"`)
+	})
+
+	t.Run("Returns the route table and routing result when enabled", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.DEBUG_ROUTES_ENABLED, "true"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.DEBUG_ROUTES_ENABLED, "false") })
+
+		resp := call()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Routes  []debugRoute       `json:"routes"`
+			Routing debugRoutingResult `json:"routing"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Routes, 1)
+		assert.Equal(t, "/users/:id", result.Routes[0].Path)
+		require.Len(t, result.Routes[0].Responses, 1)
+		assert.True(t, result.Routes[0].Responses[0].Enabled)
+		assert.True(t, result.Routing.Matched)
+		assert.Equal(t, endpoint.ID, result.Routing.EndpointID)
+		assert.Equal(t, http.MethodGet, result.Routing.Method)
+	})
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// statusClassTemplateKeys maps a status class (status / 100) to the system config holding its body template
+var statusClassTemplateKeys = map[int]string{
+	4: systemConfig.DEFAULT_RESPONSE_TEMPLATE_4XX,
+	5: systemConfig.DEFAULT_RESPONSE_TEMPLATE_5XX,
+}
+
+// defaultResponseTemplate returns the configured body template for the status class, or "" when none is set
+func defaultResponseTemplate(statusCode int) string {
+	key, ok := statusClassTemplateKeys[statusCode/100]
+	if !ok || database.DB == nil {
+		return ""
+	}
+
+	template, err := systemConfig.GetSystemConfigWithType[string](key)
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+// renderDefaultResponseTemplate fills {{status}}, {{status_text}} and {{message}} in the template.
+// Text values are JSON-escaped so they can sit inside string literals of a JSON template.
+func renderDefaultResponseTemplate(template string, statusCode int, message string) string {
+	replacer := strings.NewReplacer(
+		"{{status}}", strconv.Itoa(statusCode),
+		"{{status_text}}", escapeJSONString(http.StatusText(statusCode)),
+		"{{message}}", escapeJSONString(message),
+	)
+	return replacer.Replace(template)
+}
+
+// escapeJSONString escapes s for use inside a JSON string literal, without the surrounding quotes
+func escapeJSONString(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded[1 : len(encoded)-1])
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestRenderDefaultResponseTemplate(t *testing.T) {
+	template := `{"code":{{status}},"reason":"{{status_text}}","detail":"{{message}}"}`
+
+	rendered := renderDefaultResponseTemplate(template, http.StatusNotFound, `path "/x" not found`)
+
+	assert.JSONEq(t, `{"code":404,"reason":"Not Found","detail":"path \"/x\" not found"}`, rendered)
+}
+
+func TestCreateErrorResponse_StatusClassTemplate(t *testing.T) {
+	database.SetupTestEnvironment(t)
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.DEFAULT_RESPONSE_TEMPLATE_5XX, `{"status":{{status}},"error":"{{message}}"}`))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.DEFAULT_RESPONSE_TEMPLATE_5XX, "") })
+
+	t.Run("5xx responses use the class template", func(t *testing.T) {
+		resp := createErrorResponse(http.StatusBadGateway, "upstream down")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.JSONEq(t, `{"status":502,"error":"upstream down"}`, string(body))
+		assert.Equal(t, int64(len(body)), resp.ContentLength)
+	})
+
+	t.Run("Classes without a template keep the built-in body", func(t *testing.T) {
+		resp := createErrorResponse(http.StatusBadRequest, "bad input")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"error":true,"message":"bad input"}`, string(body))
+	})
+}
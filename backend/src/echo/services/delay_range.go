@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+
+	"beo-echo/backend/src/database"
+)
+
+// randomNormFloat64 is the standard normal source for "normal" delay ranges; tests can replace it
+var randomNormFloat64 = rand.NormFloat64
+
+// sampleDelayMs picks a delay in [MinMs, MaxMs]. The normal distribution is centered on the
+// middle of the range with a standard deviation of a sixth of its width, clamped to the range.
+func sampleDelayMs(delayRange *database.DelayRange) int {
+	width := delayRange.MaxMs - delayRange.MinMs
+	if width <= 0 {
+		return delayRange.MinMs
+	}
+
+	if delayRange.Distribution == database.DelayDistributionNormal {
+		mean := float64(delayRange.MinMs+delayRange.MaxMs) / 2
+		sample := math.Round(mean + randomNormFloat64()*float64(width)/6)
+		return int(math.Max(float64(delayRange.MinMs), math.Min(float64(delayRange.MaxMs), sample)))
+	}
+	return delayRange.MinMs + randomIntn(width+1)
+}
+
+// latencyPoint is a point of a latency profile's inverse CDF: the delay at a quantile
+type latencyPoint struct {
+	quantile float64
+	ms       int
+}
+
+// sampleLatencyProfileMs picks a delay from the profile's percentiles, interpolating linearly
+// between the neighbouring points of a uniform sample, so about half of the delays are under
+// p50 and one in a hundred above p99
+func sampleLatencyProfileMs(profile *database.LatencyProfile) int {
+	points := []latencyPoint{{0, profile.MinMs}, {0.5, profile.P50Ms}}
+	for _, point := range []latencyPoint{{0.9, profile.P90Ms}, {0.95, profile.P95Ms}, {0.99, profile.P99Ms}} {
+		if point.ms > 0 {
+			points = append(points, point)
+		}
+	}
+	slowest := points[len(points)-1].ms
+	if profile.MaxMs > 0 {
+		slowest = profile.MaxMs
+	}
+	points = append(points, latencyPoint{1, slowest})
+
+	sample := randomFloat64()
+	for i := 1; i < len(points); i++ {
+		lower, upper := points[i-1], points[i]
+		if sample <= upper.quantile {
+			share := (sample - lower.quantile) / (upper.quantile - lower.quantile)
+			return lower.ms + int(math.Round(share*float64(upper.ms-lower.ms)))
+		}
+	}
+	return slowest
+}
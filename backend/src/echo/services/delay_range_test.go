@@ -0,0 +1,116 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestSampleDelayMs_WithinBounds(t *testing.T) {
+	for _, distribution := range []string{"", database.DelayDistributionUniform, database.DelayDistributionNormal} {
+		t.Run("distribution "+distribution, func(t *testing.T) {
+			delayRange := &database.DelayRange{MinMs: 20, MaxMs: 80, Distribution: distribution}
+			seen := map[int]bool{}
+			for i := 0; i < 2000; i++ {
+				delay := sampleDelayMs(delayRange)
+				assert.GreaterOrEqual(t, delay, 20)
+				assert.LessOrEqual(t, delay, 80)
+				seen[delay] = true
+			}
+			assert.Greater(t, len(seen), 10, "delays should vary across the range")
+		})
+	}
+
+	t.Run("Empty range", func(t *testing.T) {
+		assert.Equal(t, 40, sampleDelayMs(&database.DelayRange{MinMs: 40, MaxMs: 40}))
+	})
+}
+
+func TestSampleDelayMs_NormalIsClamped(t *testing.T) {
+	original := randomNormFloat64
+	t.Cleanup(func() { randomNormFloat64 = original })
+	delayRange := &database.DelayRange{MinMs: 100, MaxMs: 160, Distribution: database.DelayDistributionNormal}
+
+	randomNormFloat64 = func() float64 { return 0 }
+	assert.Equal(t, 130, sampleDelayMs(delayRange))
+	randomNormFloat64 = func() float64 { return 1 }
+	assert.Equal(t, 140, sampleDelayMs(delayRange))
+	randomNormFloat64 = func() float64 { return 10 }
+	assert.Equal(t, 160, sampleDelayMs(delayRange))
+	randomNormFloat64 = func() float64 { return -10 }
+	assert.Equal(t, 100, sampleDelayMs(delayRange))
+}
+
+func TestResolveDelayMs_DelayRangePriority(t *testing.T) {
+	stubRandomIntn(t, func(n int) int { return n - 1 }) // Always the top of the range
+	service := &MockService{}
+	project := &database.Project{AdvanceConfig: `{"delayMs": 5, "delayRange": {"minMs": 10, "maxMs": 20}}`}
+	endpoint := &database.MockEndpoint{AdvanceConfig: `{"delayRange": {"minMs": 30, "maxMs": 40}}`}
+
+	assert.Equal(t, 20, service.resolveDelayMs(project, nil, nil), "project range wins over project delayMs")
+	assert.Equal(t, 40, service.resolveDelayMs(project, endpoint, nil), "endpoint range overrides project")
+	assert.Equal(t, 7, service.resolveDelayMs(project, endpoint, &database.MockResponse{DelayMS: 7}), "response delay overrides endpoint")
+	assert.Equal(t, 60, service.resolveDelayMs(project, endpoint, &database.MockResponse{
+		DelayMS:       7,
+		AdvanceConfig: `{"delayRange": {"minMs": 50, "maxMs": 60}}`,
+	}), "response range overrides everything")
+	assert.Equal(t, 40, service.resolveDelayMs(project, &database.MockEndpoint{AdvanceConfig: `{"delayMs": 40}`}, nil), "fixed endpoint delay overrides project range")
+}
+
+func TestSampleLatencyProfileMs(t *testing.T) {
+	original := randomFloat64
+	t.Cleanup(func() { randomFloat64 = original })
+	sample := func(profile *database.LatencyProfile, quantile float64) int {
+		randomFloat64 = func() float64 { return quantile }
+		return sampleLatencyProfileMs(profile)
+	}
+	profile := &database.LatencyProfile{P50Ms: 20, P95Ms: 200, P99Ms: 1000}
+
+	assert.Equal(t, 0, sample(profile, 0))
+	assert.Equal(t, 10, sample(profile, 0.25))
+	assert.Equal(t, 20, sample(profile, 0.5))
+	assert.Equal(t, 110, sample(profile, 0.725), "p90 is omitted, so p50 to p95 is one segment")
+	assert.Equal(t, 200, sample(profile, 0.95))
+	assert.Equal(t, 1000, sample(profile, 0.99))
+	assert.Equal(t, 1000, sample(profile, 0.999), "the tail is capped at the highest percentile by default")
+
+	withMax := &database.LatencyProfile{MinMs: 10, P50Ms: 20, P99Ms: 1000, MaxMs: 3000}
+	assert.Equal(t, 15, sample(withMax, 0.25))
+	assert.Equal(t, 2000, sample(withMax, 0.995))
+
+	t.Run("Samples follow the percentiles", func(t *testing.T) {
+		randomFloat64 = original
+		var under50, under95, under99 int
+		const n = 20000
+		for i := 0; i < n; i++ {
+			delay := sampleLatencyProfileMs(profile)
+			if delay <= 20 {
+				under50++
+			}
+			if delay <= 200 {
+				under95++
+			}
+			if delay <= 1000 {
+				under99++
+			}
+		}
+		assert.InDelta(t, 0.50, float64(under50)/n, 0.02)
+		assert.InDelta(t, 0.95, float64(under95)/n, 0.01)
+		assert.Equal(t, n, under99)
+	})
+}
+
+func TestResolveDelayMs_LatencyProfile(t *testing.T) {
+	original := randomFloat64
+	randomFloat64 = func() float64 { return 0.95 }
+	t.Cleanup(func() { randomFloat64 = original })
+	service := &MockService{}
+	project := &database.Project{AdvanceConfig: `{"delayMs": 5}`}
+	endpoint := &database.MockEndpoint{AdvanceConfig: `{"delayMs": 40, "latencyProfile": {"p50Ms": 20, "p95Ms": 200}}`}
+
+	assert.Equal(t, 200, service.resolveDelayMs(project, endpoint, nil), "the profile replaces the endpoint delayMs")
+	assert.Equal(t, 7, service.resolveDelayMs(project, endpoint, &database.MockResponse{DelayMS: 7}), "response delay overrides the profile")
+	assert.Equal(t, 40, service.resolveDelayMs(project, &database.MockEndpoint{AdvanceConfig: `{"delayMs": 40}`}, nil), "fixed delays are unchanged without a profile")
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// Device classes produced by classifyUserAgent
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// Substrings (lowercase) identifying each class, checked in order bot, tablet, mobile
+var (
+	botUserAgentMarkers = []string{
+		"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "httpclient",
+		"go-http-client", "okhttp", "postmanruntime", "insomnia", "headless", "lighthouse", "facebookexternalhit",
+	}
+	tabletUserAgentMarkers = []string{"ipad", "tablet", "kindle", "silk/", "playbook", "nexus 7", "nexus 10", "sm-t"}
+	mobileUserAgentMarkers = []string{
+		"mobi", "iphone", "ipod", "android", "windows phone", "blackberry", "bb10", "opera mini", "iemobile",
+	}
+)
+
+// classifyUserAgent derives a device class from a User-Agent string.
+// Android devices without "mobile" in the string are tablets; an empty User-Agent counts as a bot.
+func classifyUserAgent(userAgent string) string {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return DeviceBot
+	}
+
+	if containsAny(ua, botUserAgentMarkers) {
+		return DeviceBot
+	}
+	if containsAny(ua, tabletUserAgentMarkers) || (strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")) {
+		return DeviceTablet
+	}
+	if containsAny(ua, mobileUserAgentMarkers) {
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
+
+// matchDeviceRule compares the request's device class with the rule value (e.g. equals "mobile")
+func matchDeviceRule(rule database.MockRule, req *http.Request) bool {
+	return matchRuleValue(rule.Operator, classifyUserAgent(req.UserAgent()), strings.ToLower(strings.TrimSpace(rule.Value)))
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestClassifyUserAgent(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":      DeviceMobile,
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36":  DeviceMobile,
+		"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":               DeviceTablet,
+		"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 Chrome/120.0 Safari/537.36":         DeviceTablet,
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36":        DeviceDesktop,
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15 Version/17.0 Safari/605.1.15": DeviceDesktop,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                       DeviceBot,
+		"curl/8.4.0": DeviceBot,
+		"":           DeviceBot,
+	}
+
+	for userAgent, expected := range tests {
+		t.Run(expected+" "+userAgent, func(t *testing.T) {
+			assert.Equal(t, expected, classifyUserAgent(userAgent))
+		})
+	}
+}
+
+func TestMatchesRules_Device(t *testing.T) {
+	response := database.MockResponse{Rules: []database.MockRule{{Type: "device", Operator: "equals", Value: "Mobile"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/feed", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148")
+	assert.True(t, matchesRules(response, req))
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0")
+	assert.False(t, matchesRules(response, req))
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestHandleRequest_DisabledResponses(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "disabled-responses")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/orders")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	low := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "low"})
+	high := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "high", Priority: 10})
+	disable := func(response database.MockResponse) {
+		require.NoError(t, database.DB.Model(&response).Update("enabled", false).Error)
+	}
+
+	call := func() (*http.Response, string, bool) {
+		req := httptest.NewRequest(http.MethodGet, "/disabled-responses/orders", nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/orders", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body), matched
+	}
+
+	t.Run("Disabled responses are skipped before priority", func(t *testing.T) {
+		disable(high)
+		_, body, _ := call()
+		assert.Equal(t, "low", body)
+	})
+
+	t.Run("All disabled serves the no-response default", func(t *testing.T) {
+		disable(low)
+		resp, body, _ := call()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"message": "`+systemConfig.DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED+`"}`, body)
+	})
+
+	t.Run("All disabled in proxy mode forwards the request", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("upstream"))
+		}))
+		defer upstream.Close()
+		project.Mode = database.ModeProxy
+		project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+		defer func() { project.Mode, project.ActiveProxy = database.ModeMock, nil }()
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		resp, matched, err := service.handleProxyMode(context.Background(), project, http.MethodGet, "/orders", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.False(t, matched)
+		assert.Equal(t, "upstream", string(body))
+	})
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// environmentHeader selects the environment for a single request
+const environmentHeader = "X-Env"
+
+// requestEnvironment returns the environment used for response selection: the X-Env header,
+// otherwise the MOCK_ENVIRONMENT system config of this server ("" when neither is set)
+func requestEnvironment(req *http.Request) string {
+	if req != nil {
+		if env := strings.TrimSpace(req.Header.Get(environmentHeader)); env != "" {
+			return env
+		}
+	}
+	if database.DB == nil {
+		return ""
+	}
+	env, err := systemConfig.GetSystemConfigWithType[string](systemConfig.MOCK_ENVIRONMENT)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(env)
+}
+
+// filterResponsesByEnvironment narrows responses to the current environment. Responses tagged
+// with the environment win; otherwise untagged responses are used. When every response is
+// tagged for other environments none is left, so the endpoint answers as if it had no response.
+func filterResponsesByEnvironment(responses []database.MockResponse, env string) []database.MockResponse {
+	return filterResponsesByTag(responses, responseEnvironments, env)
+}
+
+// filterResponsesByTag keeps the responses whose tags (from tagsOf) contain value, case-insensitively.
+// Without such responses the untagged ones are kept; responses tagged for other values never are.
+func filterResponsesByTag(responses []database.MockResponse, tagsOf func(database.MockResponse) []string, value string) []database.MockResponse {
+	var tagged, untagged []database.MockResponse
+	for _, response := range responses {
+		tags := tagsOf(response)
+		if len(tags) == 0 {
+			untagged = append(untagged, response)
+			continue
+		}
+		if value != "" && containsFold(tags, value) {
+			tagged = append(tagged, response)
+		}
+	}
+
+	if len(tagged) > 0 {
+		return tagged
+	}
+	return untagged
+}
+
+// responseEnvironments returns the environment tags of a response
+func responseEnvironments(response database.MockResponse) []string {
+	if response.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err != nil {
+		return nil
+	}
+	return config.Environments
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
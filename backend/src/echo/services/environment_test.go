@@ -0,0 +1,77 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestFilterResponsesByEnvironment(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "default"},
+		{ID: "staging", AdvanceConfig: `{"environments": ["staging"]}`},
+		{ID: "dev", AdvanceConfig: `{"environments": ["dev", "local"]}`},
+	}
+
+	ids := func(filtered []database.MockResponse) []string {
+		var result []string
+		for _, response := range filtered {
+			result = append(result, response.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"staging"}, ids(filterResponsesByEnvironment(responses, "Staging")))
+	assert.Equal(t, []string{"dev"}, ids(filterResponsesByEnvironment(responses, "local")))
+	assert.Equal(t, []string{"default"}, ids(filterResponsesByEnvironment(responses, "prod")))
+	assert.Equal(t, []string{"default"}, ids(filterResponsesByEnvironment(responses, "")))
+
+	onlyTagged := responses[1:]
+	assert.Empty(t, filterResponsesByEnvironment(onlyTagged, "prod"), "responses of other environments are never served")
+	assert.Empty(t, filterResponsesByEnvironment(onlyTagged, ""))
+}
+
+func TestSelectResponseWithEndpoint_Environment(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "default", Priority: 1},
+		{ID: "staging", AdvanceConfig: `{"environments": ["staging"]}`},
+	}
+
+	t.Run("X-Env header selects the tagged response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-Env", "staging")
+
+		selected := selectResponseWithEndpoint("endpoint-env", responses, "static", req)
+		require.NotNil(t, selected)
+		assert.Equal(t, "staging", selected.ID)
+	})
+
+	t.Run("Server environment is used without the header", func(t *testing.T) {
+		database.SetupTestEnvironment(t)
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.MOCK_ENVIRONMENT, "staging"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.MOCK_ENVIRONMENT, "") })
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+
+		selected := selectResponseWithEndpoint("endpoint-env", responses, "static", req)
+		require.NotNil(t, selected)
+		assert.Equal(t, "staging", selected.ID)
+
+		req.Header.Set("X-Env", "dev")
+		selected = selectResponseWithEndpoint("endpoint-env", responses, "static", req)
+		assert.Equal(t, "default", selected.ID, "header takes precedence over the server environment")
+	})
+
+	t.Run("Responses of other environments are not served", func(t *testing.T) {
+		production := []database.MockResponse{{ID: "production", AdvanceConfig: `{"environments": ["production"]}`}}
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-Env", "staging")
+
+		assert.Nil(t, selectResponseWithEndpoint("endpoint-env-other", production, "static", req))
+	})
+}
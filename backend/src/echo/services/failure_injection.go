@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+)
+
+// injectedFailureHeader records on the response (and so in request logs) which kind of failure was injected
+const injectedFailureHeader = "beo-echo-injected-failure"
+
+// statusConnectionAborted is the non-standard 444 status (as used by nginx) logged for
+// connections closed without a response
+const statusConnectionAborted = 444
+
+// AbortedBody is implemented by the body of a response that must not be sent: the mock handler
+// closes the client connection instead, so the client sees a reset or an empty reply.
+type AbortedBody interface {
+	io.ReadCloser
+	AbortConnection()
+}
+
+// abortBody is the empty body of an aborted response
+type abortBody struct {
+	*bytes.Reader
+}
+
+func (b *abortBody) Close() error { return nil }
+
+// AbortConnection marks abortBody as an AbortedBody
+func (b *abortBody) AbortConnection() {}
+
+// injectFailure rolls the endpoint's failureInjection for the request and returns the failure
+// response when it triggers, nil otherwise. The roll uses the random source of random-mode
+// selection, so beo-echo-random-seed makes it reproducible.
+func injectFailure(endpoint *database.MockEndpoint, req *http.Request) *http.Response {
+	if endpoint == nil || endpoint.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil || config.FailureInjection == nil || config.FailureInjection.Rate <= 0 {
+		return nil
+	}
+	failure := config.FailureInjection
+	if pickRandomFloat(req) >= failure.Rate {
+		return nil
+	}
+
+	if failure.Kind == database.FailureKindAbort {
+		resp := &http.Response{
+			StatusCode: statusConnectionAborted,
+			Body:       &abortBody{Reader: bytes.NewReader(nil)},
+			Header:     make(http.Header),
+		}
+		resp.Header.Set(injectedFailureHeader, database.FailureKindAbort)
+		return resp
+	}
+
+	resp := createCustomResponse(failure.Response, http.StatusInternalServerError, "Injected failure")
+	resp.Header.Set(injectedFailureHeader, database.FailureKindStatus)
+	return resp
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestInjectFailure(t *testing.T) {
+	endpoint := func(config string) *database.MockEndpoint {
+		return &database.MockEndpoint{AdvanceConfig: config}
+	}
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+
+	t.Run("Not configured", func(t *testing.T) {
+		assert.Nil(t, injectFailure(endpoint(""), req))
+		assert.Nil(t, injectFailure(endpoint(`{"failureInjection": {"rate": 0}}`), req))
+	})
+
+	t.Run("Triggered below the rate", func(t *testing.T) {
+		stubRandomFloat64(t, 0.29)
+
+		resp := injectFailure(endpoint(`{"failureInjection": {"rate": 0.3}}`), req)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "status", resp.Header.Get(injectedFailureHeader))
+
+		stubRandomFloat64(t, 0.3)
+		assert.Nil(t, injectFailure(endpoint(`{"failureInjection": {"rate": 0.3}}`), req))
+	})
+
+	t.Run("Custom failure response", func(t *testing.T) {
+		resp := injectFailure(endpoint(`{"failureInjection": {"rate": 1, "response": {"statusCode": 503, "body": "{\"down\":true}"}}}`), req)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		var body map[string]bool
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.True(t, body["down"])
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		resp := injectFailure(endpoint(`{"failureInjection": {"rate": 1, "kind": "abort"}}`), req)
+		require.NotNil(t, resp)
+		_, aborted := resp.Body.(AbortedBody)
+		assert.True(t, aborted)
+		assert.Equal(t, statusConnectionAborted, resp.StatusCode)
+		assert.Equal(t, "abort", resp.Header.Get(injectedFailureHeader))
+	})
+}
+
+func TestHandleRequest_FailureInjectionAppliesDelay(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "failure-injection")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/flaky")
+	require.NoError(t, err)
+	endpoint.AdvanceConfig = `{"delayMs": 30, "failureInjection": {"rate": 1}}`
+	require.NoError(t, database.DB.Save(endpoint).Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+	req := httptest.NewRequest(http.MethodGet, "/failure-injection/flaky", nil)
+	start := time.Now()
+	resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/flaky", req)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	assert.True(t, matched)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "Injected failure")
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beo-echo/backend/src/database"
+)
+
+// fixtureProjectKey stores the project ID in the request context so fixture rules know which
+// project's store to read
+type fixtureProjectKey struct{}
+
+// fixturePlaceholder matches {{fixture.<key>}} in response bodies
+var fixturePlaceholder = regexp.MustCompile(`\{\{fixture\.([^}]+)\}\}`)
+
+// requestBodyPlaceholder matches {{request.body}} and {{request.body.<path>}} in fixture values
+var requestBodyPlaceholder = regexp.MustCompile(`\{\{request\.body(?:\.([^}]+))?\}\}`)
+
+// ErrFixtureStoreUnavailable is returned when the fixture store is used without a database
+var ErrFixtureStoreUnavailable = errors.New("fixture store is not available")
+
+// withFixtureProject returns a request whose context carries the project ID for fixture rules
+func withFixtureProject(req *http.Request, projectID string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), fixtureProjectKey{}, projectID))
+}
+
+// fixtureProject returns the project ID stored by withFixtureProject
+func fixtureProject(req *http.Request) string {
+	projectID, _ := req.Context().Value(fixtureProjectKey{}).(string)
+	return projectID
+}
+
+// GetFixture returns the stored value for the key and whether it exists
+func GetFixture(projectID, key string) (string, bool, error) {
+	if database.DB == nil {
+		return "", false, ErrFixtureStoreUnavailable
+	}
+
+	var entry database.FixtureEntry
+	err := database.DB.Where("project_id = ? AND key = ?", projectID, key).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return entry.Value, true, nil
+}
+
+// SetFixture creates or replaces the value for the key
+func SetFixture(projectID, key, value string) error {
+	if database.DB == nil {
+		return ErrFixtureStoreUnavailable
+	}
+	if key == "" {
+		return errors.New("fixture key is required")
+	}
+
+	entry := database.FixtureEntry{ProjectID: projectID, Key: key, Value: value}
+	return database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&entry).Error
+}
+
+// PatchFixture applies a JSON Merge Patch to the value of the key and returns the new value. A
+// missing key is patched as an empty document. Fails with ErrInvalidMergePatch when the patch or
+// the stored value isn't JSON.
+func PatchFixture(projectID, key string, patch []byte) (string, error) {
+	if database.DB == nil {
+		return "", ErrFixtureStoreUnavailable
+	}
+	if key == "" {
+		return "", errors.New("fixture key is required")
+	}
+
+	var patched []byte
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var entry database.FixtureEntry
+		err := tx.Where("project_id = ? AND key = ?", projectID, key).First(&entry).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if patched, err = applyJSONMergePatch([]byte(entry.Value), patch); err != nil {
+			return err
+		}
+
+		entry = database.FixtureEntry{ProjectID: projectID, Key: key, Value: string(patched)}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "project_id"}, {Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&entry).Error
+	})
+	return string(patched), err
+}
+
+// DeleteFixture removes the key; deleting a missing key is not an error
+func DeleteFixture(projectID, key string) error {
+	if database.DB == nil {
+		return ErrFixtureStoreUnavailable
+	}
+	return database.DB.Where("project_id = ? AND key = ?", projectID, key).Delete(&database.FixtureEntry{}).Error
+}
+
+// ListFixtures returns all entries of the project's store ordered by key
+func ListFixtures(projectID string) ([]database.FixtureEntry, error) {
+	if database.DB == nil {
+		return nil, ErrFixtureStoreUnavailable
+	}
+	var entries []database.FixtureEntry
+	err := database.DB.Where("project_id = ?", projectID).Order("key").Find(&entries).Error
+	return entries, err
+}
+
+// ResetFixtures clears the project's store
+func ResetFixtures(projectID string) error {
+	if database.DB == nil {
+		return ErrFixtureStoreUnavailable
+	}
+	return database.DB.Where("project_id = ?", projectID).Delete(&database.FixtureEntry{}).Error
+}
+
+// matchFixtureRule compares the stored value of the rule key; a missing key compares as empty
+func matchFixtureRule(rule database.MockRule, req *http.Request) bool {
+	projectID := fixtureProject(req)
+	if projectID == "" {
+		return false
+	}
+	value, _, err := GetFixture(projectID, rule.Key)
+	if err != nil {
+		return false
+	}
+	return matchRuleValue(rule.Operator, value, rule.Value)
+}
+
+// applyFixtures runs the response's fixtureSet/fixtureDelete writes, then fills {{fixture.<key>}}
+// in the body with the stored values (empty for missing keys). Writes happen first so a response
+// can echo what it just stored. Templated responses keep their placeholders, which the template
+// reads through the fixture function so stored values are never parsed as template code.
+// Responses without fixture options or placeholders are untouched.
+func applyFixtures(projectID string, response *database.MockResponse, req *http.Request) *database.MockResponse {
+	if response == nil {
+		return nil
+	}
+
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err == nil {
+		for key, value := range config.FixtureSet {
+			if err := SetFixture(projectID, key, renderFixtureValue(value, req)); err != nil {
+				log.Error().Err(err).Str("project_id", projectID).Str("key", key).Msg("failed to write fixture")
+			}
+		}
+		for _, key := range config.FixtureDelete {
+			if err := DeleteFixture(projectID, key); err != nil {
+				log.Error().Err(err).Str("project_id", projectID).Str("key", key).Msg("failed to delete fixture")
+			}
+		}
+	}
+
+	if (err == nil && config.Templated) || !strings.Contains(response.Body, "{{fixture.") {
+		return response
+	}
+
+	rendered := *response
+	rendered.Body = fixturePlaceholder.ReplaceAllStringFunc(response.Body, func(placeholder string) string {
+		key := fixturePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, _, _ := GetFixture(projectID, key)
+		return value
+	})
+	return &rendered
+}
+
+// fixturePlaceholdersToCalls rewrites the {{fixture.<key>}} placeholders of a template into
+// {{fixture "<key>"}} calls, so the stored value is template output rather than template source
+func fixturePlaceholdersToCalls(text string) string {
+	if !strings.Contains(text, "{{fixture.") {
+		return text
+	}
+	return fixturePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		key := fixturePlaceholder.FindStringSubmatch(placeholder)[1]
+		return "{{fixture " + strconv.Quote(key) + "}}"
+	})
+}
+
+// renderFixtureValue fills {{request.body}} with the raw request body and {{request.body.<path>}}
+// with a field of a JSON body, so writes can capture what the client sent
+func renderFixtureValue(value string, req *http.Request) string {
+	if req == nil || req.Body == nil || !strings.Contains(value, "{{request.body") {
+		return value
+	}
+
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil {
+		return value
+	}
+	bodyData, _ := requestBodyObject(req)
+
+	return requestBodyPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		path := requestBodyPlaceholder.FindStringSubmatch(placeholder)[1]
+		if path == "" {
+			return string(bodyBytes)
+		}
+		return getNestedValue(bodyData, path)
+	})
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestFixtureStore(t *testing.T) {
+	_, project := setupHandleRequestTest(t, "fixture-store")
+
+	require.NoError(t, SetFixture(project.ID, "cart", `["sku-1"]`))
+	require.NoError(t, SetFixture(project.ID, "cart", `["sku-1","sku-2"]`), "set replaces an existing key")
+	require.NoError(t, SetFixture(project.ID, "session", "abc"))
+
+	value, ok, err := GetFixture(project.ID, "cart")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `["sku-1","sku-2"]`, value)
+
+	_, ok, err = GetFixture("other-project", "cart")
+	require.NoError(t, err)
+	assert.False(t, ok, "stores are scoped per project")
+
+	require.NoError(t, DeleteFixture(project.ID, "session"))
+	require.NoError(t, DeleteFixture(project.ID, "session"), "deleting a missing key is fine")
+	entries, err := ListFixtures(project.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "cart", entries[0].Key)
+
+	require.NoError(t, ResetFixtures(project.ID))
+	entries, err = ListFixtures(project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPatchFixture(t *testing.T) {
+	_, project := setupHandleRequestTest(t, "fixture-patch")
+	require.NoError(t, SetFixture(project.ID, "order", `{"status":"pending","items":["sku-1"],"coupon":"SAVE10"}`))
+
+	value, err := PatchFixture(project.ID, "order", []byte(`{"status":"shipped","coupon":null}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"shipped","items":["sku-1"]}`, value)
+	stored, _, _ := GetFixture(project.ID, "order")
+	assert.JSONEq(t, value, stored)
+
+	value, err = PatchFixture(project.ID, "profile", []byte(`{"name":"Ada"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Ada"}`, value, "missing keys start from an empty document")
+
+	require.NoError(t, SetFixture(project.ID, "session", "abc"))
+	_, err = PatchFixture(project.ID, "session", []byte(`{"a":1}`))
+	assert.ErrorIs(t, err, ErrInvalidMergePatch, "non-JSON values can't be patched")
+	_, err = PatchFixture(project.ID, "order", []byte(`{invalid`))
+	assert.ErrorIs(t, err, ErrInvalidMergePatch)
+	stored, _, _ = GetFixture(project.ID, "session")
+	assert.Equal(t, "abc", stored, "failed patches leave the value alone")
+}
+
+func TestHandleRequest_Fixtures(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "fixture-mock")
+
+	addEndpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/cart")
+	require.NoError(t, err)
+	createTestResponse(t, addEndpoint.ID, database.MockResponse{
+		StatusCode:    201,
+		Body:          `{"item":"{{fixture.lastItem}}"}`,
+		AdvanceConfig: `{"fixtureSet": {"lastItem": "{{request.body.sku}}", "cart": "{{request.body}}"}}`,
+	})
+
+	getEndpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/cart")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(getEndpoint).Update("response_mode", "static").Error)
+	empty := createTestResponse(t, getEndpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"empty":true}`, Priority: 1})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: empty.ID, Type: "fixture", Key: "cart", Operator: "equals", Value: ""}).Error)
+	createTestResponse(t, getEndpoint.ID, database.MockResponse{StatusCode: 200, Body: `{{fixture.cart}}`})
+
+	clearEndpoint, err := database.CreateTestEndpoint(project.ID, "DELETE", "/cart")
+	require.NoError(t, err)
+	createTestResponse(t, clearEndpoint.ID, database.MockResponse{StatusCode: 204, AdvanceConfig: `{"fixtureDelete": ["cart", "lastItem"]}`})
+
+	call := func(method, body string) string {
+		req := httptest.NewRequest(method, "/fixture-mock/cart", strings.NewReader(body))
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, method, "/cart", req)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	assert.JSONEq(t, `{"empty":true}`, call(http.MethodGet, ""), "fixture rule sees the missing key as empty")
+	assert.JSONEq(t, `{"item":"sku-9"}`, call(http.MethodPost, `{"sku":"sku-9"}`), "writes are visible to the same response")
+	assert.JSONEq(t, `{"sku":"sku-9"}`, call(http.MethodGet, ""), "state persists across requests")
+
+	call(http.MethodDelete, "")
+	assert.JSONEq(t, `{"empty":true}`, call(http.MethodGet, ""))
+}
+
+func TestHandleRequest_TemplatedFixtures(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "fixture-template")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/notes")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    201,
+		Body:          `{{fixture.note}} via {{.Method}}`,
+		AdvanceConfig: `{"templated": true, "fixtureSet": {"note": "{{request.body}}"}}`,
+	})
+
+	body := `{{.Headers}} {{fixture "note"}}`
+	req := httptest.NewRequest(http.MethodPost, "/fixture-template/notes", strings.NewReader(body))
+	resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodPost, "/notes", req)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, body+" via POST", string(data), "stored values are rendered as text, not executed")
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"beo-echo/backend/src/database"
+)
+
+// requestForm is a decoded form body: field values, and the file names sent in multipart file fields
+type requestForm struct {
+	values url.Values
+	files  map[string][]string
+}
+
+// requestBodyForm decodes application/x-www-form-urlencoded and multipart/form-data bodies from
+// the cached body. It reports false for other content types and for bodies that fail to parse.
+func requestBodyForm(req *http.Request) (*requestForm, bool) {
+	cache, ok := req.Context().Value(requestBodyKey{}).(*requestBodyCache)
+	if !ok {
+		bodyBytes, err := requestBodyBytes(req)
+		if err != nil {
+			return nil, false
+		}
+		form := parseRequestForm(req.Header.Get("Content-Type"), bodyBytes)
+		return form, form != nil
+	}
+
+	cache.formOnce.Do(func() {
+		if cache.err == nil {
+			cache.form = parseRequestForm(req.Header.Get("Content-Type"), cache.bytes)
+		}
+	})
+	return cache.form, cache.form != nil
+}
+
+// parseRequestForm decodes the body according to its Content-Type, nil when it isn't a form
+func parseRequestForm(contentType string, body []byte) *requestForm {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil
+		}
+		return &requestForm{values: values, files: map[string][]string{}}
+	case "multipart/form-data":
+		if params["boundary"] == "" {
+			return nil
+		}
+		return parseMultipartForm(body, params["boundary"])
+	default:
+		return nil
+	}
+}
+
+// parseMultipartForm reads every part; file contents are skipped, only their names are kept
+func parseMultipartForm(body []byte, boundary string) *requestForm {
+	form := &requestForm{values: url.Values{}, files: map[string][]string{}}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form
+		}
+		if err != nil {
+			return nil
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		if part.FileName() != "" {
+			form.files[name] = append(form.files[name], part.FileName())
+			if _, err := io.Copy(io.Discard, part); err != nil {
+				return nil
+			}
+			continue
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil
+		}
+		form.values.Add(name, string(value))
+	}
+}
+
+// has reports whether the field was sent as a value or a file
+func (f *requestForm) has(field string) bool {
+	_, isValue := f.values[field]
+	_, isFile := f.files[field]
+	return isValue || isFile
+}
+
+// matchFormRule compares a form field with the rule. For file fields the file name is compared,
+// so "contains .pdf" matches an uploaded PDF. A missing field compares as empty.
+func matchFormRule(rule database.MockRule, form *requestForm) bool {
+	if names, ok := form.files[rule.Key]; ok {
+		return matchSelectedValues(rule.Operator, names, rule.Value)
+	}
+	if values, ok := form.values[rule.Key]; ok {
+		return matchSelectedValues(rule.Operator, values, rule.Value)
+	}
+	return matchRuleValue(rule.Operator, "", rule.Value)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchBodyRule_Form(t *testing.T) {
+	t.Run("URL-encoded fields", func(t *testing.T) {
+		newReq := func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("username=ada&grant_type=password&scope="))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+			return withRequestBody(req)
+		}
+
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "grant_type", Operator: "equals", Value: "password"}, newReq()))
+		assert.False(t, matchBodyRule(database.MockRule{Type: "body", Key: "grant_type", Operator: "equals", Value: "refresh_token"}, newReq()))
+		assert.False(t, matchBodyRule(database.MockRule{Type: "body", Key: "username", Operator: "contains", Value: "grant_type"}, newReq()), "fields are matched, not the raw body")
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "scope", Operator: "exists"}, newReq()), "an empty field is still sent")
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "client_id", Operator: "not_exists"}, newReq()))
+	})
+
+	t.Run("Multipart fields and files", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("title", "Quarterly report"))
+		file, err := writer.CreateFormFile("attachment", "report.pdf")
+		require.NoError(t, err)
+		_, err = file.Write([]byte("%PDF-1.7"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		newReq := func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body.Bytes()))
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			return withRequestBody(req)
+		}
+
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "title", Operator: "equals", Value: "Quarterly report"}, newReq()))
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "attachment", Operator: "exists"}, newReq()))
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "attachment", Operator: "contains", Value: ".pdf"}, newReq()), "file fields compare the file name")
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "avatar", Operator: "not_exists"}, newReq()))
+	})
+
+	t.Run("Form parse is shared across rules", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = withRequestBody(req)
+
+		first, ok := requestBodyForm(req)
+		require.True(t, ok)
+		second, _ := requestBodyForm(req)
+		assert.Same(t, first, second)
+	})
+
+	t.Run("JSON bodies support presence operators too", func(t *testing.T) {
+		newReq := func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"user":{"id":1}}`))
+		}
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "user.id", Operator: "exists"}, newReq()))
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "user.name", Operator: "not_exists"}, newReq()))
+	})
+}
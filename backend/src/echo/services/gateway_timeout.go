@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// respondWithGatewayTimeout waits for the configured duration and then answers 504, simulating
+// an upstream that hangs until the gateway gives up. A configured body and headers are kept with
+// the forced status; without a body the standard error JSON is used. A cancelled ctx (client gone)
+// aborts the wait early.
+func respondWithGatewayTimeout(ctx context.Context, response *database.MockResponse, timeoutMs int) (*http.Response, error) {
+	timer := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	if response.Body == "" {
+		return createErrorResponse(http.StatusGatewayTimeout, "Gateway Timeout: upstream did not respond in time"), nil
+	}
+
+	timedOut := *response
+	timedOut.StatusCode = http.StatusGatewayTimeout
+	timedOut.RedirectURL = ""
+	return createMockResponse(timedOut)
+}
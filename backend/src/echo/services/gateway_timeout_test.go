@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestRespondWithDelay_GatewayTimeout(t *testing.T) {
+	service := &MockService{}
+	project := &database.Project{}
+	endpoint := &database.MockEndpoint{}
+
+	t.Run("Waits then answers 504 with the configured body", func(t *testing.T) {
+		response := &database.MockResponse{
+			StatusCode:    200,
+			Body:          `{"error":"upstream timeout"}`,
+			AdvanceConfig: `{"timeoutAfterMs": 40}`,
+		}
+
+		start := time.Now()
+		resp, err := service.respondWithDelay(context.Background(), project, endpoint, response)
+
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"error":"upstream timeout"}`, string(body))
+	})
+
+	t.Run("Empty body falls back to the standard error response", func(t *testing.T) {
+		response := &database.MockResponse{StatusCode: 200, AdvanceConfig: `{"timeoutAfterMs": 1}`}
+
+		resp, err := service.respondWithDelay(context.Background(), project, endpoint, response)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "Gateway Timeout")
+	})
+
+	t.Run("Client disconnect aborts the wait", func(t *testing.T) {
+		response := &database.MockResponse{StatusCode: 200, Body: "late", AdvanceConfig: `{"timeoutAfterMs": 10000}`}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		resp, err := service.respondWithDelay(ctx, project, endpoint, response)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, resp)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestAdvanceConfigResponse_TimeoutAfterMsValidation(t *testing.T) {
+	_, err := database.ParseResponseAdvanceConfig(`{"timeoutAfterMs": -1}`)
+	assert.Error(t, err)
+	_, err = database.ParseResponseAdvanceConfig(`{"timeoutAfterMs": 120001}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// geoLocation is the country and region a network resolves to
+type geoLocation struct {
+	Country string
+	Region  string
+}
+
+type geoNetwork struct {
+	network  *net.IPNet
+	location geoLocation
+}
+
+// geoDatabase resolves IPs to locations from a CSV of network,country[,region] lines.
+// Lines starting with # and a "network,..." header are skipped. The most specific network wins.
+type geoDatabase struct {
+	networks []geoNetwork
+}
+
+// loadedGeoDatabase caches the database per path and modification time so edits are picked up
+var loadedGeoDatabase struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	db      *geoDatabase
+}
+
+// loadGeoDatabase parses a GeoIP CSV file
+func loadGeoDatabase(path string) (*geoDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	db := &geoDatabase{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(strings.ToLower(line), "network,") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("geoip database line %d: expected network,country[,region]", lineNumber)
+		}
+		_, network, err := net.ParseCIDR(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("geoip database line %d: %v", lineNumber, err)
+		}
+		location := geoLocation{Country: strings.ToUpper(strings.TrimSpace(fields[1]))}
+		if len(fields) > 2 {
+			location.Region = strings.ToUpper(strings.TrimSpace(fields[2]))
+		}
+		db.networks = append(db.networks, geoNetwork{network: network, location: location})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// lookup returns the location of the most specific network containing ip
+func (db *geoDatabase) lookup(ip net.IP) (geoLocation, bool) {
+	var best geoLocation
+	bestSize := -1
+	for _, entry := range db.networks {
+		if !entry.network.Contains(ip) {
+			continue
+		}
+		if size, _ := entry.network.Mask.Size(); size > bestSize {
+			best, bestSize = entry.location, size
+		}
+	}
+	return best, bestSize >= 0
+}
+
+// currentGeoDatabase returns the database configured by GEOIP_DATABASE_PATH, nil when not
+// configured or unreadable
+func currentGeoDatabase() *geoDatabase {
+	if database.DB == nil {
+		return nil
+	}
+	path, err := systemConfig.GetSystemConfigWithType[string](systemConfig.GEOIP_DATABASE_PATH)
+	if err != nil || strings.TrimSpace(path) == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	loadedGeoDatabase.mu.Lock()
+	defer loadedGeoDatabase.mu.Unlock()
+	if loadedGeoDatabase.db != nil && loadedGeoDatabase.path == path && loadedGeoDatabase.modTime.Equal(info.ModTime()) {
+		return loadedGeoDatabase.db
+	}
+
+	db, err := loadGeoDatabase(path)
+	if err != nil {
+		fmt.Println("Error loading GeoIP database:", err)
+		return nil
+	}
+	loadedGeoDatabase.path, loadedGeoDatabase.modTime, loadedGeoDatabase.db = path, info.ModTime(), db
+	return db
+}
+
+// matchGeoRule resolves the client IP and compares its country (rule key "country" or empty)
+// or region (rule key "region") code with the rule value, case-insensitively. Private and
+// loopback addresses, unknown networks and a missing database never match.
+func matchGeoRule(rule database.MockRule, req *http.Request) bool {
+	ip := clientIP(req)
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return false
+	}
+	db := currentGeoDatabase()
+	if db == nil {
+		return false
+	}
+	location, found := db.lookup(ip)
+	if !found {
+		return false
+	}
+
+	actual := location.Country
+	if strings.EqualFold(strings.TrimSpace(rule.Key), "region") {
+		actual = location.Region
+	}
+	if actual == "" {
+		return false
+	}
+	return matchRuleValue(rule.Operator, actual, strings.ToUpper(strings.TrimSpace(rule.Value)))
+}
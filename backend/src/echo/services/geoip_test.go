@@ -0,0 +1,49 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestMatchGeoRule(t *testing.T) {
+	database.SetupTestEnvironment(t)
+
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	require.NoError(t, os.WriteFile(path, []byte("network,country,region\n# documentation ranges\n203.0.113.0/24,id,JK\n203.0.113.128/25,SG\n2001:db8::/32,DE,BE\n"), 0o644))
+
+	newReq := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/prices", nil)
+		req.RemoteAddr = ip + ":5000"
+		return req
+	}
+	country := database.MockRule{Type: "geo", Operator: "equals", Value: "id"}
+
+	t.Run("Missing database never matches", func(t *testing.T) {
+		assert.False(t, matchGeoRule(country, newReq("203.0.113.10")))
+	})
+
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.GEOIP_DATABASE_PATH, path))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.GEOIP_DATABASE_PATH, "") })
+
+	assert.True(t, matchGeoRule(country, newReq("203.0.113.10")))
+	assert.True(t, matchGeoRule(database.MockRule{Type: "geo", Key: "region", Operator: "equals", Value: "jk"}, newReq("203.0.113.10")))
+	assert.False(t, matchGeoRule(country, newReq("203.0.113.200")), "the most specific network wins")
+	assert.True(t, matchGeoRule(database.MockRule{Type: "geo", Operator: "equals", Value: "DE"}, newReq("[2001:db8::1]")))
+	assert.False(t, matchGeoRule(country, newReq("198.51.100.1")), "unknown networks don't match")
+	assert.False(t, matchGeoRule(country, newReq("10.0.0.1")), "private addresses don't match")
+
+	t.Run("X-Forwarded-For takes precedence", func(t *testing.T) {
+		req := newReq("127.0.0.1")
+		req.Header.Set("X-Forwarded-For", "203.0.113.10, 10.0.0.1")
+		assert.True(t, matchGeoRule(country, req))
+	})
+}
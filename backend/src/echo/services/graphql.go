@@ -0,0 +1,238 @@
+package services
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// graphQLRequest is one operation of a GraphQL-over-HTTP request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLOperation is an operation definition found in a query document
+type graphQLOperation struct {
+	Type string // "query", "mutation" or "subscription"
+	Name string // Empty for anonymous operations
+}
+
+// matchGraphQLRule matches a GraphQL request. The key selects what is compared:
+//
+//	operationName (or empty)  the operationName, or the name of the operation in the query
+//	operationType             query, mutation or subscription
+//	query                     the query document
+//	variables.<path>          the variable values at the JSONPath, e.g. variables.input.id
+//
+// Batched requests match when any of their operations does.
+func matchGraphQLRule(rule database.MockRule, req *http.Request) bool {
+	requests, ok := parseGraphQLRequests(req)
+	if !ok {
+		return false
+	}
+
+	var values []string
+	found := false
+	for _, gql := range requests {
+		selected, ok := graphQLRuleValues(gql, rule.Key)
+		found = found || ok
+		values = append(values, selected...)
+	}
+
+	switch strings.ToLower(rule.Operator) {
+	case "exists":
+		return found
+	case "not_exists":
+		return !found
+	}
+	if !found {
+		return false
+	}
+	return matchSelectedValues(rule.Operator, values, rule.Value)
+}
+
+// graphQLRuleValues returns the values the rule key selects from one operation and whether
+// the key selected anything
+func graphQLRuleValues(gql graphQLRequest, key string) ([]string, bool) {
+	switch key = strings.TrimSpace(key); key {
+	case "", "operationName":
+		name := gql.OperationName
+		if name == "" {
+			if operation, ok := selectGraphQLOperation(gql); ok {
+				name = operation.Name
+			}
+		}
+		return []string{name}, true
+	case "operationType":
+		operation, ok := selectGraphQLOperation(gql)
+		if !ok {
+			return nil, false
+		}
+		return []string{operation.Type}, true
+	case "query":
+		return []string{gql.Query}, gql.Query != ""
+	}
+
+	if key != "variables" && !strings.HasPrefix(key, "variables.") && !strings.HasPrefix(key, "variables[") {
+		return nil, false
+	}
+	values, err := jsonPathValues(map[string]interface{}{"variables": gql.Variables}, key)
+	if err != nil || len(values) == 0 || (len(values) == 1 && values[0] == nil) {
+		return nil, false
+	}
+	selected := make([]string, len(values))
+	for i, value := range values {
+		selected[i] = stringifyJSONValue(value)
+	}
+	return selected, true
+}
+
+// parseGraphQLRequests reads the operations of a request: a JSON body with query,
+// operationName and variables (or an array of them for batches), an application/graphql body
+// holding the query, or the query parameters of a GET request
+func parseGraphQLRequests(req *http.Request) ([]graphQLRequest, bool) {
+	if req.Method == http.MethodGet {
+		params := req.URL.Query()
+		if params.Get("query") == "" {
+			return nil, false
+		}
+		gql := graphQLRequest{Query: params.Get("query"), OperationName: params.Get("operationName")}
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &gql.Variables); err != nil {
+				return nil, false
+			}
+		}
+		return []graphQLRequest{gql}, true
+	}
+
+	if req.Body == nil {
+		return nil, false
+	}
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil || len(bodyBytes) == 0 {
+		return nil, false
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "application/graphql" {
+		return []graphQLRequest{{Query: string(bodyBytes)}}, true
+	}
+
+	var batch []graphQLRequest
+	if err := json.Unmarshal(bodyBytes, &batch); err == nil {
+		return batch, len(batch) > 0
+	}
+	var gql graphQLRequest
+	if err := json.Unmarshal(bodyBytes, &gql); err != nil || (gql.Query == "" && gql.OperationName == "") {
+		return nil, false
+	}
+	return []graphQLRequest{gql}, true
+}
+
+// selectGraphQLOperation returns the operation a request executes: the one named by
+// operationName, or the first one in the document
+func selectGraphQLOperation(gql graphQLRequest) (graphQLOperation, bool) {
+	operations := parseGraphQLOperations(gql.Query)
+	for _, operation := range operations {
+		if gql.OperationName == "" || operation.Name == gql.OperationName {
+			return operation, true
+		}
+	}
+	return graphQLOperation{}, false
+}
+
+// parseGraphQLOperations lists the operation definitions of a query document. It only scans
+// the top level: selection sets, variable definitions, strings and comments are skipped, and
+// fragment definitions are not operations. A bare { ... } is an anonymous query.
+func parseGraphQLOperations(query string) []graphQLOperation {
+	var operations []graphQLOperation
+	var current *graphQLOperation
+	expectName, inFragment := false, false
+	braces, parens := 0, 0
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '"':
+			i = skipGraphQLString(query, i)
+			continue
+		case c == '{':
+			if braces == 0 && parens == 0 {
+				switch {
+				case inFragment:
+					inFragment = false
+				case current != nil:
+					operations = append(operations, *current)
+				default:
+					operations = append(operations, graphQLOperation{Type: "query"})
+				}
+				current, expectName = nil, false
+			}
+			braces++
+		case c == '}':
+			braces--
+		case c == '(':
+			parens++
+		case c == ')':
+			parens--
+		case isGraphQLNameStart(c):
+			start := i
+			for i < len(query) && isGraphQLNameChar(query[i]) {
+				i++
+			}
+			if braces == 0 && parens == 0 {
+				name := query[start:i]
+				switch {
+				case expectName:
+					current.Name, expectName = name, false
+				case inFragment || current != nil:
+				case name == "query" || name == "mutation" || name == "subscription":
+					current, expectName = &graphQLOperation{Type: name}, true
+				case name == "fragment":
+					inFragment = true
+				}
+			}
+			continue
+		case c > ' ' && c != ',':
+			expectName = false
+		}
+		i++
+	}
+	return operations
+}
+
+// skipGraphQLString returns the index after the string or block string starting at i
+func skipGraphQLString(query string, i int) int {
+	if strings.HasPrefix(query[i:], `"""`) {
+		if end := strings.Index(query[i+3:], `"""`); end >= 0 {
+			return i + 3 + end + 3
+		}
+		return len(query)
+	}
+	for i++; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return isGraphQLNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchGraphQLRule(t *testing.T) {
+	match := func(body, key, operator, value string) bool {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return matchGraphQLRule(database.MockRule{Type: "graphql", Key: key, Operator: operator, Value: value}, req)
+	}
+
+	getUser := `{"query": "query GetUser($id: ID!) { user(id: $id) { name } }", "operationName": "GetUser", "variables": {"id": "42", "filter": {"roles": ["admin", "billing"]}}}`
+
+	t.Run("Operation name", func(t *testing.T) {
+		assert.True(t, match(getUser, "operationName", "equals", "GetUser"))
+		assert.True(t, match(getUser, "", "equals", "GetUser"), "operationName is the default key")
+		assert.False(t, match(getUser, "operationName", "equals", "ListUsers"))
+	})
+
+	t.Run("Operation name comes from the query when not sent", func(t *testing.T) {
+		body := `{"query": "# fetch\nfragment F on User { id }\nmutation CreateUser($input: UserInput) { createUser(input: $input) { ...F } }"}`
+		assert.True(t, match(body, "operationName", "equals", "CreateUser"))
+		assert.True(t, match(body, "operationType", "equals", "mutation"))
+		assert.True(t, match(`{"query": "{ users { id } }"}`, "operationType", "equals", "query"), "shorthand is an anonymous query")
+		assert.True(t, match(`{"query": "{ users { id } }"}`, "operationName", "equals", ""))
+	})
+
+	t.Run("operationName picks the executed operation", func(t *testing.T) {
+		body := `{"query": "query A { a } mutation B { b }", "operationName": "B"}`
+		assert.True(t, match(body, "operationType", "equals", "mutation"))
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		assert.True(t, match(getUser, "variables.id", "equals", "42"))
+		assert.True(t, match(getUser, "variables.filter.roles[*]", "equals", "billing"))
+		assert.True(t, match(getUser, "variables.id", "exists", ""))
+		assert.True(t, match(getUser, "variables.missing", "not_exists", ""))
+		assert.False(t, match(getUser, "variables.missing", "not_equals", "x"), "variables selecting nothing never match")
+	})
+
+	t.Run("Batches match any operation", func(t *testing.T) {
+		batch := `[{"query": "query A { a }"}, {"query": "query B { b }"}]`
+		assert.True(t, match(batch, "operationName", "equals", "B"))
+		assert.False(t, match(batch, "operationName", "equals", "C"))
+	})
+
+	t.Run("GET requests and application/graphql bodies", func(t *testing.T) {
+		params := url.Values{"query": {"query GetUser { user { id } }"}, "variables": {`{"id": "7"}`}}
+		req := httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil)
+		assert.True(t, matchGraphQLRule(database.MockRule{Key: "operationName", Operator: "equals", Value: "GetUser"}, req))
+		assert.True(t, matchGraphQLRule(database.MockRule{Key: "variables.id", Operator: "equals", Value: "7"}, req))
+
+		req = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`query Ping { ping }`))
+		req.Header.Set("Content-Type", "application/graphql; charset=utf-8")
+		assert.True(t, matchGraphQLRule(database.MockRule{Key: "operationName", Operator: "equals", Value: "Ping"}, req))
+	})
+
+	t.Run("Non-GraphQL bodies never match", func(t *testing.T) {
+		assert.False(t, match(`{"name": "Ada"}`, "operationName", "equals", ""))
+		assert.False(t, match(`not json`, "query", "contains", "json"))
+		assert.False(t, match("", "operationName", "not_equals", "x"))
+	})
+}
+
+func TestParseGraphQLOperations(t *testing.T) {
+	query := `
+	query Search($term: String = "{ not a selection }") @cached { search(term: $term) { id } }
+	"""Creates a user named "query X" """
+	mutation{ create { id } }
+	subscription OnEvent { event }`
+
+	assert.Equal(t, []graphQLOperation{
+		{Type: "query", Name: "Search"},
+		{Type: "mutation"},
+		{Type: "subscription", Name: "OnEvent"},
+	}, parseGraphQLOperations(query))
+}
+
+func TestHandleRequest_GraphQLRule(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "graphql-rule")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/graphql")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"data": null}`})
+	user := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"data": {"user": {"id": "42"}}}`, Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: user.ID, Type: "graphql", Key: "operationName", Operator: "equals", Value: "GetUser"}).Error)
+	missing := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"errors": [{"message": "not found"}]}`, Priority: 20})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: missing.ID, Type: "graphql", Key: "operationName", Operator: "equals", Value: "GetUser"}).Error)
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: missing.ID, Type: "graphql", Key: "variables.id", Operator: "equals", Value: "0"}).Error)
+
+	call := func(body string) string {
+		req := httptest.NewRequest(http.MethodPost, "/graphql-rule/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodPost, "/graphql", req)
+		require.NoError(t, err)
+		respBody, _ := io.ReadAll(resp.Body)
+		return string(respBody)
+	}
+
+	assert.Equal(t, `{"data": {"user": {"id": "42"}}}`, call(`{"query": "query GetUser($id: ID!) { user(id: $id) { id } }", "variables": {"id": "42"}}`))
+	assert.Equal(t, `{"errors": [{"message": "not found"}]}`, call(`{"query": "query GetUser($id: ID!) { user(id: $id) { id } }", "variables": {"id": "0"}}`))
+	assert.Equal(t, `{"data": null}`, call(`{"query": "query ListUsers { users { id } }"}`))
+}
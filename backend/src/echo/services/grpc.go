@@ -0,0 +1,362 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"beo-echo/backend/src/database"
+)
+
+// grpcContentType is the content type of gRPC calls with protobuf messages
+const grpcContentType = "application/grpc"
+
+// grpcFrameHeaderLen is the length of the prefix of every gRPC message: a compressed flag byte
+// followed by the big-endian message length
+const grpcFrameHeaderLen = 5
+
+// gRPC status codes answered by the mock server, as defined by google.golang.org/grpc/codes
+const (
+	grpcStatusOK                 = 0
+	grpcStatusCancelled          = 1
+	grpcStatusUnknown            = 2
+	grpcStatusInvalidArgument    = 3
+	grpcStatusDeadlineExceeded   = 4
+	grpcStatusNotFound           = 5
+	grpcStatusPermissionDenied   = 7
+	grpcStatusResourceExhausted  = 8
+	grpcStatusFailedPrecondition = 9
+	grpcStatusAborted            = 10
+	grpcStatusUnimplemented      = 12
+	grpcStatusInternal           = 13
+	grpcStatusUnavailable        = 14
+	grpcStatusUnauthenticated    = 16
+)
+
+// grpcStatusByHTTPStatus maps the status of a non-2xx mock response to the gRPC status with the
+// same meaning (google.rpc.Code); unlisted statuses become UNKNOWN
+var grpcStatusByHTTPStatus = map[int]int{
+	http.StatusBadRequest:          grpcStatusInvalidArgument,
+	http.StatusUnauthorized:        grpcStatusUnauthenticated,
+	http.StatusForbidden:           grpcStatusPermissionDenied,
+	http.StatusNotFound:            grpcStatusNotFound,
+	http.StatusConflict:            grpcStatusAborted,
+	http.StatusPreconditionFailed:  grpcStatusFailedPrecondition,
+	http.StatusTooManyRequests:     grpcStatusResourceExhausted,
+	statusClientClosedRequest:      grpcStatusCancelled,
+	http.StatusInternalServerError: grpcStatusInternal,
+	http.StatusNotImplemented:      grpcStatusUnimplemented,
+	http.StatusServiceUnavailable:  grpcStatusUnavailable,
+	http.StatusGatewayTimeout:      grpcStatusDeadlineExceeded,
+}
+
+// grpcSkippedHeaders describe the JSON mock response and are not sent as gRPC metadata
+var grpcSkippedHeaders = map[string]bool{
+	"Content-Type":     true,
+	"Content-Length":   true,
+	"Content-Encoding": true,
+	"Grpc-Status":      true,
+	"Grpc-Message":     true,
+}
+
+// Parsed descriptor sets by their base64 text (string -> *protoregistry.Files)
+var grpcDescriptorCache sync.Map
+
+// errGRPCCompressed is returned for request messages with the compressed flag set
+var errGRPCCompressed = errors.New("compressed messages are not supported")
+
+// grpcJSONMarshal renders request messages with the field names of the .proto file, so rules
+// and templates use e.g. "user_id"
+var grpcJSONMarshal = protojson.MarshalOptions{UseProtoNames: true}
+
+// grpcJSONUnmarshal accepts both proto and JSON field names and ignores unknown fields, so a
+// response body may carry notes the message type doesn't define
+var grpcJSONUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// IsGRPCRequest reports whether the request is a gRPC call with protobuf messages
+func IsGRPCRequest(req *http.Request) bool {
+	if req.ProtoMajor != 2 || req.Method != http.MethodPost {
+		return false
+	}
+	contentType := strings.ToLower(req.Header.Get("Content-Type"))
+	return contentType == grpcContentType || strings.HasPrefix(contentType, grpcContentType+"+proto") || strings.HasPrefix(contentType, grpcContentType+";")
+}
+
+// HandleGRPCRequest answers a unary or server-streaming gRPC call to /<package.Service>/<Method>.
+// The request message is decoded with the project's descriptor set and handed to HandleRequest
+// as a JSON POST, so endpoint matching, rules, templates and delays work as for HTTP mocks. The
+// mock response is encoded with the method's output type; non-2xx statuses become gRPC errors, or
+// a grpc-status header on the response sets the code explicitly. Server-streaming calls get the
+// messages of the response's grpcStream config, or the body as a single message without one.
+func (s *MockService) HandleGRPCRequest(ctx context.Context, alias, path string, req *http.Request) (*http.Response, error, string, database.ProjectMode, bool) {
+	project, err := s.Repo.FindProjectByAlias(alias)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusNotFound, "project not found"), nil, "", "", false
+	}
+
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil || config.GRPC == nil {
+		return createGRPCStatusResponse(grpcStatusUnimplemented, "gRPC is not configured for this project"), nil, project.ID, project.Mode, false
+	}
+	if project.Mode != database.ModeMock && project.Mode != database.ModeDisabled {
+		return createGRPCStatusResponse(grpcStatusUnimplemented, "gRPC calls are only mocked in mock mode"), nil, project.ID, project.Mode, false
+	}
+
+	files, err := loadGRPCDescriptorSet(config.GRPC.DescriptorSet)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusInternal, "invalid descriptor set: "+err.Error()), nil, project.ID, project.Mode, false
+	}
+	method, err := findGRPCMethod(files, path)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusUnimplemented, err.Error()), nil, project.ID, project.Mode, false
+	}
+	if method.IsStreamingClient() {
+		return createGRPCStatusResponse(grpcStatusUnimplemented, "only unary and server-streaming calls are supported"), nil, project.ID, project.Mode, false
+	}
+
+	payload, err := readGRPCMessage(req)
+	if errors.Is(err, errRequestBodyTooLarge) {
+		return createGRPCStatusResponse(grpcStatusResourceExhausted, fmt.Sprintf("request message exceeds %d bytes", maxRequestBodyBytes())), nil, project.ID, project.Mode, false
+	}
+	if errors.Is(err, errGRPCCompressed) {
+		return createGRPCStatusResponse(grpcStatusUnimplemented, err.Error()), nil, project.ID, project.Mode, false
+	}
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusInternal, err.Error()), nil, project.ID, project.Mode, false
+	}
+	input := dynamicpb.NewMessage(method.Input())
+	if err := proto.Unmarshal(payload, input); err != nil {
+		return createGRPCStatusResponse(grpcStatusInvalidArgument, fmt.Sprintf("cannot decode %s: %v", method.Input().FullName(), err)), nil, project.ID, project.Mode, false
+	}
+	jsonBody, err := grpcJSONMarshal.Marshal(input)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusInternal, err.Error()), nil, project.ID, project.Mode, false
+	}
+
+	ctx = withGRPCMethod(ctx, method)
+	mockReq := req.Clone(ctx)
+	mockReq.Body = io.NopCloser(bytes.NewReader(jsonBody))
+	mockReq.ContentLength = int64(len(jsonBody))
+	mockReq.Header.Set("Content-Type", "application/json")
+	mockReq.Header.Del("Content-Length")
+	mockReq.Header.Del("Accept-Encoding")
+
+	resp, err, projectID, mode, matched := s.HandleRequest(ctx, alias, http.MethodPost, path, mockReq)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusInternal, err.Error()), nil, projectID, mode, matched
+	}
+	if isGRPCResponse(resp) {
+		return resp, nil, projectID, mode, matched
+	}
+	return createGRPCResponse(resp, method.Output()), nil, projectID, mode, matched
+}
+
+// loadGRPCDescriptorSet parses a base64 FileDescriptorSet, caching the result
+func loadGRPCDescriptorSet(descriptorSet string) (*protoregistry.Files, error) {
+	if cached, ok := grpcDescriptorCache.Load(descriptorSet); ok {
+		return cached.(*protoregistry.Files), nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(descriptorSet)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcDescriptorCache.Store(descriptorSet, files)
+	return files, nil
+}
+
+// findGRPCMethod resolves /<package.Service>/<Method> in the descriptor set
+func findGRPCMethod(files *protoregistry.Files, path string) (protoreflect.MethodDescriptor, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("malformed method name %q", path)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %s", parts[0])
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", parts[0])
+	}
+	method := service.Methods().ByName(protoreflect.Name(parts[1]))
+	if method == nil {
+		return nil, fmt.Errorf("unknown method %s for service %s", parts[1], parts[0])
+	}
+	return method, nil
+}
+
+// readGRPCMessage reads the single length-prefixed message of a unary call, at most
+// MAX_REQUEST_BODY_BYTES of it (errRequestBodyTooLarge above)
+func readGRPCMessage(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, errors.New("missing request message")
+	}
+	data, err := readRequestBody(req, maxRequestBodyBytes())
+	if err != nil {
+		return nil, err
+	}
+	return parseGRPCFrame(data)
+}
+
+// parseGRPCFrame returns the message of a single uncompressed gRPC frame
+func parseGRPCFrame(data []byte) ([]byte, error) {
+	if len(data) < grpcFrameHeaderLen {
+		return nil, errors.New("missing request message")
+	}
+	if data[0] != 0 {
+		return nil, errGRPCCompressed
+	}
+	length := binary.BigEndian.Uint32(data[1:grpcFrameHeaderLen])
+	if uint64(len(data)-grpcFrameHeaderLen) != uint64(length) {
+		return nil, errors.New("request message length does not match its frame")
+	}
+	return data[grpcFrameHeaderLen:], nil
+}
+
+// frameGRPCMessage prefixes an uncompressed message with its gRPC frame header
+func frameGRPCMessage(message []byte) []byte {
+	frame := make([]byte, grpcFrameHeaderLen+len(message))
+	binary.BigEndian.PutUint32(frame[1:grpcFrameHeaderLen], uint32(len(message)))
+	copy(frame[grpcFrameHeaderLen:], message)
+	return frame
+}
+
+// createGRPCResponse encodes a mock response as a gRPC reply of the output type. Headers of the
+// mock response are sent as metadata and the status goes in the grpc-status trailer.
+func createGRPCResponse(resp *http.Response, output protoreflect.MessageDescriptor) *http.Response {
+	body := ""
+	if resp.Body != nil {
+		defer resp.Body.Close()
+		var ok bool
+		if body, ok = readRecordedBody(resp); !ok {
+			return createGRPCStatusResponse(grpcStatusInternal, "mock response body cannot be decoded")
+		}
+	}
+
+	status := grpcStatusFor(resp)
+	if status != grpcStatusOK {
+		grpcResp := createGRPCStatusResponse(status, grpcErrorMessage(resp, body))
+		copyGRPCMetadata(grpcResp.Header, resp.Header)
+		return grpcResp
+	}
+
+	message := dynamicpb.NewMessage(output)
+	if strings.TrimSpace(body) != "" {
+		if err := grpcJSONUnmarshal.Unmarshal([]byte(body), message); err != nil {
+			return createGRPCStatusResponse(grpcStatusInternal, fmt.Sprintf("mock response is not a valid %s: %v", output.FullName(), err))
+		}
+	}
+	wire, err := proto.Marshal(message)
+	if err != nil {
+		return createGRPCStatusResponse(grpcStatusInternal, err.Error())
+	}
+
+	frame := frameGRPCMessage(wire)
+	grpcResp := createGRPCStatusResponse(grpcStatusOK, "")
+	// Request logs show the JSON the response was written in rather than the protobuf bytes
+	grpcResp.Body = &decodedProxyBody{Reader: bytes.NewReader(frame), decoded: []byte(body)}
+	grpcResp.ContentLength = int64(len(frame))
+	copyGRPCMetadata(grpcResp.Header, resp.Header)
+	return grpcResp
+}
+
+// createGRPCStatusResponse builds a gRPC reply without a message. gRPC always answers HTTP 200;
+// the outcome is in the grpc-status and grpc-message trailers.
+func createGRPCStatusResponse(status int, message string) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       http.NoBody,
+		Header:     make(http.Header),
+		Trailer:    make(http.Header),
+	}
+	resp.Header.Set("Content-Type", grpcContentType)
+	resp.Trailer.Set("Grpc-Status", strconv.Itoa(status))
+	if message != "" {
+		resp.Trailer.Set("Grpc-Message", encodeGRPCMessage(message))
+	}
+	return resp
+}
+
+// grpcStatusFor returns the gRPC status of a mock response: its grpc-status header when set,
+// otherwise OK for 2xx and the mapped code for other statuses
+func grpcStatusFor(resp *http.Response) int {
+	if value := resp.Header.Get("Grpc-Status"); value != "" {
+		if status, err := strconv.Atoi(value); err == nil && status >= 0 && status <= 16 {
+			return status
+		}
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return grpcStatusOK
+	}
+	if status, ok := grpcStatusByHTTPStatus[resp.StatusCode]; ok {
+		return status
+	}
+	return grpcStatusUnknown
+}
+
+// grpcErrorMessage picks the error text: the grpc-message header, the "message" field of a JSON
+// body, or the body itself
+func grpcErrorMessage(resp *http.Response, body string) string {
+	if message := resp.Header.Get("Grpc-Message"); message != "" {
+		return message
+	}
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(body)
+}
+
+// copyGRPCMetadata sends the mock response's headers as gRPC response metadata
+func copyGRPCMetadata(dst, src http.Header) {
+	for key, values := range src {
+		if grpcSkippedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		dst[key] = values
+	}
+}
+
+// encodeGRPCMessage percent-encodes a grpc-message value: bytes outside printable ASCII and '%'
+func encodeGRPCMessage(message string) string {
+	var out strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < 0x20 || c > 0x7E || c == '%' {
+			fmt.Fprintf(&out, "%%%02X", c)
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
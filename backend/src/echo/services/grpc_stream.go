@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"beo-echo/backend/src/database"
+)
+
+// grpcMethodKey stores the gRPC method being mocked in the request context, so responses with
+// a grpcStream config know the output type to encode
+type grpcMethodKey struct{}
+
+// withGRPCMethod returns a context carrying the method resolved by HandleGRPCRequest
+func withGRPCMethod(ctx context.Context, method protoreflect.MethodDescriptor) context.Context {
+	return context.WithValue(ctx, grpcMethodKey{}, method)
+}
+
+// grpcStreamOutput returns the output type of a server-streaming method stored by withGRPCMethod
+func grpcStreamOutput(ctx context.Context) (protoreflect.MessageDescriptor, bool) {
+	method, ok := ctx.Value(grpcMethodKey{}).(protoreflect.MethodDescriptor)
+	if !ok || !method.IsStreamingServer() {
+		return nil, false
+	}
+	return method.Output(), true
+}
+
+// grpcStreamBody produces the framed messages of a gRPC stream one read at a time, waiting each
+// message's delay first, so the mock handler flushes every message as it is due
+type grpcStreamBody struct {
+	ctx      context.Context
+	messages []database.GRPCStreamMessage
+	frames   [][]byte
+	next     int
+	pending  []byte
+}
+
+func (b *grpcStreamBody) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		if err := b.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// fill queues the next frame once its delay passed. A cancelled context (client gone) ends the
+// stream with the context error.
+func (b *grpcStreamBody) fill() error {
+	if b.next >= len(b.frames) {
+		return io.EOF
+	}
+
+	delayMs := b.messages[b.next].DelayMs
+	if delayMs > 0 {
+		timer := time.NewTimer(time.Duration(delayMs) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		case <-timer.C:
+		}
+	} else if err := b.ctx.Err(); err != nil {
+		return err
+	}
+
+	b.pending = b.frames[b.next]
+	b.next++
+	return nil
+}
+
+func (b *grpcStreamBody) Close() error { return nil }
+
+// Streaming marks grpcStreamBody as a StreamingBody so each message is flushed on its own
+func (b *grpcStreamBody) Streaming() {}
+
+// createGRPCStreamResponse encodes the stream's messages with the output type up front, so an
+// invalid message fails the call before anything is sent. Headers of the mock response are sent
+// as metadata and the configured closing status goes in the trailers.
+func createGRPCStreamResponse(ctx context.Context, response database.MockResponse, stream *database.GRPCStream, output protoreflect.MessageDescriptor) (*http.Response, error) {
+	frames := make([][]byte, len(stream.Messages))
+	for i, item := range stream.Messages {
+		message := dynamicpb.NewMessage(output)
+		if err := grpcJSONUnmarshal.Unmarshal(item.Body, message); err != nil {
+			return createGRPCStatusResponse(grpcStatusInternal, fmt.Sprintf("messages[%d] is not a valid %s: %v", i, output.FullName(), err)), nil
+		}
+		wire, err := proto.Marshal(message)
+		if err != nil {
+			return createGRPCStatusResponse(grpcStatusInternal, err.Error()), nil
+		}
+		frames[i] = frameGRPCMessage(wire)
+	}
+
+	response.Body = ""
+	response.RedirectURL = ""
+	resp, err := createMockResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	grpcResp := createGRPCStatusResponse(stream.Status, stream.Message)
+	copyGRPCMetadata(grpcResp.Header, resp.Header)
+	grpcResp.ContentLength = -1
+	grpcResp.Body = &grpcStreamBody{ctx: ctx, messages: stream.Messages, frames: frames}
+	return grpcResp, nil
+}
+
+// isGRPCResponse reports whether a mock response is already a gRPC reply (a grpcStream or an
+// invalid stream config), which HandleGRPCRequest sends as is
+func isGRPCResponse(resp *http.Response) bool {
+	return resp.Trailer.Get("Grpc-Status") != ""
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+// splitGRPCFrames returns the messages of a body holding several gRPC frames
+func splitGRPCFrames(t *testing.T, body []byte) [][]byte {
+	var messages [][]byte
+	for len(body) > 0 {
+		require.GreaterOrEqual(t, len(body), grpcFrameHeaderLen)
+		length := int(binary.BigEndian.Uint32(body[1:grpcFrameHeaderLen]))
+		require.GreaterOrEqual(t, len(body), grpcFrameHeaderLen+length)
+		messages = append(messages, body[grpcFrameHeaderLen:grpcFrameHeaderLen+length])
+		body = body[grpcFrameHeaderLen+length:]
+	}
+	return messages
+}
+
+func TestHandleGRPCRequest_ServerStreaming(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "grpc-stream")
+	descriptorSet := greeterDescriptorSet(t)
+	project.AdvanceConfig = `{"grpc": {"descriptorSet": "` + descriptorSet + `"}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/helloworld.Greeter/Greetings")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode: 200,
+		Headers:    `{"X-Mock": "greetings"}`,
+		AdvanceConfig: `{"grpcStream": {"status": 14, "message": "server restarting", "messages": [
+			{"body": {"message": "Hello", "count": 1}},
+			{"body": {"message": "Hello again", "count": 2}, "delayMs": 30}
+		]}}`,
+	})
+	plain := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"message": "only once"}`, Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: plain.ID, Type: "body", Key: "name", Operator: "equals", Value: "Once"}).Error)
+	invalid := createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		AdvanceConfig: `{"grpcStream": {"messages": [{"body": {"count": "many"}}]}}`,
+		Priority:      10,
+	})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: invalid.ID, Type: "body", Key: "name", Operator: "equals", Value: "Invalid"}).Error)
+
+	call := func(ctx context.Context, name string) *http.Response {
+		req := newGRPCTestRequest(t, descriptorSet, "/helloworld.Greeter/Greetings", name)
+		resp, err, _, _, matched := service.HandleGRPCRequest(ctx, project.Alias, "/helloworld.Greeter/Greetings", req)
+		require.NoError(t, err)
+		assert.True(t, matched)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/grpc", resp.Header.Get("Content-Type"))
+		return resp
+	}
+
+	t.Run("Messages are streamed, then the stream closes with the configured status", func(t *testing.T) {
+		resp := call(context.Background(), "Ada")
+		_, streaming := resp.Body.(StreamingBody)
+		assert.True(t, streaming, "messages are flushed as they are produced")
+		assert.Equal(t, "greetings", resp.Header.Get("X-Mock"), "mock headers are sent as metadata")
+
+		start := time.Now()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+		messages := splitGRPCFrames(t, body)
+		require.Len(t, messages, 2)
+		assert.Equal(t, map[string]interface{}{"message": "Hello", "count": int64(1)}, unmarshalHelloReply(t, descriptorSet, messages[0]))
+		assert.Equal(t, map[string]interface{}{"message": "Hello again", "count": int64(2)}, unmarshalHelloReply(t, descriptorSet, messages[1]))
+		assert.Equal(t, "14", resp.Trailer.Get("Grpc-Status"))
+		assert.Equal(t, "server restarting", resp.Trailer.Get("Grpc-Message"))
+	})
+
+	t.Run("Without a grpcStream config the body is a single message", func(t *testing.T) {
+		resp := call(context.Background(), "Once")
+
+		assert.Equal(t, "only once", decodeHelloReply(t, descriptorSet, resp)["message"])
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+	})
+
+	t.Run("Messages that don't fit the output type fail the call", func(t *testing.T) {
+		resp := call(context.Background(), "Invalid")
+
+		assert.Equal(t, "13", resp.Trailer.Get("Grpc-Status"))
+		assert.Contains(t, resp.Trailer.Get("Grpc-Message"), "messages[0] is not a valid helloworld.HelloReply")
+	})
+
+	t.Run("A cancelled call ends the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := call(ctx, "Ada")
+
+		buf := make([]byte, 64)
+		n, err := resp.Body.Read(buf)
+		require.NoError(t, err)
+		assert.Len(t, splitGRPCFrames(t, buf[:n]), 1)
+
+		cancel()
+		_, err = resp.Body.Read(buf)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// greeterDescriptorSet is the base64 descriptor set of
+//
+//	package helloworld;
+//	message HelloRequest { string name = 1; }
+//	message HelloReply { string message = 1; int32 count = 2; }
+//	service Greeter {
+//	  rpc SayHello(HelloRequest) returns (HelloReply);
+//	  rpc Greetings(HelloRequest) returns (stream HelloReply);
+//	  rpc Chat(stream HelloRequest) returns (stream HelloReply);
+//	}
+func greeterDescriptorSet(t *testing.T) string {
+	stringField := func(name string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			JsonName: proto.String(name),
+		}
+	}
+	countField := stringField("count", 2)
+	countField.Type = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("helloworld.proto"),
+		Package: proto.String("helloworld"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("HelloRequest"), Field: []*descriptorpb.FieldDescriptorProto{stringField("name", 1)}},
+			{Name: proto.String("HelloReply"), Field: []*descriptorpb.FieldDescriptorProto{stringField("message", 1), countField}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Greeter"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("SayHello"), InputType: proto.String(".helloworld.HelloRequest"), OutputType: proto.String(".helloworld.HelloReply")},
+				{Name: proto.String("Greetings"), InputType: proto.String(".helloworld.HelloRequest"), OutputType: proto.String(".helloworld.HelloReply"), ServerStreaming: proto.Bool(true)},
+				{Name: proto.String("Chat"), InputType: proto.String(".helloworld.HelloRequest"), OutputType: proto.String(".helloworld.HelloReply"), ClientStreaming: proto.Bool(true), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+
+	raw, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// newGRPCTestRequest frames a HelloRequest the way a gRPC client sends it over HTTP/2
+func newGRPCTestRequest(t *testing.T, descriptorSet, path, name string) *http.Request {
+	files, err := loadGRPCDescriptorSet(descriptorSet)
+	require.NoError(t, err)
+	descriptor, err := files.FindDescriptorByName("helloworld.HelloRequest")
+	require.NoError(t, err)
+
+	message := dynamicpb.NewMessage(descriptor.(protoreflect.MessageDescriptor))
+	message.Set(message.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString(name))
+	wire, err := proto.Marshal(message)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(frameGRPCMessage(wire)))
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header.Set("Content-Type", "application/grpc")
+	return req
+}
+
+// decodeHelloReply reads the framed HelloReply of a gRPC response
+func decodeHelloReply(t *testing.T, descriptorSet string, resp *http.Response) map[string]interface{} {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	payload, err := parseGRPCFrame(body)
+	require.NoError(t, err)
+	return unmarshalHelloReply(t, descriptorSet, payload)
+}
+
+// unmarshalHelloReply decodes a HelloReply message into its fields
+func unmarshalHelloReply(t *testing.T, descriptorSet string, payload []byte) map[string]interface{} {
+	files, err := loadGRPCDescriptorSet(descriptorSet)
+	require.NoError(t, err)
+	descriptor, err := files.FindDescriptorByName("helloworld.HelloReply")
+	require.NoError(t, err)
+	message := dynamicpb.NewMessage(descriptor.(protoreflect.MessageDescriptor))
+	require.NoError(t, proto.Unmarshal(payload, message))
+
+	fields := message.Descriptor().Fields()
+	return map[string]interface{}{
+		"message": message.Get(fields.ByName("message")).String(),
+		"count":   message.Get(fields.ByName("count")).Int(),
+	}
+}
+
+func TestHandleGRPCRequest(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "grpc-mock")
+	descriptorSet := greeterDescriptorSet(t)
+	project.AdvanceConfig = `{"grpc": {"descriptorSet": "` + descriptorSet + `"}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/helloworld.Greeter/SayHello")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"message": "Hello {{.Body.name}}", "count": 2, "note": "ignored"}`,
+		Headers:       `{"X-Mock": "greeter"}`,
+		AdvanceConfig: `{"templated": true}`,
+	})
+	blocked := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 403, Body: `{"message": "blocked user"}`, Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: blocked.ID, Type: "body", Key: "name", Operator: "equals", Value: "Mallory"}).Error)
+
+	call := func(path, name string) *http.Response {
+		req := newGRPCTestRequest(t, descriptorSet, path, name)
+		require.True(t, IsGRPCRequest(req))
+		resp, err, _, _, _ := service.HandleGRPCRequest(context.Background(), project.Alias, path, req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "gRPC always answers HTTP 200")
+		assert.Equal(t, "application/grpc", resp.Header.Get("Content-Type"))
+		return resp
+	}
+
+	t.Run("JSON response is encoded as the output message", func(t *testing.T) {
+		resp := call("/helloworld.Greeter/SayHello", "Ada")
+
+		assert.Equal(t, map[string]interface{}{"message": "Hello Ada", "count": int64(2)}, decodeHelloReply(t, descriptorSet, resp))
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+		assert.Equal(t, "greeter", resp.Header.Get("X-Mock"), "mock headers are sent as metadata")
+	})
+
+	t.Run("Rules see the request message as JSON", func(t *testing.T) {
+		resp := call("/helloworld.Greeter/SayHello", "Mallory")
+
+		assert.Equal(t, "7", resp.Trailer.Get("Grpc-Status"), "403 maps to PERMISSION_DENIED")
+		assert.Equal(t, "blocked user", resp.Trailer.Get("Grpc-Message"))
+	})
+
+	t.Run("Unknown methods are unimplemented", func(t *testing.T) {
+		resp := call("/helloworld.Greeter/SayGoodbye", "Ada")
+
+		assert.Equal(t, "12", resp.Trailer.Get("Grpc-Status"))
+		assert.Contains(t, resp.Trailer.Get("Grpc-Message"), "unknown method SayGoodbye")
+	})
+
+	t.Run("Client-streaming methods are unimplemented", func(t *testing.T) {
+		resp := call("/helloworld.Greeter/Chat", "Ada")
+
+		assert.Equal(t, "12", resp.Trailer.Get("Grpc-Status"))
+		assert.Equal(t, "only unary and server-streaming calls are supported", resp.Trailer.Get("Grpc-Message"))
+	})
+}
+
+func TestHandleGRPCRequest_UseProxy(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "grpc-proxy")
+	descriptorSet := greeterDescriptorSet(t)
+	project.AdvanceConfig = `{"grpc": {"descriptorSet": "` + descriptorSet + `"}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	var forwarded http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message": "from upstream"}`))
+	}))
+	defer upstream.Close()
+	target := &database.ProxyTarget{ProjectID: project.ID, URL: upstream.URL}
+	require.NoError(t, database.DB.Create(target).Error)
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/helloworld.Greeter/SayHello")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Updates(map[string]interface{}{"use_proxy": true, "proxy_target_id": target.ID}).Error)
+
+	req := newGRPCTestRequest(t, descriptorSet, "/helloworld.Greeter/SayHello", "Ada")
+	req.Header.Set("beo-echo-project", project.Alias)
+	resp, err, _, _, _ := service.HandleGRPCRequest(context.Background(), project.Alias, "/helloworld.Greeter/SayHello", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message"))
+	assert.Equal(t, "from upstream", decodeHelloReply(t, descriptorSet, resp)["message"])
+	assert.Empty(t, forwarded.Get("beo-echo-project"), "the project header isn't forwarded")
+}
+
+func TestHandleGRPCRequest_MaxRequestBodyBytes(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "grpc-limit")
+	descriptorSet := greeterDescriptorSet(t)
+	project.AdvanceConfig = `{"grpc": {"descriptorSet": "` + descriptorSet + `"}}`
+	require.NoError(t, database.DB.Save(project).Error)
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.MAX_REQUEST_BODY_BYTES, "16"))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.MAX_REQUEST_BODY_BYTES, "10485760") })
+
+	req := newGRPCTestRequest(t, descriptorSet, "/helloworld.Greeter/SayHello", "a name far too long for the limit")
+	resp, err, _, _, _ := service.HandleGRPCRequest(context.Background(), project.Alias, "/helloworld.Greeter/SayHello", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "8", resp.Trailer.Get("Grpc-Status"), "RESOURCE_EXHAUSTED")
+	assert.Contains(t, resp.Trailer.Get("Grpc-Message"), "exceeds 16 bytes")
+}
+
+func TestHandleGRPCRequest_NotConfigured(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "grpc-off")
+
+	req := newGRPCTestRequest(t, greeterDescriptorSet(t), "/helloworld.Greeter/SayHello", "Ada")
+	resp, err, _, _, matched := service.HandleGRPCRequest(context.Background(), project.Alias, "/helloworld.Greeter/SayHello", req)
+
+	require.NoError(t, err)
+	assert.False(t, matched)
+	assert.Equal(t, "12", resp.Trailer.Get("Grpc-Status"))
+	assert.Equal(t, "gRPC is not configured for this project", resp.Trailer.Get("Grpc-Message"))
+}
+
+func TestGRPCStatusFor(t *testing.T) {
+	newResp := func(status int, grpcStatus string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+		if grpcStatus != "" {
+			resp.Header.Set("Grpc-Status", grpcStatus)
+		}
+		return resp
+	}
+
+	assert.Equal(t, grpcStatusOK, grpcStatusFor(newResp(201, "")))
+	assert.Equal(t, grpcStatusNotFound, grpcStatusFor(newResp(404, "")))
+	assert.Equal(t, grpcStatusUnknown, grpcStatusFor(newResp(418, "")))
+	assert.Equal(t, grpcStatusAborted, grpcStatusFor(newResp(200, "10")), "an explicit grpc-status header wins")
+}
+
+func TestEncodeGRPCMessage(t *testing.T) {
+	assert.Equal(t, "100%25 sure", encodeGRPCMessage("100% sure"))
+	assert.Equal(t, "caf%C3%A9", encodeGRPCMessage("café"))
+}
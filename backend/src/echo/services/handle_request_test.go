@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/repositories"
+)
+
+// setupHandleRequestTest creates a workspace with a mock-mode project and returns a service backed by the test database
+func setupHandleRequestTest(t *testing.T, alias string) (*MockService, *database.Project) {
+	database.SetupTestEnvironment(t)
+
+	setup, err := database.InitTestWorkspaceWithProject(alias+"@example.com", "Handle Request User", "Handle Request Workspace", "Handle Request Project", alias)
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	return NewMockService(repositories.NewMockRepository(database.DB)), setup.Project
+}
+
+// createTestResponse stores a response for the endpoint and returns it
+func createTestResponse(t *testing.T, endpointID string, response database.MockResponse) database.MockResponse {
+	response.EndpointID = endpointID
+	response.Enabled = true
+	require.NoError(t, database.DB.Create(&response).Error)
+	return response
+}
+
+func TestHandleRequest_ProcessingTimeHeader(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "processing-time")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/slow")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "ok", DelayMS: 30})
+
+	req := httptest.NewRequest(http.MethodGet, "/processing-time/slow", nil)
+	resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/slow", req)
+
+	require.NoError(t, err)
+	assert.True(t, matched)
+	processingMs, convErr := strconv.Atoi(resp.Header.Get("beo-echo-processing-ms"))
+	require.NoError(t, convErr)
+	assert.GreaterOrEqual(t, processingMs, 30, "processing time includes the applied delay")
+	assert.Empty(t, resp.Header.Get("beo-echo-latency-ms"), "upstream latency header is only set when proxying")
+}
+
+func TestHandleRequest_MaxInFlight(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "max-in-flight")
+	project.AdvanceConfig = `{"maxInFlight": 1, "limitExceededResponse": {"body": "{\"busy\":true}"}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/items")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+	// Occupy the only in-flight slot
+	release, ok := acquireInFlight(project.ID, 1)
+	require.True(t, ok)
+
+	req := httptest.NewRequest(http.MethodGet, "/max-in-flight/items", nil)
+	resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/items", req)
+	require.NoError(t, err)
+	assert.False(t, matched)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"busy":true}`, string(body))
+
+	release()
+	resp, err, _, _, matched = service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/items", req)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(0), ProjectInFlight(project.ID), "counter is released after each request")
+}
+
+func TestHandleRequest_CancelledContext(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "cancelled")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/items")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/cancelled/items", nil)
+	resp, err, _, _, matched := service.HandleRequest(ctx, project.Alias, http.MethodGet, "/items", req)
+	require.NoError(t, err)
+	assert.False(t, matched)
+	assert.Equal(t, statusClientClosedRequest, resp.StatusCode)
+
+	t.Run("Project config overrides the built-in response", func(t *testing.T) {
+		project := &database.Project{AdvanceConfig: `{"cancelledResponse": {"statusCode": 408, "body": "gone"}}`}
+
+		resp := createCancelledResponse(ctx, project)
+		require.NotNil(t, resp)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+		assert.Equal(t, "gone", string(body))
+
+		assert.Nil(t, createCancelledResponse(context.Background(), project), "live requests are not short-circuited")
+	})
+}
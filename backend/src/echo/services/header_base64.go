@@ -0,0 +1,35 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// matchHeaderBase64Rule base64-decodes a header before matching. The rule key is the header name,
+// optionally followed by ":" and a dot-notation path into the decoded JSON, e.g. "X-Context" matches
+// the whole decoded value and "X-Context:tenant.id" a field of it. A missing header, invalid base64
+// or (with a path) a value that isn't a JSON object never matches.
+func matchHeaderBase64Rule(rule database.MockRule, req *http.Request) bool {
+	headerName, field, hasField := strings.Cut(rule.Key, ":")
+	headerValue := req.Header.Get(strings.TrimSpace(headerName))
+	if headerValue == "" {
+		return false
+	}
+	decoded, ok := decodeBase64(headerValue)
+	if !ok {
+		return false
+	}
+
+	actual := string(decoded)
+	if hasField {
+		var data map[string]interface{}
+		if err := json.Unmarshal(decoded, &data); err != nil {
+			return false
+		}
+		actual = getNestedValue(data, strings.TrimSpace(field))
+	}
+	return matchRuleValue(rule.Operator, actual, rule.Value)
+}
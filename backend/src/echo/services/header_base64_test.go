@@ -0,0 +1,29 @@
+package services
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchHeaderBase64Rule(t *testing.T) {
+	newReq := func(value string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set("X-Context", value)
+		return req
+	}
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(`{"tenant":{"id":"acme"},"plan":"pro"}`))
+
+	assert.True(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Context:tenant.id", Operator: "equals", Value: "acme"}, newReq(encoded)))
+	assert.False(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Context:plan", Operator: "equals", Value: "free"}, newReq(encoded)))
+	assert.True(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Context", Operator: "contains", Value: `"plan":"pro"`}, newReq(encoded)), "without a path the whole decoded value is matched")
+
+	assert.False(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Context", Operator: "contains", Value: "a"}, newReq("%%%not-base64")), "invalid base64 never matches")
+	assert.False(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Context:id", Operator: "equals", Value: ""}, newReq(base64.StdEncoding.EncodeToString([]byte("plain")))), "non-JSON values don't match paths")
+	assert.False(t, matchHeaderBase64Rule(database.MockRule{Key: "X-Missing", Operator: "equals", Value: ""}, newReq(encoded)))
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// healthStatus is the body of a health check response
+type healthStatus struct {
+	Status  string               `json:"status"`
+	Project string               `json:"project"`
+	Mode    database.ProjectMode `json:"mode"`
+}
+
+// healthCheckPath returns the configured health check path with a leading slash, or "" when disabled
+func healthCheckPath() string {
+	if database.DB == nil {
+		return ""
+	}
+	path, err := systemConfig.GetSystemConfigWithType[string](systemConfig.HEALTH_CHECK_PATH)
+	if err != nil {
+		return ""
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ""
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(path, "/")
+}
+
+// isHealthCheckRequest reports whether a GET or HEAD request targets the health check path
+func isHealthCheckRequest(method, path string) bool {
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+	healthPath := healthCheckPath()
+	return healthPath != "" && strings.TrimSuffix(path, "/") == healthPath
+}
+
+// createHealthResponse answers a health probe with 200 whatever the project mode, so load
+// balancers can tell the mock server is up even while a project is disabled
+func createHealthResponse(project *database.Project, method string) *http.Response {
+	jsonBody, _ := json.Marshal(healthStatus{Status: "ok", Project: project.Alias, Mode: project.Mode})
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Body:          newGeneratedBody(jsonBody),
+		Header:        make(http.Header),
+		ContentLength: int64(len(jsonBody)),
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Header.Set("Cache-Control", "no-store")
+	if method == http.MethodHead {
+		stripResponseBody(resp)
+	}
+	return resp
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestHandleRequest_HealthCheck(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "health-check")
+	project.Mode = database.ModeDisabled
+	require.NoError(t, database.DB.Save(project).Error)
+
+	t.Run("Disabled project answers the health path with 200", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health-check/__beo-echo/health", nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/health-check/__beo-echo/health", req)
+
+		require.NoError(t, err)
+		assert.False(t, matched)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"status":"ok","project":"health-check","mode":"disabled"}`, string(body))
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("Other paths still follow the project mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health-check/users", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/users", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("Configured path", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.HEALTH_CHECK_PATH, "healthz"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.HEALTH_CHECK_PATH, "/__beo-echo/health") })
+
+		req := httptest.NewRequest(http.MethodHead, "/health-check/healthz", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodHead, "/healthz", req)
+
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, body)
+		assert.NotEmpty(t, resp.Header.Get("Content-Length"))
+	})
+
+	t.Run("Empty path disables the health check", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.HEALTH_CHECK_PATH, ""))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.HEALTH_CHECK_PATH, "/__beo-echo/health") })
+
+		req := httptest.NewRequest(http.MethodGet, "/health-check/__beo-echo/health", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/__beo-echo/health", req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
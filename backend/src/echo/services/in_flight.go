@@ -0,0 +1,33 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Global in-flight request counters per project (projectID -> *int64)
+var projectInFlight sync.Map
+
+// acquireInFlight counts a request as in flight for the project.
+// When max is positive and would be exceeded the request is not counted and ok is false.
+// The returned release must be called once the request is done.
+func acquireInFlight(projectID string, max int) (release func(), ok bool) {
+	value, _ := projectInFlight.LoadOrStore(projectID, new(int64))
+	counter := value.(*int64)
+
+	if current := atomic.AddInt64(counter, 1); max > 0 && current > int64(max) {
+		atomic.AddInt64(counter, -1)
+		return func() {}, false
+	}
+
+	return func() { atomic.AddInt64(counter, -1) }, true
+}
+
+// ProjectInFlight returns the number of requests currently being handled for the project
+func ProjectInFlight(projectID string) int64 {
+	value, ok := projectInFlight.Load(projectID)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt64(value.(*int64))
+}
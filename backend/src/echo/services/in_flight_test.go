@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireInFlight(t *testing.T) {
+	projectID := "in-flight-project"
+
+	releaseFirst, ok := acquireInFlight(projectID, 2)
+	assert.True(t, ok)
+	releaseSecond, ok := acquireInFlight(projectID, 2)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), ProjectInFlight(projectID))
+
+	// Third request exceeds the limit and is not counted
+	_, ok = acquireInFlight(projectID, 2)
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), ProjectInFlight(projectID))
+
+	releaseFirst()
+	releaseSecond()
+	assert.Equal(t, int64(0), ProjectInFlight(projectID))
+
+	// Zero max means unlimited
+	release, ok := acquireInFlight(projectID, 0)
+	assert.True(t, ok)
+	release()
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSON body formats for the jsonFormat response option
+const (
+	JSONFormatPretty = "pretty" // Indent with two spaces
+	JSONFormatMinify = "minify" // Strip insignificant whitespace
+)
+
+// formatJSONBody re-formats a body according to the jsonFormat option, keeping key order.
+// Bodies that aren't valid JSON, or an empty format, leave the body unchanged.
+func formatJSONBody(body, format string) string {
+	if body == "" || format == "" || !json.Valid([]byte(body)) {
+		return body
+	}
+
+	var formatted bytes.Buffer
+	var err error
+	switch format {
+	case JSONFormatPretty:
+		err = json.Indent(&formatted, []byte(body), "", "  ")
+	case JSONFormatMinify:
+		err = json.Compact(&formatted, []byte(body))
+	default:
+		return body
+	}
+	if err != nil {
+		return body
+	}
+	return formatted.String()
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_JSONFormat(t *testing.T) {
+	body := func(mockResp database.MockResponse) string {
+		resp, err := createMockResponse(mockResp)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	assert.Equal(t, "{\n  \"b\": 1,\n  \"a\": [\n    true\n  ]\n}", body(database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"b":1,"a":[true]}`,
+		AdvanceConfig: `{"jsonFormat": "pretty"}`,
+	}))
+	assert.Equal(t, `{"b":1,"a":[true]}`, body(database.MockResponse{
+		StatusCode:    200,
+		Body:          "{\n  \"b\": 1,\n  \"a\": [ true ]\n}",
+		AdvanceConfig: `{"jsonFormat": "minify"}`,
+	}))
+	assert.Equal(t, "not { json", body(database.MockResponse{
+		StatusCode:    200,
+		Body:          "not { json",
+		AdvanceConfig: `{"jsonFormat": "pretty"}`,
+	}), "non-JSON bodies pass through")
+
+	_, err := database.ParseResponseAdvanceConfig(`{"jsonFormat": "tabs"}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one segment of a parsed JSONPath expression
+type jsonPathStep struct {
+	name     string // Object key; empty for index and wildcard steps
+	index    int    // Array index, negative counts from the end
+	isIndex  bool
+	wildcard bool // [*] or .* - every array element or object value
+}
+
+// parseJSONPath parses the JSONPath subset used by body rules: an optional leading "$",
+// dot-separated keys, [n] array indices (negative from the end), [*] and .* wildcards, and
+// ['key'] for keys containing dots. Plain "user.address.city" keys parse as before.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	var steps []jsonPathStep
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in %q", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{name: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid array index %q in %q", inner, path)
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			name := path[i : i+end]
+			i += end
+
+			if name == "*" {
+				steps = append(steps, jsonPathStep{wildcard: true})
+			} else {
+				steps = append(steps, jsonPathStep{name: name})
+			}
+		}
+	}
+	return steps, nil
+}
+
+// jsonPathValues returns the values the expression selects from decoded JSON. Missing keys
+// and out-of-range indices select nothing, so the result may be empty.
+func jsonPathValues(data interface{}, path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					for _, child := range v {
+						next = append(next, child)
+					}
+				} else if child, ok := v[step.name]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if step.wildcard {
+					next = append(next, v...)
+				} else if step.isIndex {
+					index := step.index
+					if index < 0 {
+						index += len(v)
+					}
+					if index >= 0 && index < len(v) {
+						next = append(next, v[index])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// stringifyJSONValue renders a decoded JSON value the way rules compare it: strings as-is,
+// numbers and booleans in Go's %v form, objects and arrays as JSON. Null renders empty.
+func stringifyJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, float64:
+		return fmt.Sprintf("%v", v)
+	default:
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(bytes)
+	}
+}
+
+// matchSelectedValues applies the operator to every value a path selected. Positive operators
+// match when any value matches; negated ones (not_equals, not_contains) only when all of them
+// do, so "users[*].role not_equals admin" means no user is an admin.
+func matchSelectedValues(operator string, values []string, expected string) bool {
+	negated := strings.HasPrefix(strings.ToLower(operator), "not_")
+	for _, value := range values {
+		matched := matchRuleValue(operator, value, expected)
+		if negated && !matched {
+			return false
+		}
+		if !negated && matched {
+			return true
+		}
+	}
+	return negated
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestJSONPathValues(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"items": [{"sku": "A-1", "tags": ["new", "sale"]}, {"sku": "B-2", "tags": []}],
+		"users": [{"role": "admin"}, {"role": "viewer"}],
+		"matrix": [[1, 2], [3, 4]],
+		"config": {"feature.flag": true}
+	}`), &data))
+
+	cases := []struct {
+		path string
+		want []string
+	}{
+		{"items[0].sku", []string{"A-1"}},
+		{"$.items[1].sku", []string{"B-2"}},
+		{"items[-1].sku", []string{"B-2"}},
+		{"items[0].tags[1]", []string{"sale"}},
+		{"matrix[1][0]", []string{"3"}},
+		{"users[*].role", []string{"admin", "viewer"}},
+		{"items.*.sku", []string{"A-1", "B-2"}},
+		{"config['feature.flag']", []string{"true"}},
+		{"items[5].sku", nil},
+		{"items[0].missing", nil},
+		{"missing.key", nil},
+		{"users.role", nil},
+	}
+
+	for _, tc := range cases {
+		values, err := jsonPathValues(data, tc.path)
+		require.NoError(t, err, tc.path)
+		var got []string
+		for _, value := range values {
+			got = append(got, stringifyJSONValue(value))
+		}
+		assert.Equal(t, tc.want, got, tc.path)
+	}
+
+	_, err := jsonPathValues(data, "items[abc]")
+	assert.Error(t, err)
+	_, err = jsonPathValues(data, "items[0")
+	assert.Error(t, err)
+}
+
+func TestMatchBodyRule_JSONPath(t *testing.T) {
+	body := `{"items":[{"sku":"A-1"},{"sku":"B-2"}],"users":[{"role":"admin"},{"role":"viewer"}]}`
+	match := func(key, operator, value string) bool {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		return matchBodyRule(database.MockRule{Type: "body", Key: key, Operator: operator, Value: value}, req)
+	}
+
+	assert.True(t, match("items[0].sku", "equals", "A-1"))
+	assert.False(t, match("items[0].sku", "equals", "B-2"))
+	assert.True(t, match("users[*].role", "equals", "viewer"), "wildcards match when any value matches")
+	assert.False(t, match("users[*].role", "not_equals", "admin"), "negations require every value to differ")
+	assert.True(t, match("users[*].role", "not_equals", "owner"))
+	assert.False(t, match("items[9].sku", "equals", "A-1"), "out-of-range indices select nothing")
+
+	t.Run("Top-level arrays", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"id":7}]`))
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "[0].id", Operator: "equals", Value: "7"}, req))
+	})
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestReplayRequestLog(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "replay-log")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/orders")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 201, Body: "created"})
+	vip := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 202, Body: "vip", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: vip.ID, Type: "header", Key: "X-Tier", Operator: "equals", Value: "vip"}).Error)
+
+	requestLog := database.RequestLog{
+		ProjectID:      project.ID,
+		Method:         http.MethodPost,
+		Path:           "/orders",
+		QueryParams:    "source=app",
+		RequestHeaders: `{"X-Tier":"vip","Content-Length":"2","beo-echo-loop-detect":"true"}`,
+		RequestBody:    "{}",
+	}
+	require.NoError(t, database.DB.Create(&requestLog).Error)
+
+	t.Run("Replays through the mock", func(t *testing.T) {
+		resp, err := service.ReplayRequestLog(context.Background(), project.ID, requestLog.ID, "")
+		require.NoError(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "vip", string(body))
+	})
+
+	t.Run("Replays against a target", func(t *testing.T) {
+		var received *http.Request
+		var receivedBody string
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			data, _ := io.ReadAll(r.Body)
+			receivedBody = string(data)
+			w.WriteHeader(http.StatusTeapot)
+		}))
+		defer upstream.Close()
+
+		resp, err := service.ReplayRequestLog(context.Background(), project.ID, requestLog.ID, upstream.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		require.NotNil(t, received)
+		assert.Equal(t, "/orders", received.URL.Path)
+		assert.Equal(t, "app", received.URL.Query().Get("source"))
+		assert.Equal(t, "vip", received.Header.Get("X-Tier"))
+		assert.Equal(t, "{}", receivedBody)
+	})
+
+	t.Run("Unknown log", func(t *testing.T) {
+		_, err := service.ReplayRequestLog(context.Background(), project.ID, "missing", "")
+		assert.Error(t, err)
+	})
+}
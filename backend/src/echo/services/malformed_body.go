@@ -0,0 +1,31 @@
+package services
+
+import (
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// malformBody applies the malformed-body fault injection options to a body: injectSyntaxError
+// breaks the JSON structure, then truncateBodyAt cuts the body to that many bytes.
+// The result is sent as-is; a Content-Length header configured on the response is not corrected,
+// so declaring the original length simulates a connection dropped mid-body.
+func malformBody(body string, config *database.AdvanceConfigResponse) string {
+	if config.InjectSyntaxError {
+		body = injectJSONSyntaxError(body)
+	}
+	if config.TruncateBodyAt > 0 && config.TruncateBodyAt < len(body) {
+		body = body[:config.TruncateBodyAt]
+	}
+	return body
+}
+
+// injectJSONSyntaxError inserts a stray comma right after the first opening brace or bracket
+// (e.g. `{,"id":1}`), which every JSON parser rejects. Bodies without one get a leading brace.
+func injectJSONSyntaxError(body string) string {
+	index := strings.IndexAny(body, "{[")
+	if index < 0 {
+		return "{" + body
+	}
+	return body[:index+1] + "," + body[index+1:]
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_MalformedBody(t *testing.T) {
+	body := `{"id":1,"name":"beo"}`
+
+	t.Run("Truncates at the configured byte offset", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			Headers:       `{"Content-Type":"application/json"}`,
+			AdvanceConfig: `{"truncateBodyAt": 10}`,
+		})
+		require.NoError(t, err)
+
+		got, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"id":1,"n`, string(got))
+		assert.Equal(t, int64(10), resp.ContentLength)
+		assert.False(t, json.Valid(got))
+	})
+
+	t.Run("Injects a syntax error", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			AdvanceConfig: `{"injectSyntaxError": true}`,
+		})
+		require.NoError(t, err)
+
+		got, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{,"id":1,"name":"beo"}`, string(got))
+		assert.False(t, json.Valid(got))
+	})
+
+	t.Run("Configured Content-Length is not corrected", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			Headers:       `{"Content-Length":"21"}`,
+			AdvanceConfig: `{"truncateBodyAt": 5}`,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "21", resp.Header.Get("Content-Length"))
+	})
+
+	t.Run("Offset beyond the body keeps it intact", func(t *testing.T) {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          body,
+			AdvanceConfig: `{"truncateBodyAt": 500}`,
+		})
+		require.NoError(t, err)
+
+		got, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, body, string(got))
+	})
+}
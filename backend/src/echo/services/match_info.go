@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+)
+
+// matchInfoKey is the context key of the request's MatchInfo
+type matchInfoKey struct{}
+
+// MatchInfo records which endpoint and mock response answered a request, for request logs.
+// Fields stay empty when no endpoint matched or the request was proxied.
+type MatchInfo struct {
+	EndpointID string
+	ResponseID string
+	trace      *matchTrace // Set when the project sends the beo-echo-debug header
+}
+
+// WithMatchInfo returns a request carrying an empty MatchInfo, filled in while HandleRequest
+// selects the response
+func WithMatchInfo(req *http.Request) (*http.Request, *MatchInfo) {
+	info := &MatchInfo{}
+	return req.WithContext(context.WithValue(req.Context(), matchInfoKey{}, info)), info
+}
+
+// recordMatch stores the endpoint and the selected response (nil when none was selected) in
+// the request's MatchInfo, if it has one
+func recordMatch(req *http.Request, endpoint *database.MockEndpoint, response *database.MockResponse) {
+	info, ok := req.Context().Value(matchInfoKey{}).(*MatchInfo)
+	if !ok {
+		return
+	}
+	info.EndpointID, info.ResponseID = endpoint.ID, ""
+	if response != nil {
+		info.ResponseID = response.ID
+	}
+	if info.trace != nil {
+		info.trace.Endpoint = endpoint.Method + " " + endpoint.Path
+		info.trace.EndpointID = endpoint.ID
+		info.trace.ResponseMode = endpoint.ResponseMode
+		info.trace.ResponseID = info.ResponseID
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_MatchInfo(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "match-info")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/users")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "all"})
+	admin := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "admin", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: admin.ID, Type: "query", Key: "role", Operator: "equals", Value: "admin"}).Error)
+
+	call := func(target string) *MatchInfo {
+		req, info := WithMatchInfo(httptest.NewRequest(http.MethodGet, target, nil))
+		_, err, _, _, _ := service.HandleRequest(req.Context(), project.Alias, http.MethodGet, req.URL.Path, req)
+		require.NoError(t, err)
+		return info
+	}
+
+	assert.Equal(t, &MatchInfo{EndpointID: endpoint.ID, ResponseID: admin.ID}, call("/match-info/users?role=admin"))
+	assert.Equal(t, endpoint.ID, call("/match-info/users").EndpointID)
+	assert.NotEqual(t, admin.ID, call("/match-info/users").ResponseID)
+	assert.Equal(t, &MatchInfo{}, call("/match-info/missing"), "no endpoint matched")
+
+	t.Run("Requests without MatchInfo are handled as before", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/match-info/users", nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/users", req)
+		require.NoError(t, err)
+		assert.True(t, matched)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
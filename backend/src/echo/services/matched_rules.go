@@ -0,0 +1,46 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// matchedRuleView is how a matched rule is exposed to response templates
+type matchedRuleView struct {
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// matchedRules returns the rules that selected the response: all of its rules when they match
+// the request, only the passing ones for "any" rule logic, none when it was served without
+// rules or as a fallback
+func matchedRules(response database.MockResponse, req *http.Request) []database.MockRule {
+	if req == nil || len(response.Rules) == 0 || !matchesRules(response, req) {
+		return nil
+	}
+	if !strings.EqualFold(strings.TrimSpace(response.RuleLogic), database.RuleLogicAny) {
+		return response.Rules
+	}
+
+	var passed []database.MockRule
+	for _, rule := range response.Rules {
+		if matched, ok := matchRule(rule, req); matched && ok {
+			passed = append(passed, rule)
+		}
+	}
+	return passed
+}
+
+// matchedRuleViews returns the matched rules as templates see them in .MatchedRules, an empty
+// list when no rule matched so {{json .MatchedRules}} renders []
+func matchedRuleViews(response database.MockResponse, req *http.Request) []matchedRuleView {
+	views := []matchedRuleView{}
+	for _, rule := range matchedRules(response, req) {
+		views = append(views, matchedRuleView{Type: rule.Type, Key: rule.Key, Operator: rule.Operator, Value: rule.Value})
+	}
+	return views
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestResponseTemplate_MatchedRules(t *testing.T) {
+	response := &database.MockResponse{
+		Body:          `{"matchedBy":"{{range .MatchedRules}}{{.Type}}:{{.Key}}={{.Value}}{{end}}","rules":{{json .MatchedRules}}}`,
+		AdvanceConfig: `{"templated": true}`,
+		Rules: []database.MockRule{
+			{Type: "header", Key: "X-Tier", Operator: "equals", Value: "gold"},
+		},
+	}
+
+	t.Run("Matched rules are template data", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/plans", nil)
+		req.Header.Set("X-Tier", "gold")
+
+		rendered := renderResponseTemplate(nil, response, req)
+		assert.JSONEq(t, `{"matchedBy":"header:X-Tier=gold","rules":[{"type":"header","key":"X-Tier","operator":"equals","value":"gold"}]}`, rendered.Body)
+	})
+
+	t.Run("Rule values are rendered as text, not executed", func(t *testing.T) {
+		withTemplateValue := *response
+		withTemplateValue.Rules = []database.MockRule{{Type: "header", Key: "X-Tier", Operator: "equals", Value: "{{.Method}}"}}
+		req := httptest.NewRequest(http.MethodGet, "/plans", nil)
+		req.Header.Set("X-Tier", "{{.Method}}")
+
+		rendered := renderResponseTemplate(nil, &withTemplateValue, req)
+		assert.Contains(t, rendered.Body, `"matchedBy":"header:X-Tier={{.Method}}"`)
+	})
+
+	t.Run("Fallback responses have no matched rules", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/plans", nil)
+
+		rendered := renderResponseTemplate(nil, response, req)
+		assert.JSONEq(t, `{"matchedBy":"","rules":[]}`, rendered.Body)
+	})
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMergePatch is returned when the patch or the document it applies to isn't JSON
+var ErrInvalidMergePatch = errors.New("invalid merge patch")
+
+// applyJSONMergePatch applies a JSON Merge Patch (RFC 7396) to target and returns the patched document.
+// Objects are merged recursively, a null member deletes the key, and any non-object patch
+// replaces the target entirely (this is how PATCH differs from PUT, which always replaces).
+func applyJSONMergePatch(target, patch []byte) ([]byte, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMergePatch, err)
+	}
+
+	var targetValue interface{}
+	if len(target) > 0 {
+		if err := json.Unmarshal(target, &targetValue); err != nil {
+			return nil, fmt.Errorf("%w target: %v", ErrInvalidMergePatch, err)
+		}
+	}
+
+	return json.Marshal(mergePatchValue(targetValue, patchValue))
+}
+
+// mergePatchValue implements the MergePatch(Target, Patch) pseudo-code from RFC 7396 section 2
+func mergePatchValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatchValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONMergePatch(t *testing.T) {
+	// Test cases from RFC 7396 Appendix A
+	testCases := []struct {
+		name     string
+		target   string
+		patch    string
+		expected string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null deletes member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"null deletes only that member", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"value replaced by array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested merge with delete", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are not merged", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"non-object patch replaces", `["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{"object target replaced by array", `{"a":"b"}`, `["c"]`, `["c"]`},
+		{"null patch", `{"a":"foo"}`, `null`, `null`},
+		{"string patch", `{"a":"foo"}`, `"bar"`, `"bar"`},
+		{"null member in nested missing target", `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{"non-object target becomes object", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{"deep nested null", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"empty target", ``, `{"a":1}`, `{"a":1}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := applyJSONMergePatch([]byte(tc.target), []byte(tc.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(result))
+		})
+	}
+
+	t.Run("invalid patch returns error", func(t *testing.T) {
+		_, err := applyJSONMergePatch([]byte(`{}`), []byte(`{invalid`))
+		assert.Error(t, err)
+	})
+}
@@ -2,21 +2,19 @@ package services
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/andybalholm/brotli"
-
 	"beo-echo/backend/src/database"
 	"beo-echo/backend/src/echo/repositories"
 	systemConfig "beo-echo/backend/src/systemConfigs"
@@ -36,18 +34,95 @@ func NewMockService(repo *repositories.MockRepository) *MockService {
 
 // HandleRequest processes an incoming request and returns a mock response or proxies it
 // Returns response, error, project ID, execution mode, whether the request matched an endpoint
-func (s *MockService) HandleRequest(ctx context.Context, alias, method, reqPath string, req *http.Request) (*http.Response, error, string, database.ProjectMode, bool) {
-	// Find project by alias
-	project, err := s.Repo.FindProjectByAlias(alias)
+func (s *MockService) HandleRequest(ctx context.Context, alias, method, reqPath string, req *http.Request) (resp *http.Response, err error, projectID string, mode database.ProjectMode, matched bool) {
+	// Report mock-side processing time (including applied delay), separate from upstream beo-echo-latency-ms
+	startTime := time.Now()
+	var rateLimits *rateLimitHolder
+	var corsPolicy *database.CORSPolicy
+	var debug *matchTrace
+	defer func() {
+		negotiateGeneratedResponse(resp, req)
+		applyCORSHeaders(corsPolicy, req, resp)
+		if resp != nil && resp.Header != nil && rateLimits != nil {
+			for key, values := range rateLimits.headers {
+				resp.Header[key] = values
+			}
+		}
+		if resp != nil && resp.Header != nil {
+			resp.Header.Set("beo-echo-processing-ms", strconv.FormatInt(time.Since(startTime).Milliseconds(), 10))
+		}
+		if debug != nil {
+			setDebugHeader(resp, debug, mode)
+		}
+	}()
+
+	// Skip all work for requests the client already abandoned
+	if cancelled := createCancelledResponse(ctx, nil); cancelled != nil {
+		return cancelled, nil, "", "", false
+	}
+
+	// Find project by alias, unless the CORS middleware already did
+	project, err := s.findRequestProject(req, alias)
 	if err != nil {
 		// Get default response for project not found
-		return createDefaultJSONResponse(systemConfig.DEFAULT_RESPONSE_PROJECT_NOT_FOUND), nil, "", "", false
+		return createNotFoundResponse(nil, systemConfig.DEFAULT_RESPONSE_PROJECT_NOT_FOUND), nil, "", "", false
+	}
+
+	projectConfig, configErr := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if configErr != nil {
+		projectConfig = &database.AdvanceConfigProject{}
+	}
+	if cancelled := createCancelledResponse(ctx, project); cancelled != nil {
+		return cancelled, nil, project.ID, project.Mode, false
+	}
+
+	// Health probes are answered before limits and mode handling, so they succeed while disabled
+	if isHealthCheckRequest(method, strings.TrimPrefix(reqPath, "/"+project.Alias)) {
+		return createHealthResponse(project, method), nil, project.ID, project.Mode, false
+	}
+
+	// Projects with a cors config answer preflights themselves, before any limit or mode handling
+	corsPolicy = corsPolicyFor(projectConfig)
+	if corsPolicy != nil && isPreflightRequest(req) {
+		return createPreflightResponse(corsPolicy, req), nil, project.ID, project.Mode, false
+	}
+
+	// Track in-flight requests and apply backpressure above the project's maxInFlight
+	release, ok := acquireInFlight(project.ID, projectConfig.MaxInFlight)
+	defer release()
+	if !ok {
+		return createLimitExceededResponse(project, nil, http.StatusServiceUnavailable, "Too many in-flight requests"), nil, project.ID, project.Mode, false
+	}
+
+	// Project-wide quotas shared by all endpoints; endpoint quotas are checked once matched
+	req, rateLimits = withRateLimitHolder(req)
+	rateLimits.headers, ok = takeRateLimits("project", project.ID, projectConfig.RateLimit, projectConfig.TokenBucket, req)
+	if !ok {
+		return createLimitExceededResponse(project, nil, http.StatusTooManyRequests, "Project request quota exceeded"), nil, project.ID, project.Mode, false
 	}
 
 	// Extract the actual API endpoint path
 	// Path comes in like "/api/users" or "/users" - we need just the endpoint part
 	// First trim any project alias prefix if it exists
 	cleanPath := strings.TrimPrefix(reqPath, "/"+project.Alias)
+	if projectConfig.NormalizePaths {
+		cleanPath = normalizeRequestPath(cleanPath)
+	}
+	req = withFixtureProject(withRequestPath(req, cleanPath), project.ID)
+	req = withSession(req, project.ID, projectConfig.Session)
+	if projectConfig.DebugHeader {
+		req, debug = withMatchTrace(req)
+	}
+	// Read the body once for every rule evaluated against this request
+	req = withRequestBody(req)
+	if requestBodyTooLarge(req) {
+		return createErrorResponse(http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxRequestBodyBytes())), nil, project.ID, project.Mode, false
+	}
+
+	// Reserved introspection path, answered before any mode handling when enabled
+	if isDebugPath(cleanPath) {
+		return s.handleDebugPath(project, req), nil, project.ID, project.Mode, false
+	}
 
 	// Check project mode
 	switch project.Mode {
@@ -63,27 +138,52 @@ func (s *MockService) HandleRequest(ctx context.Context, alias, method, reqPath
 	case database.ModeDisabled:
 		return createErrorResponse(http.StatusServiceUnavailable, "Service is disabled"), nil, project.ID, project.Mode, false
 	default:
-		return createErrorResponse(http.StatusInternalServerError, "Invalid project mode"), nil, project.ID, project.Mode, false
+		return createInvalidModeResponse(project), nil, project.ID, project.Mode, false
 	}
 }
 
 // handleMockMode generates mock response and returns if the request matched an endpoint
 func (s *MockService) handleMockMode(ctx context.Context, project *database.Project, method, path string, req *http.Request) (*http.Response, error, database.ProjectMode, bool) {
-	endpoint, err := s.Repo.FindMatchingEndpoint(project.ID, method, path)
+	endpoint, err := s.findEndpoint(project, method, path)
 	if err != nil {
+		// HEAD and OPTIONS fall back to the endpoints defined for other methods
+		if resp, err, matched := s.handleAutoMethod(ctx, project, method, path, req); resp != nil || err != nil {
+			return resp, err, database.ModeMock, matched
+		}
+
 		// No matching endpoint found - apply project-level delay before returning error
 		s.applyDelay(project, nil, nil)
 
 		// Get default response for endpoint not found
-		return createDefaultJSONResponse(systemConfig.DEFAULT_RESPONSE_ENDPOINT_NOT_FOUND), nil, database.ModeMock, false
+		return createNotFoundResponse(project, systemConfig.DEFAULT_RESPONSE_ENDPOINT_NOT_FOUND), nil, database.ModeMock, false
+	}
+
+	// Expose the values of :name / {name} path segments to templates
+	req = withPathParams(req, endpoint, path, pathMatchOptionsFor(project))
+	recordMatch(req, endpoint, nil)
+
+	if !checkEndpointRateLimits(endpoint, req) {
+		return createLimitExceededResponse(project, endpoint, http.StatusTooManyRequests, "Endpoint request quota exceeded"), nil, database.ModeMock, true
+	}
+
+	// Chaos testing: a share of requests fail before any response is selected
+	if failure := injectFailure(endpoint, req); failure != nil {
+		s.applyDelay(project, endpoint, nil)
+		return failure, nil, database.ModeMock, true
 	}
 
 	// Check if endpoint is configured for proxying
 	if endpoint.UseProxy && endpoint.ProxyTarget != nil {
 		// Apply delays before proxying
 		s.applyDelay(project, endpoint, nil)
+		if cancelled := createCancelledResponse(ctx, project); cancelled != nil {
+			return cancelled, nil, database.ModeProxy, false
+		}
 		// Forward the request to the proxy target
-		resp, err := executeProxyRequest(ctx, endpoint.ProxyTarget.URL, method, path, req.URL.RawQuery, req)
+		resp, err := executeProxyRequest(ctx, endpoint.ProxyTarget.URL, method, path, req.URL.RawQuery, req, proxyOptionsForEndpoint(project, endpoint).forTarget(endpoint.ProxyTarget))
+		if err == nil {
+			applyProxyResponseHeaders(project, resp)
+		}
 		return resp, err, database.ModeProxy, true
 	}
 
@@ -94,82 +194,132 @@ func (s *MockService) handleMockMode(ctx context.Context, project *database.Proj
 		s.applyDelay(project, endpoint, nil)
 
 		// Get default response for no response configured
-		return createDefaultJSONResponse(systemConfig.DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED), nil, database.ModeMock, true
+		return createDefaultJSONResponse(http.StatusOK, systemConfig.DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED), nil, database.ModeMock, true
+	}
+
+	// Don't select or build a response for an abandoned request
+	if cancelled := createCancelledResponse(ctx, project); cancelled != nil {
+		return cancelled, nil, database.ModeMock, false
 	}
 
+	// Decode wrapped payloads before rules look at the body
+	matchReq := withSequencePolicy(endpoint, withRequestSequence(endpoint.ID, transformRequestBody(endpoint, req)))
+
 	// Select response based on ResponseMode
-	response := selectResponseWithEndpoint(endpoint.ID, responses, endpoint.ResponseMode, req)
+	response := selectResponseWithEndpoint(endpoint.ID, responses, endpoint.ResponseMode, matchReq)
+	recordMatch(req, endpoint, response)
 	if response == nil {
 		// No valid response found based on rules
-		return createDefaultJSONResponse(systemConfig.DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED), nil, database.ModeMock, false
+		return createDefaultJSONResponse(http.StatusOK, systemConfig.DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED), nil, database.ModeMock, false
 	}
 
-	// Apply delays (response-level delay overrides endpoint-level delay, which overrides project-level delay)
-	s.applyDelay(project, endpoint, response)
+	// Count redirect hops so mock-to-mock redirect chains can't loop forever
+	response = applyFixtures(project.ID, response, matchReq)
+	response = renderResponseTemplate(endpoint, response, matchReq)
+	response, loopResp := prepareRedirectResponse(project, response, req)
+	if loopResp != nil {
+		return loopResp, nil, database.ModeMock, true
+	}
 
-	// Create and return HTTP response with match indicator
-	resp, err := createMockResponse(*response)
+	// Apply delays (response-level delay overrides endpoint-level delay, which overrides project-level delay)
+	// and create the HTTP response with match indicator
+	resp, err := s.respondWithDelay(ctx, project, endpoint, response)
 	return resp, err, database.ModeMock, true
 }
 
 // handleProxyMode checks for mock endpoint first, if not found forwards the request to target
 func (s *MockService) handleProxyMode(ctx context.Context, project *database.Project, method, path string, req *http.Request) (*http.Response, bool, error) {
-	if project.ActiveProxy == nil {
+	target := resolveProxyTarget(project, path, req)
+	if target == nil {
 		return createErrorResponse(http.StatusInternalServerError, "No proxy target configured"), false, nil
 	}
 
-	// Check for recursive proxy loops by checking for any header with beo-echo prefix
-	for name := range req.Header {
-		if strings.HasPrefix(strings.ToLower(name), "beo-echo") {
-			return createErrorResponse(http.StatusLoopDetected, "Proxy loop detected: request contains beo-echo header"), false, nil
-		}
+	// Requests we forwarded ourselves coming back are a proxy loop
+	if isProxyLoop(req) {
+		return createLoopDetectedResponse(project, "Proxy loop detected: request contains "+loopDetectHeader+" header"), false, nil
 	}
 
 	// First check if a mock endpoint exists for this request
-	endpoint, err := s.Repo.FindMatchingEndpoint(project.ID, method, path)
+	endpoint, err := s.findEndpoint(project, method, path)
 	if err == nil {
+		req := withPathParams(req, endpoint, path, pathMatchOptionsFor(project))
+		if !checkEndpointRateLimits(endpoint, req) {
+			return createLimitExceededResponse(project, endpoint, http.StatusTooManyRequests, "Endpoint request quota exceeded"), true, nil
+		}
+
 		// Found a matching endpoint, use the mock response
-		responses, err := s.Repo.FindResponsesByEndpointID(endpoint.ID)
-		if err == nil && len(responses) > 0 {
-			// Select response based on ResponseMode
-			response := selectResponseWithEndpoint(endpoint.ID, responses, endpoint.ResponseMode, req)
-			if response != nil {
-				// Apply delay with proper priority: Response > Endpoint > Project
-				s.applyDelay(project, endpoint, response)
-
-				// Create and return HTTP response from mock
-				resp, err := createMockResponse(*response)
-				if err == nil {
-					// Add header to indicate response was mocked
-					resp.Header.Set("beo-echo-response-type", "mock")
-					return resp, true, nil // True because it was handled by a mock endpoint
-				}
-			}
+		if resp := s.respondWithEndpointMock(ctx, project, endpoint, req); resp != nil {
+			return resp, true, nil // True because it was handled by a mock endpoint
 		}
 	}
 
 	// No matching mock endpoint found or error occurred, forward to target
 	// Apply project-level delay before forwarding
 	s.applyDelay(project, nil, nil)
-	resp, err := executeProxyRequest(ctx, project.ActiveProxy.URL, method, path, req.URL.RawQuery, req)
+	if cancelled := createCancelledResponse(ctx, project); cancelled != nil {
+		return cancelled, false, nil
+	}
+	resp, err := executeProxyRequest(ctx, target.URL, method, path, req.URL.RawQuery, req, proxyOptionsFor(project).forTarget(target))
+	if err == nil && resp != nil {
+		// Failed upstream answers may be replaced by the project's fallback mock
+		if fallback := s.respondWithProxyFallback(ctx, project, path, req, resp); fallback != nil {
+			return fallback, true, nil
+		}
+	}
 	if err == nil && resp != nil && resp.Header != nil {
-		// Add header to indicate response was proxied
+		// Sanitize upstream headers, then indicate response was proxied
+		applyProxyResponseHeaders(project, resp)
+		s.recordProxiedResponse(project, method, path, req, resp)
 		resp.Header.Set("beo-echo-response-type", "proxy")
 	}
 	return resp, false, err // False because it was forwarded to target, not handled by a mock
 }
 
+// respondWithEndpointMock builds the endpoint's mock response for a proxied request, or returns
+// nil when none of its responses match so the request is forwarded instead
+func (s *MockService) respondWithEndpointMock(ctx context.Context, project *database.Project, endpoint *database.MockEndpoint, req *http.Request) *http.Response {
+	responses, err := s.Repo.FindResponsesByEndpointID(endpoint.ID)
+	if err != nil || len(responses) == 0 {
+		return nil
+	}
+
+	// Select response based on ResponseMode, matching against the decoded body
+	matchReq := withSequencePolicy(endpoint, withRequestSequence(endpoint.ID, transformRequestBody(endpoint, req)))
+	response := selectResponseWithEndpoint(endpoint.ID, responses, endpoint.ResponseMode, matchReq)
+	if response != nil {
+		recordMatch(req, endpoint, response)
+	}
+	response = applyFixtures(project.ID, response, matchReq)
+	response = renderResponseTemplate(endpoint, response, matchReq)
+	response, loopResp := prepareRedirectResponse(project, response, req)
+	if loopResp != nil {
+		return loopResp
+	}
+	if response == nil {
+		return nil
+	}
+
+	// Apply delay with proper priority: Response > Endpoint > Project
+	// and create the HTTP response from mock
+	resp, err := s.respondWithDelay(ctx, project, endpoint, response)
+	if err != nil {
+		return nil
+	}
+	// Add header to indicate response was mocked
+	resp.Header.Set("beo-echo-response-type", "mock")
+	return resp
+}
+
 // handleForwarderMode always forwards requests to the target without checking for mock endpoints
 func (s *MockService) handleForwarderMode(ctx context.Context, project *database.Project, method, path string, req *http.Request) (*http.Response, error) {
-	if project.ActiveProxy == nil {
+	target := resolveProxyTarget(project, path, req)
+	if target == nil {
 		return createErrorResponse(http.StatusInternalServerError, "No proxy target configured"), nil
 	}
 
-	// Check for recursive proxy loops by checking for any header with beo-echo prefix
-	for name := range req.Header {
-		if strings.HasPrefix(strings.ToLower(name), "beo-echo") {
-			return createErrorResponse(http.StatusLoopDetected, "Proxy loop detected: request contains beo-echo header"), nil
-		}
+	// Requests we forwarded ourselves coming back are a proxy loop
+	if isProxyLoop(req) {
+		return createLoopDetectedResponse(project, "Proxy loop detected: request contains "+loopDetectHeader+" header"), nil
 	}
 
 	// Use the common executeProxyRequest helper function, but with the path parameter
@@ -177,19 +327,25 @@ func (s *MockService) handleForwarderMode(ctx context.Context, project *database
 	// Note: handleForwarderMode always returns false for match status in HandleRequest
 	// Apply project-level delay before forwarding
 	s.applyDelay(project, nil, nil)
+	if cancelled := createCancelledResponse(ctx, project); cancelled != nil {
+		return cancelled, nil
+	}
 
-	return executeProxyRequest(ctx, project.ActiveProxy.URL, method, path, req.URL.RawQuery, req)
+	resp, err := executeProxyRequest(ctx, target.URL, method, path, req.URL.RawQuery, req, proxyOptionsFor(project).forTarget(target))
+	if err == nil {
+		applyProxyResponseHeaders(project, resp)
+		s.recordProxiedResponse(project, method, path, req, resp)
+	}
+	return resp, err
 }
 
 // executeProxyRequest is a common helper function to forward requests to a target URL
 // with proper header and body copying. This centralizes the forwarding logic for both
 // proxy and forwarder modes.
-func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr, queryString string, req *http.Request) (*http.Response, error) {
-	// Check for recursive proxy loops by checking for any header with beo-echo prefix
-	for name := range req.Header {
-		if strings.HasPrefix(strings.ToLower(name), "beo-echo") {
-			return createErrorResponse(http.StatusLoopDetected, "Proxy loop detected: request contains beo-echo header"), nil
-		}
+func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr, queryString string, req *http.Request, opts proxyOptions) (*http.Response, error) {
+	// Requests we forwarded ourselves coming back are a proxy loop
+	if isProxyLoop(req) {
+		return createErrorResponse(http.StatusLoopDetected, "Proxy loop detected: request contains "+loopDetectHeader+" header"), nil
 	}
 
 	targetURL, err := url.Parse(targetURLString)
@@ -198,12 +354,19 @@ func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr,
 	}
 
 	// Create a new client with desired configuration
+	tlsConfig, err := proxyTLSConfig(opts.tlsVerify)
+	if err != nil {
+		return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Invalid proxy TLS configuration: %s", err.Error())), nil
+	}
+
+	// The timeout covers connecting, the headers and reading the whole body
+	timeout := opts.upstreamTimeout()
 	client := &http.Client{
-		Timeout: time.Second * 30,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true, // Disable SSL verification
-			},
+		Timeout:   timeout,
+		Transport: proxyTransport(opts.httpVersion, targetURL.Scheme, tlsConfig),
+		// Upstream redirects go back to the client (and through proxyResponseRewrite) unfollowed
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
 		},
 	}
 
@@ -216,12 +379,14 @@ func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr,
 	// Read the original request body if present
 	var bodyBytes []byte
 	if req.Body != nil {
-		bodyBytes, err = io.ReadAll(req.Body)
+		limit := maxRequestBodyBytes()
+		bodyBytes, err = readRequestBody(req, limit)
+		if errors.Is(err, errRequestBodyTooLarge) {
+			return createErrorResponse(http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", limit)), nil
+		}
 		if err != nil {
 			return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Failed to read request body: %s", err.Error())), nil
 		}
-		// Restore the original body
-		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	}
 
 	// Create a new request with all original attributes
@@ -235,28 +400,58 @@ func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr,
 		return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Failed to create request: %s", err.Error())), nil
 	}
 
-	// Copy all headers
+	// Copy all headers but the beo-echo-* ones steering the mock (scenario, random seed, ...)
 	for key, values := range req.Header {
+		if key == "Referer" || isControlHeader(key) {
+			continue
+		}
 		for _, value := range values {
-			if key != "Referer" {
-				newReq.Header.Add(key, value)
-			}
+			newReq.Header.Add(key, value)
 		}
 	}
 
-	// Set host header to target host
-	newReq.Host = targetURL.Host
+	// Set host header to the target host, the client's Host or a literal one (virtual hosting)
+	newReq.Host = opts.upstreamHost(targetURL, req)
+
+	// Configured header edits win over the client's headers, project first, then endpoint
+	for _, rewrite := range opts.requestHeaders {
+		rewriteHeaders(newReq.Header, rewrite)
+	}
 
 	// Add loop detection header to prevent recursive proxying
-	newReq.Header.Set("beo-echo-loop-detect", "true")
+	newReq.Header.Set(loopDetectHeader, "true")
+
+	// An open circuit answers with its fallback without reaching the upstream
+	if opts.circuit != nil {
+		if allowed, state := opts.circuit.allow(opts.circuitConfig); !allowed {
+			return createCircuitOpenResponse(opts.circuitConfig, state), nil
+		}
+	}
 
 	// Track request time for latency measurement
 	startTime := time.Now()
 
-	// Execute the request
-	resp, err := client.Do(newReq)
-	if err != nil {
-		return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Request error: %s", err.Error())), nil
+	// Execute the request, retrying gateway failures when the project allows it. Every attempt
+	// replays the buffered body.
+	attempts := opts.retry.attemptsFor(method)
+	attempt := 1
+	bufferLimit := proxyBufferLimit()
+	resp := sendProxyAttempt(client, newReq, bodyBytes, timeout, bufferLimit)
+	for attempt < attempts && isRetryableProxyStatus(resp.StatusCode) {
+		if !waitProxyBackoff(ctx, opts.retry.backoffBefore(attempt)) {
+			break
+		}
+		resp.Body.Close()
+		attempt++
+		resp = sendProxyAttempt(client, newReq, bodyBytes, timeout, bufferLimit)
+	}
+	if opts.retry.enabled() {
+		resp.Header.Set("beo-echo-proxy-attempts", strconv.Itoa(attempt))
+	}
+
+	// Retries count as one outcome for the breaker
+	if opts.circuit != nil {
+		resp.Header.Set("beo-echo-circuit-state", opts.circuit.record(opts.circuitConfig, resp.StatusCode))
 	}
 
 	latencyMS := time.Since(startTime).Milliseconds()
@@ -267,40 +462,104 @@ func executeProxyRequest(ctx context.Context, targetURLString, method, pathStr,
 		resp.Header.Set("beo-echo-latency-ms", fmt.Sprintf("%d", latencyMS))
 	}
 
+	if opts.decodeResponse {
+		decodeProxyResponse(resp, decodedBodyLimit(bufferLimit))
+	}
+
 	return resp, nil
 }
 
+// sendProxyAttempt sends one copy of the prepared upstream request and reads its body, up to
+// bufferLimit bytes (see bufferProxyBody). Failures become error responses: 504 when the timeout
+// fired, 502 otherwise.
+func sendProxyAttempt(client *http.Client, newReq *http.Request, bodyBytes []byte, timeout time.Duration, bufferLimit int64) *http.Response {
+	attemptReq := newReq.Clone(newReq.Context())
+	attemptReq.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+
+	resp, err := client.Do(attemptReq)
+	if err != nil {
+		if isTimeoutError(err) {
+			return createErrorResponse(http.StatusGatewayTimeout, fmt.Sprintf("Upstream timed out after %s", timeout))
+		}
+		return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Request error: %s", err.Error()))
+	}
+
+	// Read small bodies here so a timeout during the transfer is reported like one before the
+	// headers; larger ones are streamed and a timeout cuts them short
+	if err := bufferProxyBody(resp, bufferLimit); err != nil {
+		if isTimeoutError(err) {
+			return createErrorResponse(http.StatusGatewayTimeout, fmt.Sprintf("Upstream timed out after %s while sending the body", timeout))
+		}
+		return createErrorResponse(http.StatusBadGateway, fmt.Sprintf("Failed to read upstream response: %s", err.Error()))
+	}
+	return resp
+}
+
+// isTimeoutError reports whether a client error is a timeout rather than a connection failure
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
 // Helper functions
 
 // selectResponseWithEndpoint selects a response based on mode and rules with endpoint ID for round-robin
 func selectResponseWithEndpoint(endpointID string, responses []database.MockResponse, mode string, req *http.Request) *database.MockResponse {
+	// Responses scheduled for another time are never candidates, including as fallbacks
+	responses = filterResponsesBySchedule(responses, nowFunc())
+
+	// Keep only responses for the current environment (X-Env header or server config)
+	responses = filterResponsesByEnvironment(responses, requestEnvironment(req))
+
+	// Keep only responses for the requested scenario (beo-echo-scenario header)
+	responses = filterResponsesByScenario(responses, requestScenario(req))
+
+	// Keep only responses whose declared body size range fits the request
+	responses = filterResponsesByBodySize(responses, req)
+
 	// Filter responses by rules first
 	validResponses := filterResponsesByRules(responses, req)
+	trace := matchTraceFrom(req)
+	if trace != nil {
+		trace.Candidates, trace.PassedRules = traceResponses(responses), traceResponses(validResponses)
+	}
 	var fallbackResponses *database.MockResponse
 	if len(validResponses) == 0 {
 		// If no responses match rules, fallback to all responses
 		// search fallback responses
-		for _, resp := range responses {
-
-			if len(resp.Rules) == 0 {
-				validResponses = append(validResponses, resp)
+		for i := range responses {
+			if len(responses[i].Rules) == 0 {
+				validResponses = append(validResponses, responses[i])
 				continue
 			}
 
-			if resp.IsFallback {
-				fallbackResponses = &resp
+			// Point into the slice, not at the loop variable
+			if responses[i].IsFallback {
+				fallbackResponses = &responses[i]
 			}
 		}
 	}
 
 	if len(validResponses) == 0 && fallbackResponses == nil {
+		if trace != nil {
+			trace.Reason = "no response matched its rules and none is marked as fallback"
+		}
 		return nil
 	} else if len(validResponses) == 0 && fallbackResponses != nil {
+		if trace != nil {
+			trace.Reason = "no response matched its rules, served the fallback response"
+		}
 		return fallbackResponses
 	}
 
 	// Sort by priority (higher is more important)
 	sortByPriority(validResponses)
+	if trace != nil {
+		trace.Reason = selectionReason(mode, len(validResponses))
+		if len(trace.PassedRules) == 0 {
+			trace.Reason = "no response matched its rules, using the responses without rules: " + trace.Reason
+		}
+	}
 
 	// Select based on mode
 	switch strings.ToLower(mode) {
@@ -309,14 +568,24 @@ func selectResponseWithEndpoint(endpointID string, responses []database.MockResp
 		return &validResponses[0]
 	case "random":
 		// Return random response
-		return &validResponses[rand.Intn(len(validResponses))]
+		return &validResponses[pickRandomIndex(len(validResponses), req)]
+	case "shuffle":
+		// Serve each response once in random order before reshuffling
+		response := getNextShuffleResponse(endpointID, validResponses)
+		return &response
+	case "weighted":
+		// Pick proportionally to each response's evaluated weight
+		return selectWeightedResponse(validResponses, req)
 	case "round_robin":
 		// Use the actual endpoint ID for round-robin selection
 		response := getNextRoundRobinResponse(endpointID, validResponses)
 		return &response
+	case "sequence":
+		// Serve responses once each in order, then repeat the last or answer 410
+		return getNextSequenceResponse(endpointID, validResponses, req)
 	default:
 		// Default to random
-		return &validResponses[rand.Intn(len(validResponses))]
+		return &validResponses[pickRandomIndex(len(validResponses), req)]
 	}
 }
 
@@ -348,74 +617,212 @@ func sortByPriority(responses []database.MockResponse) {
 	}
 }
 
-// matchesRules checks if a response matches all rules against the request
+// matchesRules checks if a response matches its rules against the request: all of them by
+// default, or any one of them when the response's rule logic is "any"
 func matchesRules(response database.MockResponse, req *http.Request) bool {
 	if len(response.Rules) == 0 {
 		return true // No rules means always match
 	}
 
+	anyRule := strings.EqualFold(strings.TrimSpace(response.RuleLogic), database.RuleLogicAny)
+	evaluated := false
 	for _, rule := range response.Rules {
-		switch rule.Type {
-		case "header":
-			if !matchHeaderRule(rule, req) {
-				return false
-			}
-		case "query":
-			if !matchQueryRule(rule, req) {
-				return false
-			}
-		case "body":
-			if !matchBodyRule(rule, req) {
-				return false
-			}
+		matched, ok := matchRule(rule, req)
+		if !ok {
+			continue
+		}
+		evaluated = true
+		if anyRule && matched {
+			return true
+		}
+		if !anyRule && !matched {
+			return false
 		}
-		// Path rules are handled earlier during endpoint matching
 	}
 
-	return true
+	// With any, responses whose rules are all checked elsewhere still match like they do with all
+	return !anyRule || !evaluated
+}
+
+// matchRule checks a single rule against the request. ok is false for rule types that are not
+// evaluated during response selection.
+func matchRule(rule database.MockRule, req *http.Request) (matched bool, ok bool) {
+	switch rule.Type {
+	case "header":
+		return matchHeaderRule(rule, req), true
+	case "query":
+		return matchQueryRule(rule, req), true
+	case "cookie":
+		return matchCookieRule(rule, req), true
+	case "method":
+		return matchMethodRule(rule, req), true
+	case "ip":
+		return matchIPRule(rule, req), true
+	case "body":
+		return matchBodyRule(rule, req), true
+	case "nth_request":
+		return matchNthRequestRule(rule, req), true
+	case "call_index":
+		return matchCallIndexRule(rule, req), true
+	case "device":
+		return matchDeviceRule(rule, req), true
+	case "query_signature":
+		return matchQuerySignatureRule(rule, req), true
+	case "request_line":
+		return matchRequestLineRule(rule, req), true
+	case "body_hash":
+		return matchBodyHashRule(rule, req), true
+	case "geo":
+		return matchGeoRule(rule, req), true
+	case "header_base64":
+		return matchHeaderBase64Rule(rule, req), true
+	case "fixture":
+		return matchFixtureRule(rule, req), true
+	case "xml_body":
+		return matchXMLBodyRule(rule, req), true
+	case "graphql":
+		return matchGraphQLRule(rule, req), true
+	}
+	// Path rules are handled earlier during endpoint matching
+	return true, false
 }
 
 // matchHeaderRule checks if a header rule matches
 func matchHeaderRule(rule database.MockRule, req *http.Request) bool {
+	// Presence operators look at the map so an empty header still counts as sent
+	switch strings.ToLower(rule.Operator) {
+	case "exists":
+		_, ok := req.Header[http.CanonicalHeaderKey(rule.Key)]
+		return ok
+	case "not_exists":
+		_, ok := req.Header[http.CanonicalHeaderKey(rule.Key)]
+		return !ok
+	}
+
 	headerValue := req.Header.Get(rule.Key)
 	return matchRuleValue(rule.Operator, headerValue, rule.Value)
 }
 
 // matchQueryRule checks if a query parameter rule matches
 func matchQueryRule(rule database.MockRule, req *http.Request) bool {
+	// Presence operators look at the map so "?debug" and "?debug=" count as sent
+	switch strings.ToLower(rule.Operator) {
+	case "exists":
+		_, ok := req.URL.Query()[rule.Key]
+		return ok
+	case "not_exists":
+		_, ok := req.URL.Query()[rule.Key]
+		return !ok
+	}
+
 	queryValue := req.URL.Query().Get(rule.Key)
 	return matchRuleValue(rule.Operator, queryValue, rule.Value)
 }
 
+// matchCookieRule checks if a cookie rule matches the value of the named request cookie
+func matchCookieRule(rule database.MockRule, req *http.Request) bool {
+	cookie, err := req.Cookie(rule.Key)
+	switch strings.ToLower(rule.Operator) {
+	case "exists":
+		return err == nil
+	case "not_exists":
+		return err != nil
+	}
+
+	cookieValue := ""
+	if err == nil {
+		cookieValue = cookie.Value
+	}
+	return matchRuleValue(rule.Operator, cookieValue, rule.Value)
+}
+
+// matchMethodRule checks if a method rule matches the request method, so an endpoint reached by
+// several methods (e.g. a GET endpoint answering automatic HEAD requests) can branch on it. The
+// key is ignored; methods compare case-insensitively except for regex.
+func matchMethodRule(rule database.MockRule, req *http.Request) bool {
+	expected := rule.Value
+	if !strings.EqualFold(rule.Operator, "regex") {
+		expected = strings.ToUpper(strings.TrimSpace(expected))
+	}
+	return matchRuleValue(rule.Operator, strings.ToUpper(req.Method), expected)
+}
+
 // matchBodyRule checks if a body rule matches
 func matchBodyRule(rule database.MockRule, req *http.Request) bool {
-	// Get body content (this is a simplistic approach; in real-world you'd want to cache)
+	// Presence operators branch on whether a body was sent at all; the key is ignored
+	switch strings.ToLower(rule.Operator) {
+	case "empty":
+		return isRequestBodyEmpty(req)
+	case "not_empty":
+		return !isRequestBodyEmpty(req)
+	}
+
 	if req.Body == nil {
 		return false
 	}
 
-	// Read body
-	bodyBytes, err := io.ReadAll(req.Body)
+	// Body bytes and the decoded JSON come from the per-request cache, shared by all body rules
+	bodyBytes, err := requestBodyBytes(req)
 	if err != nil {
 		return false
 	}
 
-	// Restore body for subsequent reads
-	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	// Form bodies (urlencoded or multipart) address fields by name
+	if form, ok := requestBodyForm(req); ok {
+		switch strings.ToLower(rule.Operator) {
+		case "exists":
+			return form.has(rule.Key)
+		case "not_exists":
+			return !form.has(rule.Key)
+		}
+		return matchFormRule(rule, form)
+	}
 
-	// For JSON bodies, try to extract nested values
-	var bodyData map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &bodyData); err == nil {
-		// Try to find nested value
-		if value := getNestedValue(bodyData, rule.Key); value != "" {
-			return matchRuleValue(rule.Operator, value, rule.Value)
+	// For JSON bodies, select the values at the rule's JSONPath (e.g. "items[0].sku", "users[*].role")
+	if bodyData, err := requestBodyJSON(req); err == nil {
+		values, err := jsonPathValues(bodyData, rule.Key)
+		switch strings.ToLower(rule.Operator) {
+		case "exists":
+			return err == nil && len(values) > 0
+		case "not_exists":
+			return err == nil && len(values) == 0
+		}
+		if err == nil && len(values) > 0 {
+			selected := make([]string, len(values))
+			for i, value := range values {
+				selected[i] = stringifyJSONValue(value)
+			}
+			return matchSelectedValues(rule.Operator, selected, rule.Value)
 		}
 	}
 
-	// Fallback to treating body as string
+	// Fallback to treating body as string; it has no fields for the presence operators
+	switch strings.ToLower(rule.Operator) {
+	case "exists":
+		return false
+	case "not_exists":
+		return true
+	}
 	return matchRuleValue(rule.Operator, string(bodyBytes), rule.Value)
 }
 
+// isRequestBodyEmpty reports whether the request carries no body bytes.
+// A missing body, Content-Length: 0 and a chunked body with no data all count as empty.
+func isRequestBodyEmpty(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+
+	// Content-Length may be unknown (chunked) or not set on constructed requests,
+	// so look at the body itself
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil {
+		return true
+	}
+
+	return len(bodyBytes) == 0
+}
+
 // matchRuleValue compares values based on operator
 func matchRuleValue(operator, actual, expected string) bool {
 	switch strings.ToLower(operator) {
@@ -423,6 +830,16 @@ func matchRuleValue(operator, actual, expected string) bool {
 		return actual == expected
 	case "contains":
 		return strings.Contains(actual, expected)
+	case "not_equals":
+		return actual != expected
+	case "not_contains":
+		return !strings.Contains(actual, expected)
+	case "regex":
+		return matchRuleRegex(expected, actual)
+	case "cidr":
+		return matchRuleCIDR(expected, actual)
+	case "gt", "gte", "lt", "lte":
+		return matchRuleNumber(strings.ToLower(operator), actual, expected)
 	default:
 		return actual == expected // Default to equals
 	}
@@ -448,92 +865,12 @@ func getNestedValue(data map[string]interface{}, key string) string {
 		}
 	}
 
-	// Convert result to string
-	switch v := current.(type) {
-	case string:
-		return v
-	case bool, int, float64:
-		return fmt.Sprintf("%v", v)
-	default:
-		bytes, err := json.Marshal(current)
-		if err != nil {
-			return ""
-		}
-		return string(bytes)
-	}
+	return stringifyJSONValue(current)
 }
 
-// createMockResponse builds an HTTP response from a mock response
+// createMockResponse builds an HTTP response from a mock response with the default pipeline
 func createMockResponse(mockResp database.MockResponse) (*http.Response, error) {
-	// Parse headers first to check for Content-Encoding
-	var headers map[string]string
-	if err := json.Unmarshal([]byte(mockResp.Headers), &headers); err != nil {
-		fmt.Println("Error unmarshalling headers:", err)
-		headers = make(map[string]string)
-	}
-
-	// Check for Content-Encoding header
-	contentEncoding := ""
-	for key, value := range headers {
-		if strings.ToLower(key) == "content-encoding" {
-			contentEncoding = strings.ToLower(value)
-			break
-		}
-	}
-
-	// Prepare response body based on Content-Encoding
-	var body io.ReadCloser
-	var contentLength int64
-
-	switch contentEncoding {
-	case "gzip":
-		// Compress the body using gzip
-		var buf bytes.Buffer
-		writer := gzip.NewWriter(&buf)
-		if _, err := writer.Write([]byte(mockResp.Body)); err != nil {
-			writer.Close()
-			return nil, fmt.Errorf("failed to gzip compress response body: %w", err)
-		}
-		if err := writer.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close gzip writer: %w", err)
-		}
-		body = io.NopCloser(&buf)
-		contentLength = int64(buf.Len())
-
-	case "br":
-		// Compress the body using Brotli
-		var buf bytes.Buffer
-		writer := brotli.NewWriter(&buf)
-		if _, err := writer.Write([]byte(mockResp.Body)); err != nil {
-			writer.Close()
-			return nil, fmt.Errorf("failed to brotli compress response body: %w", err)
-		}
-		if err := writer.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close brotli writer: %w", err)
-		}
-		body = io.NopCloser(&buf)
-		contentLength = int64(buf.Len())
-
-	default:
-		// No compression or unsupported encoding, use raw body
-		body = io.NopCloser(strings.NewReader(mockResp.Body))
-		contentLength = int64(len(mockResp.Body))
-	}
-
-	// Create response
-	resp := &http.Response{
-		StatusCode:    mockResp.StatusCode,
-		Body:          body,
-		Header:        make(http.Header),
-		ContentLength: contentLength,
-	}
-
-	// Set headers
-	for key, value := range headers {
-		resp.Header.Set(key, value)
-	}
-
-	return resp, nil
+	return runResponsePipeline(defaultResponsePipeline, mockResp)
 }
 
 // createErrorResponse creates a standard error response
@@ -544,7 +881,10 @@ func createErrorResponse(statusCode int, message string) *http.Response {
 	}
 
 	jsonBody, _ := json.Marshal(respBody)
-	body := io.NopCloser(bytes.NewBuffer(jsonBody))
+	if template := defaultResponseTemplate(statusCode); template != "" {
+		jsonBody = []byte(renderDefaultResponseTemplate(template, statusCode, message))
+	}
+	body := newGeneratedBody(jsonBody)
 
 	resp := &http.Response{
 		StatusCode:    statusCode,
@@ -557,19 +897,22 @@ func createErrorResponse(statusCode int, message string) *http.Response {
 	return resp
 }
 
-// createDefaultJSONResponse creates a default JSON response with 200 status code
-func createDefaultJSONResponse(message string) *http.Response {
+// createDefaultJSONResponse creates a default JSON response with the given status code
+func createDefaultJSONResponse(statusCode int, message string) *http.Response {
 	responseBody := map[string]interface{}{
 		"message": message,
 	}
 
 	jsonBody, _ := json.Marshal(responseBody)
 	jsonString := string(jsonBody)
+	if template := defaultResponseTemplate(statusCode); template != "" {
+		jsonString = renderDefaultResponseTemplate(template, statusCode, message)
+	}
 
-	body := io.NopCloser(strings.NewReader(jsonString))
+	body := newGeneratedBody([]byte(jsonString))
 
 	resp := &http.Response{
-		StatusCode:    http.StatusOK,
+		StatusCode:    statusCode,
 		Body:          body,
 		Header:        make(http.Header),
 		ContentLength: int64(len(jsonString)),
@@ -579,13 +922,55 @@ func createDefaultJSONResponse(message string) *http.Response {
 	return resp
 }
 
+// respondWithDelay builds the mock response and applies the configured delay, either before
+// anything is sent (default) or between the headers and the body when delayBodyOnly is set
+func (s *MockService) respondWithDelay(ctx context.Context, project *database.Project, endpoint *database.MockEndpoint, response *database.MockResponse) (*http.Response, error) {
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err == nil && config.TimeoutAfterMs > 0 {
+		return respondWithGatewayTimeout(ctx, response, config.TimeoutAfterMs)
+	}
+	if err != nil || !config.DelayBodyOnly {
+		s.applyDelay(project, endpoint, response)
+		resp, err := createStreamOrMockResponse(ctx, *response, config)
+		if err == nil {
+			applyProjectDateSkew(resp, project, response)
+		}
+		return resp, err
+	}
+
+	resp, err := createStreamOrMockResponse(ctx, *response, config)
+	if err != nil {
+		return nil, err
+	}
+	applyProjectDateSkew(resp, project, response)
+	if delayMs := s.resolveDelayMs(project, endpoint, response); delayMs > 0 {
+		resp.Body = newDelayedBody(ctx, resp.Body, time.Duration(delayMs)*time.Millisecond)
+	}
+	return resp, nil
+}
+
 // applyDelay applies delay based on priority: Response DelayMS > Endpoint DelayMs > Project DelayMs
 // Response parameter is optional - pass nil when response delay is not applicable
 func (s *MockService) applyDelay(project *database.Project, endpoint *database.MockEndpoint, response *database.MockResponse) {
+	delayMs := s.resolveDelayMs(project, endpoint, response)
+
+	// Apply delay if configured
+	if delayMs > 0 {
+		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+	}
+}
+
+// resolveDelayMs picks the delay with priority: Response DelayMS > Endpoint DelayMs > Project DelayMs.
+// At each level a delayRange, sampled for every call, takes the place of the fixed delay, as
+// does the endpoint's latencyProfile.
+func (s *MockService) resolveDelayMs(project *database.Project, endpoint *database.MockEndpoint, response *database.MockResponse) int {
 	var delayMs int
 
 	// Response delay has highest priority
 	if response != nil {
+		if responseConfig, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig); err == nil && responseConfig.DelayRange != nil {
+			return sampleDelayMs(responseConfig.DelayRange)
+		}
 		if response.DelayMS > 0 {
 			delayMs = response.DelayMS
 		}
@@ -594,8 +979,16 @@ func (s *MockService) applyDelay(project *database.Project, endpoint *database.M
 	// Endpoint delay overrides project delay
 	if endpoint != nil && delayMs == 0 {
 		if endpoint.AdvanceConfig != "" {
-			if endpointConfig, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig); err == nil && endpointConfig.DelayMs > 0 {
-				delayMs = endpointConfig.DelayMs
+			if endpointConfig, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig); err == nil {
+				if endpointConfig.LatencyProfile != nil {
+					return sampleLatencyProfileMs(endpointConfig.LatencyProfile)
+				}
+				if endpointConfig.DelayRange != nil {
+					return sampleDelayMs(endpointConfig.DelayRange)
+				}
+				if endpointConfig.DelayMs > 0 {
+					delayMs = endpointConfig.DelayMs
+				}
 			}
 		}
 	}
@@ -604,13 +997,13 @@ func (s *MockService) applyDelay(project *database.Project, endpoint *database.M
 	if project != nil && delayMs == 0 {
 		if project.AdvanceConfig != "" {
 			if projectConfig, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil {
+				if projectConfig.DelayRange != nil {
+					return sampleDelayMs(projectConfig.DelayRange)
+				}
 				delayMs = projectConfig.DelayMs
 			}
 		}
 	}
 
-	// Apply delay if configured
-	if delayMs > 0 {
-		time.Sleep(time.Duration(delayMs) * time.Millisecond)
-	}
+	return delayMs
 }
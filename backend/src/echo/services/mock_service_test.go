@@ -1,12 +1,18 @@
 package services
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"beo-echo/backend/src/database"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMockService_applyDelay(t *testing.T) {
@@ -232,3 +238,271 @@ func TestMockService_applyDelay_EdgeCases(t *testing.T) {
 		assert.LessOrEqual(t, elapsed.Milliseconds(), int64(70))
 	})
 }
+
+func TestMockService_respondWithDelay_BodyOnly(t *testing.T) {
+	service := &MockService{}
+	response := &database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"ok":true}`,
+		DelayMS:       50,
+		AdvanceConfig: `{"delayBodyOnly": true}`,
+	}
+
+	t.Run("Headers are ready immediately and body waits for the delay", func(t *testing.T) {
+		start := time.Now()
+		resp, err := service.respondWithDelay(context.Background(), nil, nil, response)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start).Milliseconds(), int64(10))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"ok":true}`, string(body))
+		assert.GreaterOrEqual(t, time.Since(start).Milliseconds(), int64(45))
+	})
+
+	t.Run("Cancelled context aborts the body wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		resp, err := service.respondWithDelay(ctx, nil, nil, response)
+		require.NoError(t, err)
+		cancel()
+
+		_, err = io.ReadAll(resp.Body)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestMatchBodyRule_Presence(t *testing.T) {
+	emptyRule := database.MockRule{Type: "body", Operator: "empty"}
+	notEmptyRule := database.MockRule{Type: "body", Operator: "not_empty"}
+
+	t.Run("No body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", nil)
+		assert.True(t, matchBodyRule(emptyRule, req))
+		assert.False(t, matchBodyRule(notEmptyRule, req))
+	})
+
+	t.Run("Zero Content-Length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(""))
+		req.ContentLength = 0
+		assert.True(t, matchBodyRule(emptyRule, req))
+	})
+
+	t.Run("Chunked body without data", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", io.NopCloser(strings.NewReader("")))
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+		assert.True(t, matchBodyRule(emptyRule, req))
+	})
+
+	t.Run("Body present is preserved after the check", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", io.NopCloser(strings.NewReader(`{"a":1}`)))
+		req.ContentLength = -1
+		assert.True(t, matchBodyRule(notEmptyRule, req))
+		assert.False(t, matchBodyRule(emptyRule, req))
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+	})
+}
+
+func TestMatchRuleValue_Regex(t *testing.T) {
+	bearer := `^Bearer [A-Za-z0-9._-]+$`
+
+	t.Run("Header rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer abc.def-123")
+		assert.True(t, matchHeaderRule(database.MockRule{Type: "header", Key: "Authorization", Operator: "regex", Value: bearer}, req))
+
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+		assert.False(t, matchHeaderRule(database.MockRule{Type: "header", Key: "Authorization", Operator: "regex", Value: bearer}, req))
+	})
+
+	t.Run("Query rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders?id=ORD-2024-001", nil)
+		assert.True(t, matchQueryRule(database.MockRule{Type: "query", Key: "id", Operator: "regex", Value: `^ORD-\d{4}-\d+$`}, req))
+	})
+
+	t.Run("Body rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"user":{"email":"ada@example.com"}}`))
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "user.email", Operator: "regex", Value: `@example\.com$`}, req))
+	})
+
+	t.Run("Invalid pattern fails the match", func(t *testing.T) {
+		assert.False(t, matchRuleValue("regex", "anything", "([a-z"))
+		assert.False(t, matchRuleValue("regex", "anything", "([a-z"), "cached invalid patterns keep failing")
+	})
+
+	t.Run("Patterns are compiled once", func(t *testing.T) {
+		first := ruleRegex(`^v\d+$`)
+		require.NotNil(t, first)
+		assert.Same(t, first, ruleRegex(`^v\d+$`))
+	})
+}
+
+func TestMatchRuleValue_Negation(t *testing.T) {
+	t.Run("Header present with a different value", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", "beta")
+
+		assert.True(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Tenant", Operator: "not_equals", Value: "alpha"}, req))
+		assert.False(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Tenant", Operator: "not_equals", Value: "beta"}, req))
+	})
+
+	t.Run("Missing header compares as empty", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.True(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Tenant", Operator: "not_equals", Value: "alpha"}, req))
+		assert.True(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Tenant", Operator: "not_contains", Value: "alp"}, req))
+		assert.False(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Tenant", Operator: "not_equals", Value: ""}, req))
+	})
+
+	t.Run("Empty actual value", func(t *testing.T) {
+		assert.True(t, matchRuleValue("not_equals", "", "x"))
+		assert.False(t, matchRuleValue("not_contains", "", ""), "every string contains the empty string")
+	})
+
+	t.Run("Query rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?status=active", nil)
+
+		assert.True(t, matchQueryRule(database.MockRule{Type: "query", Key: "status", Operator: "not_contains", Value: "deleted"}, req))
+		assert.False(t, matchQueryRule(database.MockRule{Type: "query", Key: "status", Operator: "not_contains", Value: "act"}, req))
+	})
+
+	t.Run("Body field", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"order":{"note":"leave at door"}}`))
+
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "order.note", Operator: "not_contains", Value: "fragile"}, req))
+		assert.False(t, matchBodyRule(database.MockRule{Type: "body", Key: "order.note", Operator: "not_contains", Value: "door"}, req))
+	})
+
+	t.Run("Unknown operators still fall back to equals", func(t *testing.T) {
+		assert.True(t, matchRuleValue("unknown", "a", "a"))
+		assert.False(t, matchRuleValue("unknown", "a", "b"))
+	})
+}
+
+func TestMatchRuleValue_Numeric(t *testing.T) {
+	t.Run("Operators", func(t *testing.T) {
+		assert.True(t, matchRuleValue("gt", "101", "100"))
+		assert.False(t, matchRuleValue("gt", "100", "100"))
+		assert.True(t, matchRuleValue("gte", "100", "100"))
+		assert.True(t, matchRuleValue("lt", "2.5", "10"), "compared as numbers, not strings")
+		assert.True(t, matchRuleValue("lte", "-1", "-1"))
+		assert.False(t, matchRuleValue("lte", "1e3", "999"))
+	})
+
+	t.Run("Non-numeric values never match", func(t *testing.T) {
+		assert.False(t, matchRuleValue("gt", "abc", "1"))
+		assert.False(t, matchRuleValue("lt", "1", "abc"))
+		assert.False(t, matchRuleValue("gte", "", "0"))
+	})
+
+	t.Run("Query rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders?qty=150", nil)
+		assert.True(t, matchQueryRule(database.MockRule{Type: "query", Key: "qty", Operator: "gt", Value: "100"}, req))
+		assert.False(t, matchQueryRule(database.MockRule{Type: "query", Key: "qty", Operator: "lte", Value: "100"}, req))
+	})
+
+	t.Run("Header rule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Api-Version", "3")
+		assert.True(t, matchHeaderRule(database.MockRule{Type: "header", Key: "X-Api-Version", Operator: "gte", Value: "2"}, req))
+	})
+
+	t.Run("JSON body field", func(t *testing.T) {
+		body := `{"order":{"total":99.5}}`
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "order.total", Operator: "lt", Value: "100"}, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))))
+		assert.False(t, matchBodyRule(database.MockRule{Type: "body", Key: "order.missing", Operator: "lt", Value: "100"}, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))))
+	})
+}
+
+func TestMatchRuleValue_Presence(t *testing.T) {
+	t.Run("Header", func(t *testing.T) {
+		exists := database.MockRule{Type: "header", Key: "x-debug", Operator: "exists", Value: "ignored"}
+		notExists := database.MockRule{Type: "header", Key: "x-debug", Operator: "not_exists"}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		assert.False(t, matchHeaderRule(exists, req))
+		assert.True(t, matchHeaderRule(notExists, req))
+
+		req.Header.Set("X-Debug", "")
+		assert.True(t, matchHeaderRule(exists, req), "an empty header is still present")
+		assert.False(t, matchHeaderRule(notExists, req))
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		exists := database.MockRule{Type: "query", Key: "debug", Operator: "exists"}
+		notExists := database.MockRule{Type: "query", Key: "debug", Operator: "not_exists"}
+
+		assert.True(t, matchQueryRule(exists, httptest.NewRequest(http.MethodGet, "/?debug", nil)))
+		assert.True(t, matchQueryRule(exists, httptest.NewRequest(http.MethodGet, "/?debug=", nil)))
+		assert.False(t, matchQueryRule(exists, httptest.NewRequest(http.MethodGet, "/?other=1", nil)))
+		assert.True(t, matchQueryRule(notExists, httptest.NewRequest(http.MethodGet, "/", nil)))
+		assert.False(t, matchQueryRule(notExists, httptest.NewRequest(http.MethodGet, "/?debug=1", nil)))
+	})
+}
+
+func TestSelectResponseWithEndpoint_Fallback(t *testing.T) {
+	never := func(value string) []database.MockRule {
+		return []database.MockRule{{Type: "header", Key: "X-Never", Operator: "equals", Value: value}}
+	}
+	responses := []database.MockResponse{
+		{ID: "first", Body: "first", Rules: never("1")},
+		{ID: "second", Body: "second", Rules: never("2")},
+		{ID: "fallback", Body: "fallback", Rules: never("3"), IsFallback: true},
+		{ID: "last", Body: "last", Rules: never("4")},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+
+	selected := selectResponseWithEndpoint("endpoint-fallback", responses, "static", req)
+	require.NotNil(t, selected)
+	assert.Equal(t, "fallback", selected.ID)
+	assert.Equal(t, "fallback", selected.Body)
+}
+
+func TestMatchCookieRule(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "admin-42"})
+	req.AddCookie(&http.Cookie{Name: "empty", Value: ""})
+	// A header value holding the cookie text must not match it
+	req.Header.Set("X-Note", "session=user-1")
+
+	rule := func(key, operator, value string) database.MockRule {
+		return database.MockRule{Type: "cookie", Key: key, Operator: operator, Value: value}
+	}
+
+	assert.True(t, matchCookieRule(rule("session", "equals", "admin-42"), req))
+	assert.True(t, matchCookieRule(rule("session", "regex", "^admin-"), req))
+	assert.False(t, matchCookieRule(rule("session", "equals", "user-1"), req))
+	assert.False(t, matchCookieRule(rule("sess", "contains", "admin"), req), "the cookie name must match exactly")
+
+	assert.True(t, matchCookieRule(rule("empty", "exists", ""), req))
+	assert.True(t, matchCookieRule(rule("missing", "not_exists", ""), req))
+	assert.False(t, matchCookieRule(rule("session", "not_exists", ""), req))
+	assert.True(t, matchCookieRule(rule("missing", "not_equals", "admin-42"), req))
+
+	response := database.MockResponse{Rules: []database.MockRule{rule("session", "equals", "admin-42")}}
+	assert.True(t, matchesRules(response, req))
+	assert.False(t, matchesRules(response, httptest.NewRequest(http.MethodGet, "/", nil)))
+}
+
+func TestMatchMethodRule(t *testing.T) {
+	rule := func(operator, value string) database.MockRule {
+		return database.MockRule{Type: "method", Operator: operator, Value: value}
+	}
+	get := httptest.NewRequest(http.MethodGet, "/", nil)
+	post := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	assert.True(t, matchMethodRule(rule("equals", "GET"), get))
+	assert.True(t, matchMethodRule(rule("equals", "get"), get), "methods compare case-insensitively")
+	assert.False(t, matchMethodRule(rule("equals", "GET"), post))
+	assert.True(t, matchMethodRule(rule("not_equals", "GET"), post))
+	assert.True(t, matchMethodRule(rule("regex", "^(POST|PUT)$"), post))
+	assert.False(t, matchMethodRule(rule("regex", "^(POST|PUT)$"), get))
+
+	response := database.MockResponse{Rules: []database.MockRule{rule("equals", "POST")}}
+	assert.True(t, matchesRules(response, post))
+	assert.False(t, matchesRules(response, get))
+}
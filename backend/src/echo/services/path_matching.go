@@ -0,0 +1,42 @@
+package services
+
+import (
+	"strings"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/repositories"
+)
+
+// pathMatchOptionsFor returns how the project compares request paths with endpoint paths:
+// strict unless caseInsensitivePaths is set
+func pathMatchOptionsFor(project *database.Project) repositories.PathMatchOptions {
+	if project == nil || project.AdvanceConfig == "" {
+		return repositories.PathMatchOptions{}
+	}
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil {
+		return repositories.PathMatchOptions{}
+	}
+	return repositories.PathMatchOptions{CaseInsensitive: config.CaseInsensitivePaths}
+}
+
+// normalizeRequestPath collapses repeated slashes and drops a trailing slash, so /users//1/
+// becomes /users/1. The root path stays "/".
+func normalizeRequestPath(path string) string {
+	if path == "" {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return "/" + strings.Join(kept, "/")
+}
+
+// findEndpoint finds the endpoint matching the request with the project's path matching options
+func (s *MockService) findEndpoint(project *database.Project, method, path string) (*database.MockEndpoint, error) {
+	return s.Repo.FindMatchingEndpointWithOptions(project.ID, method, path, pathMatchOptionsFor(project))
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestNormalizeRequestPath(t *testing.T) {
+	assert.Equal(t, "/users/1", normalizeRequestPath("/users//1/"))
+	assert.Equal(t, "/users", normalizeRequestPath("/users/"))
+	assert.Equal(t, "/", normalizeRequestPath("//"))
+	assert.Equal(t, "", normalizeRequestPath(""))
+}
+
+func TestHandleRequest_PathMatchingOptions(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "path-matching")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/users/:id")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"id":"{{.Path.id}}"}`,
+		AdvanceConfig: `{"templated": true}`,
+	})
+
+	get := func(path string) (*http.Response, bool) {
+		req := httptest.NewRequest(http.MethodGet, "/path-matching"+path, nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, path, req)
+		require.NoError(t, err)
+		return resp, matched
+	}
+
+	t.Run("Strict by default", func(t *testing.T) {
+		_, matched := get("/Users/42")
+		assert.False(t, matched)
+		_, matched = get("/users//42")
+		assert.False(t, matched)
+	})
+
+	project.AdvanceConfig = `{"caseInsensitivePaths": true, "normalizePaths": true}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	t.Run("Case and slashes relaxed", func(t *testing.T) {
+		for _, path := range []string{"/Users/42/", "/USERS//42", "/users/42"} {
+			resp, matched := get(path)
+			require.True(t, matched, path)
+			body, _ := io.ReadAll(resp.Body)
+			assert.JSONEq(t, `{"id":"42"}`, string(body), path)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/repositories"
+)
+
+// pathParamsKey stores the path parameters captured by the matched endpoint in the request context
+type pathParamsKey struct{}
+
+// withPathParams returns a request carrying the values the endpoint's :name / {name} segments
+// captured from path, e.g. {"id": "42"} for /users/:id and /users/42
+func withPathParams(req *http.Request, endpoint *database.MockEndpoint, path string, opts repositories.PathMatchOptions) *http.Request {
+	if req == nil || endpoint == nil {
+		return req
+	}
+	params := repositories.ExtractPathParams(endpoint.Path, path, opts)
+	return req.WithContext(context.WithValue(req.Context(), pathParamsKey{}, params))
+}
+
+// pathParams returns the captured path parameters, or extracts them from the endpoint when the
+// request was not matched through withPathParams
+func pathParams(req *http.Request, endpoint *database.MockEndpoint) map[string]string {
+	if params, ok := req.Context().Value(pathParamsKey{}).(map[string]string); ok {
+		return params
+	}
+	if endpoint == nil {
+		return map[string]string{}
+	}
+	return repositories.ExtractPathParams(endpoint.Path, requestPath(req), repositories.PathMatchOptions{})
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DecodedBody is a proxied response body that also carries its decoded form. The client still
+// receives the upstream bytes; the decoded bytes are only used for request logs.
+type DecodedBody interface {
+	io.ReadCloser
+	DecodedBytes() []byte
+	DecodedTruncated() bool // DecodedBytes stops at the decode limit, see decodedBodyLimit
+}
+
+// decodedProxyBody replays the upstream bytes and keeps their decoding
+type decodedProxyBody struct {
+	*bytes.Reader
+	decoded   []byte
+	truncated bool
+}
+
+func (b *decodedProxyBody) Close() error { return nil }
+
+// DecodedBytes returns the body with every Content-Encoding removed
+func (b *decodedProxyBody) DecodedBytes() []byte { return b.decoded }
+
+// DecodedTruncated reports whether the decoded body was cut at the decode limit
+func (b *decodedProxyBody) DecodedTruncated() bool { return b.truncated }
+
+// decodedBodyLimit bounds the decoded form of an upstream body at PROXY_BUFFER_MAX_BYTES, or at
+// its default when every body is buffered, so a small compressed body can't expand without bound
+func decodedBodyLimit(bufferLimit int64) int64 {
+	if bufferLimit > 0 {
+		return bufferLimit
+	}
+	return defaultProxyBufferLimit
+}
+
+// decodeProxyResponse reads an upstream body encoded with gzip, br, deflate or zstd (in any
+// combination) and replaces it with a DecodedBody holding at most limit decoded bytes.
+// Responses without a Content-Encoding, with an unknown one, or whose body fails to decode are
+// left for the log to capture as sent.
+func decodeProxyResponse(resp *http.Response, limit int64) {
+	if resp == nil || resp.Body == nil || isProxyStream(resp) {
+		return
+	}
+	encodings := parseContentEncodings(resp.Header.Get("Content-Encoding"))
+	if len(encodings) == 0 {
+		return
+	}
+	for _, encoding := range encodings {
+		if !isDecodableEncoding(encoding) {
+			return
+		}
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(raw))
+		return
+	}
+
+	// Encodings are listed in the order they were applied, so they are removed last first
+	decoded, truncated := raw, false
+	for i := len(encodings) - 1; i >= 0; i-- {
+		if decoded, truncated, err = decodeContentEncoding(encodings[i], decoded, limit, truncated); err != nil {
+			fmt.Println("Error decoding upstream response body:", err)
+			resp.Body = io.NopCloser(bytes.NewReader(raw))
+			return
+		}
+	}
+	resp.Body = &decodedProxyBody{Reader: bytes.NewReader(raw), decoded: decoded, truncated: truncated}
+}
+
+// isDecodableEncoding reports whether decodeContentEncoding understands the encoding
+func isDecodableEncoding(encoding string) bool {
+	switch encoding {
+	case "gzip", "x-gzip", "br", "deflate", "zstd":
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeContentEncoding removes one content coding, keeping at most limit decoded bytes.
+// truncated reports whether output was cut off, either at the limit or because data is itself
+// a truncated layer, whose decoding then ends early. deflate is accepted both zlib-wrapped, as
+// the spec requires, and raw, as some servers send it.
+func decodeContentEncoding(encoding string, data []byte, limit int64, partial bool) (decoded []byte, truncated bool, err error) {
+	var reader io.Reader
+	switch encoding {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, err
+		}
+		defer gz.Close()
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(data))
+	case "deflate":
+		if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+			defer zr.Close()
+			reader = zr
+		} else {
+			fr := flate.NewReader(bytes.NewReader(data))
+			defer fr.Close()
+			reader = fr
+		}
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, false, err
+		}
+		defer zr.Close()
+		reader = zr
+	default:
+		return nil, false, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	decoded, err = io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil && !(partial && errors.Is(err, io.ErrUnexpectedEOF)) {
+		return nil, false, err
+	}
+	if int64(len(decoded)) > limit {
+		return decoded[:limit], true, nil
+	}
+	return decoded, partial, nil
+}
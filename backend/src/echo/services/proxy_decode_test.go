@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeProxyResponse(t *testing.T) {
+	plain := []byte(`{"message":"hello from upstream"}`)
+
+	var brBuf bytes.Buffer
+	br := brotli.NewWriter(&brBuf)
+	br.Write(plain)
+	br.Close()
+
+	var rawDeflate bytes.Buffer
+	fw, _ := flate.NewWriter(&rawDeflate, flate.DefaultCompression)
+	fw.Write(plain)
+	fw.Close()
+
+	var zstdBuf bytes.Buffer
+	zw, _ := zstd.NewWriter(&zstdBuf)
+	zw.Write(plain)
+	zw.Close()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"gzip", "gzip", gzipBytes(t, plain)},
+		{"brotli", "br", brBuf.Bytes()},
+		{"raw deflate", "deflate", rawDeflate.Bytes()},
+		{"zstd", "zstd", zstdBuf.Bytes()},
+		{"gzip applied twice", "gzip, gzip", gzipBytes(t, gzipBytes(t, plain))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Content-Encoding": {tt.encoding}}, Body: io.NopCloser(bytes.NewReader(tt.body))}
+			decodeProxyResponse(resp, defaultProxyBufferLimit)
+
+			decoded, ok := resp.Body.(DecodedBody)
+			require.True(t, ok)
+			assert.Equal(t, plain, decoded.DecodedBytes())
+			assert.False(t, decoded.DecodedTruncated())
+
+			// The client still receives the bytes the upstream sent
+			sent, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, sent)
+		})
+	}
+
+	t.Run("Decoding stops at the limit", func(t *testing.T) {
+		bomb := bytes.Repeat([]byte("0"), 8<<20) // 8MB compressing to a few KB
+		for name, body := range map[string][]byte{"gzip": gzipBytes(t, bomb), "gzip, gzip": gzipBytes(t, gzipBytes(t, bomb))} {
+			resp := &http.Response{Header: http.Header{"Content-Encoding": {name}}, Body: io.NopCloser(bytes.NewReader(body))}
+			decodeProxyResponse(resp, 1024)
+
+			decoded, ok := resp.Body.(DecodedBody)
+			require.True(t, ok, name)
+			assert.Equal(t, bomb[:1024], decoded.DecodedBytes(), name)
+			assert.True(t, decoded.DecodedTruncated(), name)
+			_, ok = readRecordedBody(resp)
+			assert.False(t, ok, "truncated bodies aren't recorded")
+			sent, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, body, sent, "the client still gets the whole body")
+		}
+	})
+
+	t.Run("Undecodable bodies are left as sent", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}, Body: io.NopCloser(bytes.NewReader([]byte("not gzip")))}
+		decodeProxyResponse(resp, defaultProxyBufferLimit)
+
+		_, ok := resp.Body.(DecodedBody)
+		assert.False(t, ok)
+		sent, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "not gzip", string(sent))
+	})
+}
+
+func TestHandleForwarderMode_ProxyDecodeResponse(t *testing.T) {
+	plain := []byte(`{"id":1}`)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipBytes(t, plain))
+	}))
+	defer upstream.Close()
+
+	forward := func(advanceConfig string) *http.Response {
+		project := &database.Project{ActiveProxy: &database.ProxyTarget{URL: upstream.URL}, AdvanceConfig: advanceConfig}
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users/1", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Off by default", func(t *testing.T) {
+		resp := forward("")
+		defer resp.Body.Close()
+		_, ok := resp.Body.(DecodedBody)
+		assert.False(t, ok)
+	})
+
+	t.Run("Opted in", func(t *testing.T) {
+		resp := forward(`{"proxyDecodeResponse": true}`)
+		defer resp.Body.Close()
+
+		decoded, ok := resp.Body.(DecodedBody)
+		require.True(t, ok)
+		assert.Equal(t, plain, decoded.DecodedBytes())
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	})
+}
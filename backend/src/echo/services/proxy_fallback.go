@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"beo-echo/backend/src/database"
+)
+
+// respondWithProxyFallback returns the project's fallback mock when the upstream status is one
+// of proxyFallback's statusCodes, or nil to keep the upstream response. The fallback endpoint's
+// responses are tried first, then the fixed fallback response.
+func (s *MockService) respondWithProxyFallback(ctx context.Context, project *database.Project, path string, req *http.Request, upstream *http.Response) *http.Response {
+	if project.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil || config.ProxyFallback == nil || !slices.Contains(config.ProxyFallback.StatusCodes, upstream.StatusCode) {
+		return nil
+	}
+	fallback := config.ProxyFallback
+
+	var resp *http.Response
+	if fallback.EndpointID != "" {
+		if endpoint, err := s.Repo.FindProjectEndpoint(project.ID, fallback.EndpointID); err == nil && endpoint.Enabled {
+			resp = s.respondWithEndpointMock(ctx, project, endpoint, withPathParams(req, endpoint, path, pathMatchOptionsFor(project)))
+		}
+	}
+	if resp == nil && fallback.Response != nil {
+		resp = createCustomResponse(fallback.Response, upstream.StatusCode, "Upstream failed")
+	}
+	if resp == nil {
+		return nil
+	}
+
+	if upstream.Body != nil {
+		upstream.Body.Close()
+	}
+	resp.Header.Set("beo-echo-response-type", "fallback")
+	resp.Header.Set("beo-echo-upstream-status", strconv.Itoa(upstream.StatusCode))
+	return resp
+}
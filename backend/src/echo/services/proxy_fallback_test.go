@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleProxyMode_Fallback(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "proxy-fallback")
+
+	upstreamStatus := http.StatusInternalServerError
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(upstreamStatus)
+		w.Write([]byte("upstream"))
+	}))
+	defer upstream.Close()
+
+	fallbackEndpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/fallback/orders")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(fallbackEndpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, fallbackEndpoint.ID, database.MockResponse{StatusCode: 200, Body: `{"orders": []}`})
+
+	project.Mode = database.ModeProxy
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+
+	forward := func(advanceConfig string) (*http.Response, bool, string) {
+		project.AdvanceConfig = advanceConfig
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		resp, matched, err := service.handleProxyMode(context.Background(), project, http.MethodGet, "/orders", req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, matched, string(body)
+	}
+
+	t.Run("Listed upstream statuses are answered by the fallback endpoint", func(t *testing.T) {
+		resp, matched, body := forward(`{"proxyFallback": {"statusCodes": [500, 502], "endpointId": "` + fallbackEndpoint.ID + `"}}`)
+
+		assert.True(t, matched)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"orders": []}`, body)
+		assert.Equal(t, "fallback", resp.Header.Get("beo-echo-response-type"))
+		assert.Equal(t, "500", resp.Header.Get("beo-echo-upstream-status"))
+	})
+
+	t.Run("Fixed response keeps the upstream status by default", func(t *testing.T) {
+		resp, _, body := forward(`{"proxyFallback": {"statusCodes": [500], "response": {"body": "degraded"}}}`)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "degraded", body)
+	})
+
+	t.Run("Other statuses are proxied", func(t *testing.T) {
+		upstreamStatus = http.StatusNotFound
+		defer func() { upstreamStatus = http.StatusInternalServerError }()
+
+		resp, matched, body := forward(`{"proxyFallback": {"statusCodes": [500], "endpointId": "` + fallbackEndpoint.ID + `"}}`)
+
+		assert.False(t, matched)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "upstream", body)
+		assert.Equal(t, "proxy", resp.Header.Get("beo-echo-response-type"))
+	})
+
+	t.Run("Connection failures count as 502", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		project.ActiveProxy = &database.ProxyTarget{URL: closed.URL}
+		defer func() { project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL} }()
+
+		resp, _, body := forward(`{"proxyFallback": {"statusCodes": [502], "response": {"statusCode": 200, "body": "cached"}}}`)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "cached", body)
+		assert.Equal(t, "502", resp.Header.Get("beo-echo-upstream-status"))
+	})
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// loopDetectHeader marks requests forwarded upstream, so one that comes back is a proxy loop
+const loopDetectHeader = "beo-echo-loop-detect"
+
+// isProxyLoop reports whether the request was forwarded by a beo-echo proxy
+func isProxyLoop(req *http.Request) bool {
+	return req.Header.Get(loopDetectHeader) != ""
+}
+
+// isControlHeader reports whether a request header is a beo-echo-* one the client sends to steer
+// the mock, e.g. beo-echo-scenario; those are never forwarded upstream
+func isControlHeader(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), "beo-echo")
+}
+
+// applyProxyResponseHeaders filters upstream response headers according to the project's
+// proxyResponseHeaders config, then edits them with proxyResponseRewrite. beo-echo-* headers
+// are always kept by the filter since they're added by us.
+func applyProxyResponseHeaders(project *database.Project, resp *http.Response) {
+	if project == nil || project.AdvanceConfig == "" || resp == nil || resp.Header == nil {
+		return
+	}
+
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil {
+		return
+	}
+
+	if config.ProxyResponseHeaders != nil {
+		filterHeaders(resp.Header, config.ProxyResponseHeaders)
+	}
+	if config.ProxyResponseRewrite != nil {
+		rewriteHeaders(resp.Header, config.ProxyResponseRewrite)
+	}
+}
+
+// filterHeaders applies an allow list (when set) and then a deny list to the headers in place
+func filterHeaders(headers http.Header, filter *database.HeaderFilter) {
+	if len(filter.Allow) > 0 {
+		allowed := make(map[string]bool, len(filter.Allow))
+		for _, name := range filter.Allow {
+			allowed[http.CanonicalHeaderKey(name)] = true
+		}
+		for name := range headers {
+			if !allowed[http.CanonicalHeaderKey(name)] && !strings.HasPrefix(strings.ToLower(name), "beo-echo") {
+				delete(headers, name)
+			}
+		}
+	}
+
+	for _, name := range filter.Deny {
+		headers.Del(name)
+	}
+}
+
+// rewriteHeaders removes the rewrite's headers, replaces text in values, then sets its values, in place
+func rewriteHeaders(headers http.Header, rewrite *database.HeaderRewrite) {
+	for _, name := range rewrite.Remove {
+		headers.Del(name)
+	}
+	for _, replace := range rewrite.Replace {
+		key := http.CanonicalHeaderKey(replace.Header)
+		for i, value := range headers[key] {
+			headers[key][i] = strings.ReplaceAll(value, replace.Find, replace.Replace)
+		}
+	}
+	for name, value := range rewrite.Set {
+		headers.Set(name, value)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestFilterHeaders(t *testing.T) {
+	t.Run("Deny list removes headers", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set("Server", "nginx")
+		headers.Set("X-Powered-By", "PHP")
+		headers.Set("Content-Type", "application/json")
+
+		filterHeaders(headers, &database.HeaderFilter{Deny: []string{"server", "x-powered-by"}})
+
+		assert.Empty(t, headers.Get("Server"))
+		assert.Empty(t, headers.Get("X-Powered-By"))
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	})
+
+	t.Run("Allow list keeps only listed and beo-echo headers", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set("Server", "nginx")
+		headers.Set("Content-Type", "application/json")
+		headers.Set("beo-echo-latency-ms", "12")
+
+		filterHeaders(headers, &database.HeaderFilter{Allow: []string{"content-type"}})
+
+		assert.Empty(t, headers.Get("Server"))
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+		assert.Equal(t, "12", headers.Get("beo-echo-latency-ms"))
+	})
+}
+
+func TestHandleForwarderMode_ProxyResponseHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "legacy-server")
+		w.Header().Set("X-Powered-By", "Express")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	project := &database.Project{
+		Mode:          database.ModeForwarder,
+		ActiveProxy:   &database.ProxyTarget{URL: upstream.URL},
+		AdvanceConfig: `{"proxyResponseHeaders": {"deny": ["Server", "X-Powered-By"]}}`,
+	}
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Server"))
+	assert.Empty(t, resp.Header.Get("X-Powered-By"))
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.NotEmpty(t, resp.Header.Get("beo-echo-latency-ms"))
+}
+
+func TestExecuteProxyRequest_RequestHeaders(t *testing.T) {
+	var received http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	project := &database.Project{AdvanceConfig: `{"proxyRequestHeaders": {"set": {"Authorization": "Bearer project-token", "X-Tenant": "acme"}, "remove": ["X-Client-Debug"]}}`}
+	endpoint := &database.MockEndpoint{AdvanceConfig: `{"proxyRequestHeaders": {"set": {"X-Tenant": "endpoint"}, "remove": ["beo-echo-loop-detect"]}}`}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Authorization", "Bearer client-token")
+	req.Header.Set("X-Client-Debug", "1")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := executeProxyRequest(context.Background(), upstream.URL, http.MethodGet, "/users", "", req, proxyOptionsForEndpoint(project, endpoint))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "Bearer project-token", received.Get("Authorization"))
+	assert.Equal(t, "endpoint", received.Get("X-Tenant"), "endpoint edits apply after the project's")
+	assert.Empty(t, received.Get("X-Client-Debug"))
+	assert.Equal(t, "application/json", received.Get("Accept"))
+	assert.Equal(t, "true", received.Get("beo-echo-loop-detect"), "loop detection can't be removed")
+}
+
+func TestHandleForwarderMode_ProxyResponseRewrite(t *testing.T) {
+	var upstream *httptest.Server
+	upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", upstream.URL+"/login?next=/users")
+		w.Header().Add("Set-Cookie", "session=abc; Domain=api.upstream.example; Path=/")
+		w.Header().Add("Set-Cookie", "theme=dark; Domain=api.upstream.example")
+		w.Header().Set("X-Upstream-Trace", "1234")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer upstream.Close()
+
+	project := &database.Project{
+		Mode:        database.ModeForwarder,
+		ActiveProxy: &database.ProxyTarget{URL: upstream.URL},
+		AdvanceConfig: `{"proxyResponseRewrite": {
+			"replace": [
+				{"header": "Location", "find": "` + upstream.URL + `", "replace": "http://localhost:8000/shop"},
+				{"header": "set-cookie", "find": "; Domain=api.upstream.example", "replace": ""}
+			],
+			"remove": ["X-Upstream-Trace"],
+			"set": {"X-Rewritten": "true"}
+		}}`,
+	}
+	req := httptest.NewRequest(http.MethodGet, "/account", nil)
+
+	resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/account", req)
+
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "http://localhost:8000/shop/login?next=/users", resp.Header.Get("Location"))
+	assert.Equal(t, []string{"session=abc; Path=/", "theme=dark"}, resp.Header.Values("Set-Cookie"))
+	assert.Empty(t, resp.Header.Get("X-Upstream-Trace"))
+	assert.Equal(t, "true", resp.Header.Get("X-Rewritten"))
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// defaultProxyTimeout bounds an upstream request, body included, when no proxyTimeoutMs is set
+const defaultProxyTimeout = 30 * time.Second
+
+// proxyOptions carries the per-project settings applied when forwarding a request upstream
+type proxyOptions struct {
+	hostHeader     string        // Host sent upstream: "target" (or empty), "original" or a literal host
+	decodeResponse bool          // Decode the upstream body for request logs (see decodeProxyResponse)
+	timeout        time.Duration // Whole-request upstream timeout; zero uses defaultProxyTimeout
+	tlsVerify      bool          // Verify upstream certificates (see proxyTLSConfig)
+	httpVersion    string        // Upstream protocol, a database.ProxyHTTP* value (see proxyTransport)
+	retry          proxyRetryPolicy
+	requestHeaders []*database.HeaderRewrite // Applied in order to the forwarded request headers
+	projectID      string
+	circuitConfig  *database.CircuitBreaker
+	circuit        *circuitBreaker // Breaker of the target, set by forTarget when circuitConfig is set
+}
+
+// proxyOptionsFor builds the forwarding options from the system config and the project's advance config
+func proxyOptionsFor(project *database.Project) proxyOptions {
+	opts := proxyOptions{tlsVerify: systemProxyTLSVerify()}
+	if project == nil || project.AdvanceConfig == "" {
+		return opts
+	}
+	opts.projectID = project.ID
+
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err != nil {
+		return opts
+	}
+	opts.hostHeader = config.ProxyHostHeader
+	opts.decodeResponse = config.ProxyDecodeResponse
+	opts.timeout = time.Duration(config.ProxyTimeoutMs) * time.Millisecond
+	if config.ProxyTLSVerify != nil {
+		opts.tlsVerify = *config.ProxyTLSVerify
+	}
+	opts.httpVersion = config.ProxyHTTPVersion
+	opts.retry = newProxyRetryPolicy(config.ProxyRetry)
+	opts.circuitConfig = config.ProxyCircuitBreaker
+	if config.ProxyRequestHeaders != nil {
+		opts.requestHeaders = append(opts.requestHeaders, config.ProxyRequestHeaders)
+	}
+	return opts
+}
+
+// proxyOptionsForEndpoint applies an endpoint's overrides on top of the project's options
+func proxyOptionsForEndpoint(project *database.Project, endpoint *database.MockEndpoint) proxyOptions {
+	opts := proxyOptionsFor(project)
+	if endpoint == nil || endpoint.AdvanceConfig == "" {
+		return opts
+	}
+
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil {
+		return opts
+	}
+	if config.ProxyTimeoutMs > 0 {
+		opts.timeout = time.Duration(config.ProxyTimeoutMs) * time.Millisecond
+	}
+	if config.ProxyRequestHeaders != nil {
+		opts.requestHeaders = append(opts.requestHeaders, config.ProxyRequestHeaders)
+	}
+	return opts
+}
+
+// forTarget applies the proxy target's Host header setting, which wins over the project's,
+// and picks the target's circuit breaker
+func (o proxyOptions) forTarget(target *database.ProxyTarget) proxyOptions {
+	if target != nil && target.HostHeader != "" {
+		o.hostHeader = target.HostHeader
+	}
+	if target != nil && o.circuitConfig != nil {
+		o.circuit = circuitBreakerFor(o.projectID, target)
+	}
+	return o
+}
+
+// upstreamHost returns the Host sent upstream for the hostHeader setting
+func (o proxyOptions) upstreamHost(targetURL *url.URL, req *http.Request) string {
+	switch o.hostHeader {
+	case "", database.ProxyHostTarget:
+		return targetURL.Host
+	case database.ProxyHostOriginal:
+		return req.Host
+	default:
+		return o.hostHeader
+	}
+}
+
+// upstreamTimeout returns the configured timeout or the default
+func (o proxyOptions) upstreamTimeout() time.Duration {
+	if o.timeout > 0 {
+		return o.timeout
+	}
+	return defaultProxyTimeout
+}
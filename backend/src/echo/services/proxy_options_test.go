@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleForwarderMode_ProxyHostHeader(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer upstream.Close()
+
+	forward := func(project *database.Project) string {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	t.Run("Defaults to the target host", func(t *testing.T) {
+		project := &database.Project{ActiveProxy: &database.ProxyTarget{URL: upstream.URL}}
+		assert.Equal(t, upstream.Listener.Addr().String(), forward(project))
+	})
+
+	t.Run("Project override is forwarded", func(t *testing.T) {
+		project := &database.Project{
+			ActiveProxy:   &database.ProxyTarget{URL: upstream.URL},
+			AdvanceConfig: `{"proxyHostHeader": "api.internal.example"}`,
+		}
+		assert.Equal(t, "api.internal.example", forward(project))
+	})
+
+	t.Run("Original keeps the client Host", func(t *testing.T) {
+		project := &database.Project{
+			ActiveProxy:   &database.ProxyTarget{URL: upstream.URL},
+			AdvanceConfig: `{"proxyHostHeader": "original"}`,
+		}
+		assert.Equal(t, "example.com", forward(project))
+	})
+
+	t.Run("Proxy target setting wins over the project", func(t *testing.T) {
+		project := &database.Project{
+			ActiveProxy:   &database.ProxyTarget{URL: upstream.URL, HostHeader: database.ProxyHostTarget},
+			AdvanceConfig: `{"proxyHostHeader": "api.internal.example"}`,
+		}
+		assert.Equal(t, upstream.Listener.Addr().String(), forward(project))
+
+		project.ActiveProxy.HostHeader = "tenant-a.example"
+		assert.Equal(t, "tenant-a.example", forward(project))
+	})
+}
+
+func TestExecuteProxyRequest_Timeout(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-body" {
+			// Headers arrive in time, the body doesn't
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte("late"))
+	}))
+	defer upstream.Close()
+
+	forward := func(path string, opts proxyOptions) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp, err := executeProxyRequest(context.Background(), upstream.URL, http.MethodGet, path, "", req, opts)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Timeout before the headers answers 504", func(t *testing.T) {
+		resp := forward("/slow", proxyOptions{timeout: 50 * time.Millisecond})
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	})
+
+	t.Run("Timeout covers the body transfer", func(t *testing.T) {
+		resp := forward("/slow-body", proxyOptions{timeout: 50 * time.Millisecond})
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	})
+
+	t.Run("Default timeout lets slow upstreams finish", func(t *testing.T) {
+		resp := forward("/slow", proxyOptions{})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "late", string(body))
+	})
+
+	t.Run("Connection failures stay 502", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp, err := executeProxyRequest(context.Background(), closed.URL, http.MethodGet, "/", "", req, proxyOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+}
+
+func TestProxyOptionsForEndpoint_Timeout(t *testing.T) {
+	project := &database.Project{AdvanceConfig: `{"proxyTimeoutMs": 2000}`}
+
+	assert.Equal(t, 2*time.Second, proxyOptionsForEndpoint(project, &database.MockEndpoint{}).upstreamTimeout())
+	assert.Equal(t, 500*time.Millisecond, proxyOptionsForEndpoint(project, &database.MockEndpoint{AdvanceConfig: `{"proxyTimeoutMs": 500}`}).upstreamTimeout())
+	assert.Equal(t, defaultProxyTimeout, proxyOptionsForEndpoint(nil, nil).upstreamTimeout())
+}
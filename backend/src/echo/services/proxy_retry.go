@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// defaultProxyRetryBackoff is the wait before the first retry when backoffMs is unset
+const defaultProxyRetryBackoff = 100 * time.Millisecond
+
+// proxyRetryPolicy is the retry behaviour of a project's proxied requests; the zero value sends once
+type proxyRetryPolicy struct {
+	maxAttempts   int
+	backoff       time.Duration
+	nonIdempotent bool
+}
+
+// newProxyRetryPolicy converts the project's proxyRetry config
+func newProxyRetryPolicy(config *database.ProxyRetryConfig) proxyRetryPolicy {
+	if config == nil {
+		return proxyRetryPolicy{}
+	}
+	policy := proxyRetryPolicy{
+		maxAttempts:   config.MaxAttempts,
+		backoff:       time.Duration(config.BackoffMs) * time.Millisecond,
+		nonIdempotent: config.RetryNonIdempotent,
+	}
+	if policy.backoff == 0 {
+		policy.backoff = defaultProxyRetryBackoff
+	}
+	return policy
+}
+
+// enabled reports whether requests may be sent more than once
+func (p proxyRetryPolicy) enabled() bool {
+	return p.maxAttempts > 1
+}
+
+// attemptsFor returns how many times a request with the method may be sent
+func (p proxyRetryPolicy) attemptsFor(method string) int {
+	if !p.enabled() || (!p.nonIdempotent && !isIdempotentMethod(method)) {
+		return 1
+	}
+	return p.maxAttempts
+}
+
+// backoffBefore returns the wait before the given retry (1 for the first), doubling each time
+func (p proxyRetryPolicy) backoffBefore(retry int) time.Duration {
+	return p.backoff << (retry - 1)
+}
+
+// isIdempotentMethod reports whether repeating the method has the same effect as sending it once
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableProxyStatus reports whether an attempt's status is worth retrying: the gateway
+// errors the upstream sends when it is overloaded, and the ones proxying produces when it
+// can't connect (502) or times out (504)
+func isRetryableProxyStatus(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// waitProxyBackoff sleeps for the backoff, returning false when the client leaves first
+func waitProxyBackoff(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
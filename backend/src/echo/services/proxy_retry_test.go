@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+// flakyUpstream answers 503 to the first failures requests, then echoes the request body
+func flakyUpstream(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestExecuteProxyRequest_Retry(t *testing.T) {
+	forward := func(upstreamURL, method, advanceConfig string) *http.Response {
+		project := &database.Project{AdvanceConfig: advanceConfig}
+		req := httptest.NewRequest(method, "/orders", strings.NewReader(`{"id":1}`))
+		resp, err := executeProxyRequest(context.Background(), upstreamURL, method, "/orders", "", req, proxyOptionsFor(project))
+		require.NoError(t, err)
+		return resp
+	}
+	retryConfig := `{"proxyRetry": {"maxAttempts": 3, "backoffMs": 1}}`
+
+	t.Run("Idempotent methods are retried with the body replayed", func(t *testing.T) {
+		upstream, calls := flakyUpstream(t, 2)
+		resp := forward(upstream.URL, http.MethodPut, retryConfig)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "3", resp.Header.Get("beo-echo-proxy-attempts"))
+		assert.Equal(t, int32(3), calls.Load())
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"id":1}`, string(body))
+	})
+
+	t.Run("The last failure is returned once attempts run out", func(t *testing.T) {
+		upstream, calls := flakyUpstream(t, 5)
+		resp := forward(upstream.URL, http.MethodGet, retryConfig)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "3", resp.Header.Get("beo-echo-proxy-attempts"))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("Non-idempotent methods are sent once", func(t *testing.T) {
+		upstream, calls := flakyUpstream(t, 1)
+		resp := forward(upstream.URL, http.MethodPost, retryConfig)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get("beo-echo-proxy-attempts"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Non-idempotent methods are retried when allowed", func(t *testing.T) {
+		upstream, _ := flakyUpstream(t, 1)
+		resp := forward(upstream.URL, http.MethodPost, `{"proxyRetry": {"maxAttempts": 2, "backoffMs": 1, "retryNonIdempotent": true}}`)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get("beo-echo-proxy-attempts"))
+	})
+
+	t.Run("Without retry config the request is sent once", func(t *testing.T) {
+		upstream, calls := flakyUpstream(t, 1)
+		resp := forward(upstream.URL, http.MethodGet, "")
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("beo-echo-proxy-attempts"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestProxyRetryPolicy_Backoff(t *testing.T) {
+	policy := newProxyRetryPolicy(&database.ProxyRetryConfig{MaxAttempts: 4})
+
+	assert.Equal(t, defaultProxyRetryBackoff, policy.backoffBefore(1))
+	assert.Equal(t, 2*defaultProxyRetryBackoff, policy.backoffBefore(2))
+	assert.Equal(t, 4*defaultProxyRetryBackoff, policy.backoffBefore(3))
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// resolveProxyTarget picks the upstream for a proxied request: the target of the first proxyRoutes
+// entry matching the request, otherwise the project's active proxy (nil when neither exists).
+// Routes pointing at an unknown target are skipped.
+func resolveProxyTarget(project *database.Project, path string, req *http.Request) *database.ProxyTarget {
+	if project.AdvanceConfig != "" {
+		if config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig); err == nil {
+			for _, route := range config.ProxyRoutes {
+				if !matchProxyRoute(route, path, req) {
+					continue
+				}
+				if target := findProxyTarget(project, route.TargetID); target != nil {
+					return target
+				}
+			}
+		}
+	}
+	return project.ActiveProxy
+}
+
+// matchProxyRoute reports whether every condition set on the route matches the request
+func matchProxyRoute(route database.ProxyRoute, path string, req *http.Request) bool {
+	if route.PathPrefix != "" && !strings.HasPrefix(path, route.PathPrefix) {
+		return false
+	}
+	if route.Header != "" {
+		value := req.Header.Get(route.Header)
+		if value == "" || (route.Value != "" && value != route.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// findProxyTarget returns the project's proxy target with the given ID
+func findProxyTarget(project *database.Project, targetID string) *database.ProxyTarget {
+	for i := range project.ProxyTargets {
+		if project.ProxyTargets[i].ID == targetID {
+			return &project.ProxyTargets[i]
+		}
+	}
+	if project.ActiveProxy != nil && project.ActiveProxy.ID == targetID {
+		return project.ActiveProxy
+	}
+	return nil
+}
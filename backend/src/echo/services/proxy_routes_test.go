@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestResolveProxyTarget(t *testing.T) {
+	active := &database.ProxyTarget{ID: "default", URL: "http://default.invalid"}
+	project := &database.Project{
+		ActiveProxy: active,
+		ProxyTargets: []database.ProxyTarget{
+			*active,
+			{ID: "acme", URL: "http://acme.invalid"},
+			{ID: "v2", URL: "http://v2.invalid"},
+		},
+		AdvanceConfig: `{"proxyRoutes": [
+			{"header": "X-Tenant", "value": "acme", "targetId": "acme"},
+			{"pathPrefix": "/v2", "targetId": "v2"},
+			{"pathPrefix": "/gone", "targetId": "missing"}
+		]}`,
+	}
+
+	resolve := func(path, tenant string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if tenant != "" {
+			req.Header.Set("X-Tenant", tenant)
+		}
+		return resolveProxyTarget(project, path, req).ID
+	}
+
+	assert.Equal(t, "acme", resolve("/v2/users", "acme"), "first matching route wins")
+	assert.Equal(t, "v2", resolve("/v2/users", "globex"))
+	assert.Equal(t, "default", resolve("/v1/users", "globex"))
+	assert.Equal(t, "default", resolve("/gone", ""), "routes to unknown targets fall back to the active proxy")
+}
+
+func TestHandleForwarderMode_ProxyRoutes(t *testing.T) {
+	upstream := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+		}))
+	}
+	primary, tenant := upstream("primary"), upstream("tenant")
+	defer primary.Close()
+	defer tenant.Close()
+
+	project := &database.Project{
+		Mode:          database.ModeForwarder,
+		ActiveProxy:   &database.ProxyTarget{ID: "primary", URL: primary.URL},
+		ProxyTargets:  []database.ProxyTarget{{ID: "tenant", URL: tenant.URL}},
+		AdvanceConfig: `{"proxyRoutes": [{"header": "X-Tenant", "targetId": "tenant"}]}`,
+	}
+
+	call := func(withTenant bool) string {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if withTenant {
+			req.Header.Set("X-Tenant", "acme")
+		}
+		resp, err := (&MockService{}).handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, "tenant", call(true))
+	assert.Equal(t, "primary", call(false))
+}
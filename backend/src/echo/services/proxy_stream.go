@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// defaultProxyBufferLimit applies when PROXY_BUFFER_MAX_BYTES can't be read
+const defaultProxyBufferLimit = 1 << 20 // 1MB
+
+// proxyStreamBody passes an upstream body to the client as it arrives instead of holding it in
+// memory. Bytes read while deciding to stream are replayed first.
+type proxyStreamBody struct {
+	io.Reader
+	upstream io.Closer
+}
+
+func (b *proxyStreamBody) Close() error {
+	return b.upstream.Close()
+}
+
+// Streaming marks proxyStreamBody as a StreamingBody so every chunk is flushed as it arrives
+func (b *proxyStreamBody) Streaming() {}
+
+// proxyBufferLimit returns PROXY_BUFFER_MAX_BYTES, 0 when every upstream body is buffered
+func proxyBufferLimit() int64 {
+	if database.DB == nil {
+		return defaultProxyBufferLimit
+	}
+	limit, err := systemConfig.GetSystemConfigWithType[int](systemConfig.PROXY_BUFFER_MAX_BYTES)
+	if err != nil || limit < 0 {
+		return defaultProxyBufferLimit
+	}
+	return int64(limit)
+}
+
+// bufferProxyBody reads an upstream body of up to limit bytes into memory. Larger bodies, by
+// Content-Length or once read past the limit, and event streams are left streaming. A limit of
+// 0 buffers every body. Errors are those of reading the body; the upstream is then closed.
+func bufferProxyBody(resp *http.Response, limit int64) error {
+	if limit > 0 && (resp.ContentLength > limit || isEventStream(resp.Header)) {
+		resp.Body = &proxyStreamBody{Reader: resp.Body, upstream: resp.Body}
+		return nil
+	}
+
+	var reader io.Reader = resp.Body
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		resp.Body = &proxyStreamBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), upstream: resp.Body}
+		return nil
+	}
+
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+// isProxyStream reports whether a proxied body is streamed, so it can't be decoded or recorded
+func isProxyStream(resp *http.Response) bool {
+	_, ok := resp.Body.(*proxyStreamBody)
+	return ok
+}
+
+// isEventStream reports whether the response is Server-Sent Events, which must never wait for
+// the end of the body
+func isEventStream(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
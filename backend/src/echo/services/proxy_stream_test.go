@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferProxyBody(t *testing.T) {
+	upstream := func(body string, contentLength int64, contentType string) *http.Response {
+		return &http.Response{
+			Header:        http.Header{"Content-Type": {contentType}},
+			ContentLength: contentLength,
+			Body:          &countingBody{Reader: strings.NewReader(body)},
+		}
+	}
+
+	t.Run("Small bodies are buffered", func(t *testing.T) {
+		resp := upstream("0123456789", -1, "text/plain")
+		require.NoError(t, bufferProxyBody(resp, 10))
+		assert.False(t, isProxyStream(resp))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "0123456789", string(body))
+	})
+
+	t.Run("Content-Length over the limit streams without reading", func(t *testing.T) {
+		resp := upstream("0123456789abc", 13, "text/plain")
+		counting := resp.Body.(*countingBody)
+		require.NoError(t, bufferProxyBody(resp, 10))
+		assert.True(t, isProxyStream(resp))
+		assert.Equal(t, 0, counting.drained)
+	})
+
+	t.Run("Chunked bodies past the limit stream from the start", func(t *testing.T) {
+		resp := upstream("0123456789abc", -1, "text/plain")
+		require.NoError(t, bufferProxyBody(resp, 10))
+		assert.True(t, isProxyStream(resp))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "0123456789abc", string(body))
+	})
+
+	t.Run("Event streams are never buffered", func(t *testing.T) {
+		resp := upstream("data: hi\n\n", -1, "text/event-stream; charset=utf-8")
+		require.NoError(t, bufferProxyBody(resp, 1024))
+		assert.True(t, isProxyStream(resp))
+		_, ok := resp.Body.(StreamingBody)
+		assert.True(t, ok, "streamed bodies are flushed chunk by chunk")
+	})
+
+	t.Run("Zero buffers everything", func(t *testing.T) {
+		resp := upstream(strings.Repeat("x", 1000), 1000, "text/plain")
+		require.NoError(t, bufferProxyBody(resp, 0))
+		assert.False(t, isProxyStream(resp))
+	})
+}
+
+func TestExecuteProxyRequest_StreamsLargeBodies(t *testing.T) {
+	const chunk = 64 * 1024
+	const total = 32 << 20 // 32MB, far past the default buffer limit
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := bytes.Repeat([]byte("x"), chunk)
+		for sent := 0; sent < total/2; sent += chunk {
+			w.Write(data)
+		}
+		w.(http.Flusher).Flush()
+		<-release // The proxy must answer before the upstream has finished
+		for sent := 0; sent < total/2; sent += chunk {
+			w.Write(data)
+		}
+	}))
+	defer upstream.Close()
+	finish := sync.OnceFunc(func() { close(release) })
+	defer finish()
+
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	req := httptest.NewRequest(http.MethodGet, "/download", nil)
+	resp, err := executeProxyRequest(context.Background(), upstream.URL, http.MethodGet, "/download", "", req, proxyOptions{decodeResponse: true})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, isProxyStream(resp))
+	_, recorded := readRecordedBody(resp)
+	assert.False(t, recorded, "streamed bodies aren't recorded")
+
+	finish()
+	read, err := io.Copy(io.Discard, resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int64(total), read)
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.HeapAlloc, before.HeapAlloc+total/4, "the body isn't held in memory")
+}
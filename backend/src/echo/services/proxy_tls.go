@@ -0,0 +1,76 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// proxyCAPools caches the root pools built from PROXY_CA_CERT_PATHS, keyed by the configured value,
+// so bundles are read once rather than on every proxied request
+var proxyCAPools sync.Map
+
+// proxyTLSConfig returns the TLS settings for upstream connections. Without verification any
+// certificate is accepted, as beo-echo always did; with it certificates must chain to the system
+// roots or to a bundle listed in PROXY_CA_CERT_PATHS.
+func proxyTLSConfig(verify bool) (*tls.Config, error) {
+	if !verify {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	pool, err := proxyRootCAs()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// systemProxyTLSVerify reports whether PROXY_TLS_VERIFY is on
+func systemProxyTLSVerify() bool {
+	if database.DB == nil {
+		return false
+	}
+	verify, err := systemConfig.GetSystemConfigWithType[bool](systemConfig.PROXY_TLS_VERIFY)
+	return err == nil && verify
+}
+
+// proxyRootCAs returns the system roots extended with the PROXY_CA_CERT_PATHS bundles, or nil
+// (the system roots) when none are configured
+func proxyRootCAs() (*x509.CertPool, error) {
+	if database.DB == nil {
+		return nil, nil
+	}
+	paths, err := systemConfig.GetSystemConfigWithType[string](systemConfig.PROXY_CA_CERT_PATHS)
+	if err != nil || strings.TrimSpace(paths) == "" {
+		return nil, nil
+	}
+	if pool, ok := proxyCAPools.Load(paths); ok {
+		return pool.(*x509.CertPool), nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, path := range strings.Split(paths, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle %s: %w", path, err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+		}
+	}
+	proxyCAPools.Store(paths, pool)
+	return pool, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestExecuteProxyRequest_TLSVerification(t *testing.T) {
+	database.SetupTestEnvironment(t)
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	defer upstream.Close()
+
+	forward := func(advanceConfig string) int {
+		project := &database.Project{AdvanceConfig: advanceConfig}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		resp, err := executeProxyRequest(context.Background(), upstream.URL, http.MethodGet, "/", "", req, proxyOptionsFor(project))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Unverified by default", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, forward(""))
+	})
+
+	t.Run("Project opt-in rejects an unknown authority", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadGateway, forward(`{"proxyTlsVerify": true}`))
+	})
+
+	t.Run("System config enables verification, projects can opt out", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.PROXY_TLS_VERIFY, "true"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.PROXY_TLS_VERIFY, "false") })
+
+		assert.Equal(t, http.StatusBadGateway, forward(""))
+		assert.Equal(t, http.StatusOK, forward(`{"proxyTlsVerify": false}`))
+	})
+
+	t.Run("Configured CA bundle is trusted", func(t *testing.T) {
+		caPath := filepath.Join(t.TempDir(), "upstream-ca.pem")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: upstream.Certificate().Raw})
+		require.NoError(t, os.WriteFile(caPath, caPEM, 0o644))
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.PROXY_CA_CERT_PATHS, caPath))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.PROXY_CA_CERT_PATHS, "") })
+
+		assert.Equal(t, http.StatusOK, forward(`{"proxyTlsVerify": true}`))
+	})
+
+	t.Run("Unreadable CA bundle fails the request", func(t *testing.T) {
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.PROXY_CA_CERT_PATHS, filepath.Join(t.TempDir(), "missing.pem")))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.PROXY_CA_CERT_PATHS, "") })
+
+		assert.Equal(t, http.StatusBadGateway, forward(`{"proxyTlsVerify": true}`))
+	})
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+
+	"beo-echo/backend/src/database"
+)
+
+// proxyTransport returns the upstream transport for a proxyHttpVersion setting. HTTP/1.1 is the
+// default; "auto" offers HTTP/2 in the TLS handshake, and "http2" speaks nothing else, with
+// prior knowledge (h2c) on http targets as gRPC upstreams expect.
+func proxyTransport(version, scheme string, tlsConfig *tls.Config) http.RoundTripper {
+	switch version {
+	case database.ProxyHTTPAuto:
+		return &http.Transport{
+			TLSClientConfig:   tlsConfig,
+			ForceAttemptHTTP2: true,
+		}
+	case database.ProxyHTTP2:
+		transport := &http2.Transport{TLSClientConfig: tlsConfig}
+		if scheme == "http" {
+			transport.AllowHTTP = true
+			transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, addr)
+			}
+		}
+		return transport
+	default:
+		return &http.Transport{TLSClientConfig: tlsConfig}
+	}
+}
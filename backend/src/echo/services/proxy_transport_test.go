@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"beo-echo/backend/src/database"
+)
+
+// grpcLikeUpstream answers with its protocol in the body and a Grpc-Status trailer
+var grpcLikeUpstream = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Trailer", "Grpc-Status")
+	w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+	w.Write([]byte(r.Proto))
+	w.Header().Set("Grpc-Status", "0")
+})
+
+func TestExecuteProxyRequest_HTTPVersion(t *testing.T) {
+	tlsUpstream := httptest.NewUnstartedServer(grpcLikeUpstream)
+	tlsUpstream.EnableHTTP2 = true
+	tlsUpstream.StartTLS()
+	defer tlsUpstream.Close()
+
+	h2cUpstream := httptest.NewServer(h2c.NewHandler(grpcLikeUpstream, &http2.Server{}))
+	defer h2cUpstream.Close()
+
+	forward := func(target, version, query string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/helloworld.Greeter/SayHello", nil)
+		resp, err := executeProxyRequest(context.Background(), target, http.MethodPost, "/helloworld.Greeter/SayHello", query, req, proxyOptions{httpVersion: version})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		version string
+		proto   string
+	}{
+		{"TLS defaults to HTTP/1.1", tlsUpstream.URL, "", "HTTP/1.1"},
+		{"TLS auto negotiates HTTP/2", tlsUpstream.URL, database.ProxyHTTPAuto, "HTTP/2.0"},
+		{"TLS forced HTTP/2", tlsUpstream.URL, database.ProxyHTTP2, "HTTP/2.0"},
+		{"Cleartext auto stays on HTTP/1.1", h2cUpstream.URL, database.ProxyHTTPAuto, "HTTP/1.1"},
+		{"Cleartext forced HTTP/2 uses h2c", h2cUpstream.URL, database.ProxyHTTP2, "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := forward(tt.target, tt.version, "type=application/grpc")
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.proto, string(body))
+			assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+		})
+	}
+
+	t.Run("Trailers of streamed bodies follow the body", func(t *testing.T) {
+		resp := forward(h2cUpstream.URL, database.ProxyHTTP2, "type=text/event-stream")
+		require.True(t, isProxyStream(resp))
+		io.ReadAll(resp.Body)
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+	})
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// querySignatureConfig is the JSON stored in the Value of a query_signature rule.
+// The rule Key names the query param carrying the signature.
+type querySignatureConfig struct {
+	Secret    string   `json:"secret"`
+	Algorithm string   `json:"algorithm,omitempty"` // hmac-sha256 (default), hmac-sha1, hmac-sha512
+	Params    []string `json:"params,omitempty"`    // Params to sign; empty signs every param except the signature
+	Encoding  string   `json:"encoding,omitempty"`  // hex (default) or base64
+}
+
+// parseQuerySignatureConfig parses and validates a query_signature rule value
+func parseQuerySignatureConfig(value string) (*querySignatureConfig, error) {
+	var config querySignatureConfig
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return nil, fmt.Errorf("query_signature rule value must be JSON: %v", err)
+	}
+	if config.Secret == "" {
+		return nil, fmt.Errorf("query_signature rule requires a secret")
+	}
+	if _, err := signatureHash(config.Algorithm); err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(config.Encoding) {
+	case "", "hex", "base64":
+	default:
+		return nil, fmt.Errorf("query_signature encoding must be hex or base64")
+	}
+	return &config, nil
+}
+
+// signatureHash returns the hash constructor for an algorithm name
+func signatureHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "", "hmac-sha256":
+		return sha256.New, nil
+	case "hmac-sha1":
+		return sha1.New, nil
+	case "hmac-sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("query_signature algorithm must be hmac-sha256, hmac-sha1, or hmac-sha512")
+	}
+}
+
+// canonicalQuery builds the signed string: selected params sorted by name (then value)
+// as name=value pairs joined with "&", using decoded values so encoding differences don't matter
+func canonicalQuery(query url.Values, params []string, signatureParam string) string {
+	names := params
+	if len(names) == 0 {
+		for name := range query {
+			if name != signatureParam {
+				names = append(names, name)
+			}
+		}
+	}
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// matchQuerySignatureRule matches when the signature param equals the signature recomputed
+// over the canonical query with the configured secret
+func matchQuerySignatureRule(rule database.MockRule, req *http.Request) bool {
+	config, err := parseQuerySignatureConfig(rule.Value)
+	if err != nil {
+		return false
+	}
+	query := req.URL.Query()
+	provided := query.Get(rule.Key)
+	if provided == "" {
+		return false
+	}
+
+	newHash, _ := signatureHash(config.Algorithm)
+	mac := hmac.New(newHash, []byte(config.Secret))
+	mac.Write([]byte(canonicalQuery(query, config.Params, rule.Key)))
+	sum := mac.Sum(nil)
+
+	if strings.ToLower(config.Encoding) == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(provided)
+		if err != nil {
+			if decoded, err = base64.URLEncoding.DecodeString(provided); err != nil {
+				return false
+			}
+		}
+		return hmac.Equal(decoded, sum)
+	}
+
+	decoded, err := hex.DecodeString(provided)
+	return err == nil && hmac.Equal(decoded, sum)
+}
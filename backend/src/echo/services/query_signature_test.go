@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func sign(secret, message string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func TestMatchQuerySignatureRule(t *testing.T) {
+	rule := database.MockRule{
+		Type:     "query_signature",
+		Key:      "sig",
+		Operator: "equals",
+		Value:    `{"secret":"s3cret","params":["order_id","amount"]}`,
+	}
+	validSig := hex.EncodeToString(sign("s3cret", "amount=10.00&order_id=A1"))
+
+	request := func(query string) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/callback?"+query, nil)
+	}
+
+	t.Run("Valid signature matches regardless of param order", func(t *testing.T) {
+		assert.True(t, matchQuerySignatureRule(rule, request("order_id=A1&amount=10.00&sig="+validSig)))
+		assert.True(t, matchQuerySignatureRule(rule, request("sig="+validSig+"&amount=10.00&order_id=A1&extra=ignored")))
+	})
+
+	t.Run("Tampered param or missing signature does not match", func(t *testing.T) {
+		assert.False(t, matchQuerySignatureRule(rule, request("order_id=A1&amount=99.00&sig="+validSig)))
+		assert.False(t, matchQuerySignatureRule(rule, request("order_id=A1&amount=10.00")))
+	})
+
+	t.Run("All params except the signature are signed by default, base64 encoding", func(t *testing.T) {
+		allParamsRule := rule
+		allParamsRule.Value = `{"secret":"s3cret","encoding":"base64"}`
+		sig := base64.StdEncoding.EncodeToString(sign("s3cret", "a=1&b=x y"))
+
+		assert.True(t, matchQuerySignatureRule(allParamsRule, request("b=x+y&a=1&sig="+url.QueryEscape(sig))))
+	})
+
+	t.Run("Invalid config never matches", func(t *testing.T) {
+		badRule := rule
+		badRule.Value = `{"secret":"s3cret","algorithm":"md5"}`
+		assert.False(t, matchQuerySignatureRule(badRule, request("order_id=A1&amount=10.00&sig="+validSig)))
+	})
+}
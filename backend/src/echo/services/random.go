@@ -0,0 +1,64 @@
+package services
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// randomSeedHeader lets a request seed its own random-mode selection
+const randomSeedHeader = "beo-echo-random-seed"
+
+// randomIntn and randomFloat64 are the global sources for random selection. Tests can replace
+// them to make selection deterministic without touching request headers.
+var (
+	randomIntn    = rand.Intn
+	randomFloat64 = rand.Float64
+)
+
+// pickRandomIndex returns an index in [0, n) for random-mode selection.
+// Precedence: a valid beo-echo-random-seed header (when RANDOM_SEED_HEADER_ENABLED is on)
+// seeds a per-request source, so the same seed always picks the same response for the
+// same candidates; otherwise the global randomIntn source is used.
+func pickRandomIndex(n int, req *http.Request) int {
+	if seeded := seededRequestRand(req); seeded != nil {
+		return seeded.Intn(n)
+	}
+	return randomIntn(n)
+}
+
+// pickRandomFloat returns a number in [0, 1) with the same precedence as pickRandomIndex
+func pickRandomFloat(req *http.Request) float64 {
+	if seeded := seededRequestRand(req); seeded != nil {
+		return seeded.Float64()
+	}
+	return randomFloat64()
+}
+
+// seededRequestRand returns a source seeded from the request header, or nil when not applicable
+func seededRequestRand(req *http.Request) *rand.Rand {
+	if req == nil {
+		return nil
+	}
+	seedValue := req.Header.Get(randomSeedHeader)
+	if seedValue == "" || !randomSeedHeaderEnabled() {
+		return nil
+	}
+	seed, err := strconv.ParseInt(seedValue, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// randomSeedHeaderEnabled reports whether requests may seed random selection
+func randomSeedHeaderEnabled() bool {
+	if database.DB == nil {
+		return false
+	}
+	enabled, err := systemConfig.GetSystemConfigWithType[bool](systemConfig.RANDOM_SEED_HEADER_ENABLED)
+	return err == nil && enabled
+}
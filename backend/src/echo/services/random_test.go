@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// stubRandomIntn replaces the global random source for the duration of the test
+func stubRandomIntn(t *testing.T, fn func(n int) int) {
+	original := randomIntn
+	randomIntn = fn
+	t.Cleanup(func() { randomIntn = original })
+}
+
+func TestSelectResponseWithEndpoint_RandomUsesInjectedSource(t *testing.T) {
+	stubRandomIntn(t, func(n int) int { return n - 1 })
+	responses := []database.MockResponse{
+		{ID: "a", Enabled: true},
+		{ID: "b", Enabled: true},
+		{ID: "c", Enabled: true},
+	}
+
+	selected := selectResponseWithEndpoint("endpoint-random", responses, "random", nil)
+
+	require.NotNil(t, selected)
+	assert.Equal(t, "c", selected.ID)
+}
+
+func TestPickRandomIndex_SeedHeader(t *testing.T) {
+	stubRandomIntn(t, func(n int) int { return 0 })
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(randomSeedHeader, "42")
+
+	t.Run("Header is ignored while the flag is off", func(t *testing.T) {
+		assert.Equal(t, 0, pickRandomIndex(1000, req))
+	})
+
+	t.Run("Seed takes precedence over the global source when enabled", func(t *testing.T) {
+		database.SetupTestEnvironment(t)
+		require.NoError(t, systemConfig.SetSystemConfig(systemConfig.RANDOM_SEED_HEADER_ENABLED, "true"))
+		t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.RANDOM_SEED_HEADER_ENABLED, "false") })
+
+		first := pickRandomIndex(1000, req)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, first, pickRandomIndex(1000, req), "same seed picks the same index")
+		}
+
+		req.Header.Set(randomSeedHeader, "not-a-number")
+		assert.Equal(t, 0, pickRandomIndex(1000, req), "invalid seed falls back to the global source")
+	})
+}
+
+func TestHandleForwarderMode_SeedHeader(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "seed-forwarder")
+
+	var forwarded http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+		w.Write([]byte("upstream"))
+	}))
+	defer upstream.Close()
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(randomSeedHeader, "42")
+	resp, err := service.handleForwarderMode(context.Background(), project, http.MethodGet, "/items", req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "seeded requests aren't proxy loops")
+	assert.Equal(t, "upstream", string(body))
+	assert.Empty(t, forwarded.Get(randomSeedHeader))
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// fixedWindow counts the requests of the current window, which restarts once window has
+// elapsed since it began
+type fixedWindow struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	start   time.Time
+	count   int
+	retired bool // Pruned from rateLimitWindows; requests must use the key's current window
+}
+
+// Global fixed windows per limiter key (see rateLimitKey -> *fixedWindow)
+var rateLimitWindows sync.Map
+
+// rateLimitPruneInterval is how often expired windows are swept from rateLimitWindows, so
+// per-client-IP keys don't accumulate
+const rateLimitPruneInterval = time.Minute
+
+// Time of the last sweep of rateLimitWindows in Unix nanoseconds
+var rateLimitLastPrune atomic.Int64
+
+// take counts a request in the window, starting a new window when the current one is over.
+// Returns the requests left, the time until the window resets and whether the request is
+// allowed. live is false for a retired window, which counts nothing.
+func (w *fixedWindow) take(now time.Time) (remaining int, reset time.Duration, ok bool, live bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.retired {
+		return 0, 0, false, false
+	}
+	if now.Sub(w.start) >= w.window {
+		w.start = now
+		w.count = 0
+	}
+	reset = w.start.Add(w.window).Sub(now)
+	if w.count >= w.limit {
+		return 0, reset, false, true
+	}
+	w.count++
+	return w.limit - w.count, reset, true, true
+}
+
+// retireIfExpired retires the window when its last window elapsed without a new request
+func (w *fixedWindow) retireIfExpired(now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now.Sub(w.start) >= w.window {
+		w.retired = true
+	}
+	return w.retired
+}
+
+// configuredAs reports whether the window was started with the config's limit and length
+func (w *fixedWindow) configuredAs(config *database.RateLimitWindow) bool {
+	return w.limit == config.Requests && w.window == time.Duration(config.WindowMs)*time.Millisecond
+}
+
+// loadRateLimitWindow returns the window of the key, starting a new one when the key has none
+// yet or its configuration changed. Concurrent callers always share one window.
+func loadRateLimitWindow(key string, config *database.RateLimitWindow, now time.Time) *fixedWindow {
+	if value, ok := rateLimitWindows.Load(key); ok && value.(*fixedWindow).configuredAs(config) {
+		return value.(*fixedWindow)
+	}
+
+	fresh := &fixedWindow{limit: config.Requests, window: time.Duration(config.WindowMs) * time.Millisecond, start: now}
+	for {
+		value, loaded := rateLimitWindows.LoadOrStore(key, fresh)
+		if !loaded {
+			return fresh
+		}
+		existing := value.(*fixedWindow)
+		if existing.configuredAs(config) {
+			return existing
+		}
+		// Only one caller replaces an outdated window; the others retry and find the new one
+		if rateLimitWindows.CompareAndSwap(key, existing, fresh) {
+			return fresh
+		}
+	}
+}
+
+// pruneRateLimitWindows removes expired windows, at most once per rateLimitPruneInterval.
+// Removed windows are retired first, so a request still holding one loads the new window.
+func pruneRateLimitWindows(now time.Time) {
+	last := rateLimitLastPrune.Load()
+	if now.UnixNano()-last < int64(rateLimitPruneInterval) || !rateLimitLastPrune.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	rateLimitWindows.Range(func(key, value any) bool {
+		if window := value.(*fixedWindow); window.retireIfExpired(now) {
+			rateLimitWindows.CompareAndDelete(key, window)
+		}
+		return true
+	})
+}
+
+// takeWindowRequest counts a request against the key's fixed window and returns the limit
+// headers to attach to the response. The headers are nil when no window is configured.
+func takeWindowRequest(key string, config *database.RateLimitWindow) (headers http.Header, ok bool) {
+	if config == nil || config.Requests <= 0 || config.WindowMs <= 0 {
+		return nil, true
+	}
+
+	now := time.Now()
+	pruneRateLimitWindows(now)
+	window := loadRateLimitWindow(key, config, now)
+	remaining, reset, ok, live := window.take(now)
+	for !live {
+		// The window was pruned meanwhile; drop it if the sweep hasn't yet and use a new one
+		rateLimitWindows.CompareAndDelete(key, window)
+		window = loadRateLimitWindow(key, config, now)
+		remaining, reset, ok, live = window.take(now)
+	}
+	resetSeconds := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+
+	headers = make(http.Header)
+	headers.Set("X-RateLimit-Limit", strconv.Itoa(config.Requests))
+	headers.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	headers.Set("X-RateLimit-Reset", resetSeconds)
+	if !ok {
+		headers.Set("Retry-After", resetSeconds)
+	}
+	return headers, ok
+}
+
+// rateLimitKey identifies the limiter state of a project or endpoint, per client IP when requested
+func rateLimitKey(scope, id string, perClientIP bool, req *http.Request) string {
+	key := scope + ":" + id
+	if !perClientIP {
+		return key
+	}
+	if ip := clientIP(req); ip != nil {
+		return key + "|" + ip.String()
+	}
+	return key + "|unknown"
+}
+
+// takeRateLimits checks the fixed window, then the token bucket, of a project or endpoint. A
+// request rejected by the window doesn't consume a token. The returned headers describe the
+// limit that rejected the request, or otherwise the one with the fewest requests left.
+func takeRateLimits(scope, id string, window *database.RateLimitWindow, bucket *database.TokenBucketConfig, req *http.Request) (http.Header, bool) {
+	headers, ok := takeWindowRequest(rateLimitKey(scope, id, window != nil && window.PerClientIP, req), window)
+	if !ok {
+		return headers, false
+	}
+	bucketHeaders, ok := takeToken(rateLimitKey(scope, id, bucket != nil && bucket.PerClientIP, req), bucket)
+	return mergeRateLimitHeaders(headers, bucketHeaders), ok
+}
+
+// mergeRateLimitHeaders combines the headers of two limits: a rejecting limit (with Retry-After)
+// wins, otherwise the one with the fewest requests left
+func mergeRateLimitHeaders(current, next http.Header) http.Header {
+	switch {
+	case next == nil:
+		return current
+	case current == nil, next.Get("Retry-After") != "":
+		return next
+	case current.Get("Retry-After") != "":
+		return current
+	}
+	currentRemaining, _ := strconv.Atoi(current.Get("X-RateLimit-Remaining"))
+	nextRemaining, _ := strconv.Atoi(next.Get("X-RateLimit-Remaining"))
+	if nextRemaining < currentRemaining {
+		return next
+	}
+	return current
+}
+
+// rateLimitHeadersKey stores the limit headers collected while handling a request, so endpoint
+// limits checked by the mode handlers reach the response alongside the project's
+type rateLimitHeadersKey struct{}
+
+// rateLimitHolder is the mutable slot behind rateLimitHeadersKey
+type rateLimitHolder struct {
+	headers http.Header
+}
+
+// withRateLimitHolder returns a request carrying an empty slot for limit headers
+func withRateLimitHolder(req *http.Request) (*http.Request, *rateLimitHolder) {
+	holder := &rateLimitHolder{}
+	return req.WithContext(context.WithValue(req.Context(), rateLimitHeadersKey{}, holder)), holder
+}
+
+// checkEndpointRateLimits applies the endpoint's rateLimit and tokenBucket. The headers are
+// merged into the request's limit headers; ok is false when the request must be rejected.
+func checkEndpointRateLimits(endpoint *database.MockEndpoint, req *http.Request) bool {
+	if endpoint == nil || endpoint.AdvanceConfig == "" {
+		return true
+	}
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil || (config.RateLimit == nil && config.TokenBucket == nil) {
+		return true
+	}
+
+	headers, ok := takeRateLimits("endpoint", endpoint.ID, config.RateLimit, config.TokenBucket, req)
+	if holder, found := req.Context().Value(rateLimitHeadersKey{}).(*rateLimitHolder); found {
+		holder.headers = mergeRateLimitHeaders(holder.headers, headers)
+	}
+	return ok
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestFixedWindow_Take(t *testing.T) {
+	start := time.Now()
+	window := &fixedWindow{limit: 2, window: time.Minute, start: start}
+
+	remaining, reset, ok, _ := window.take(start)
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, time.Minute, reset)
+
+	remaining, _, ok, _ = window.take(start.Add(10 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	_, reset, ok, _ = window.take(start.Add(20 * time.Second))
+	assert.False(t, ok, "full window rejects")
+	assert.Equal(t, 40*time.Second, reset)
+
+	// A new window starts once the current one has elapsed
+	remaining, _, ok, _ = window.take(start.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestTakeWindowRequest_Concurrent(t *testing.T) {
+	allowedConcurrently := func(key string, config *database.RateLimitWindow) int64 {
+		t.Cleanup(func() { rateLimitWindows.Delete(key) })
+		var allowed atomic.Int64
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				if _, ok := takeWindowRequest(key, config); ok {
+					allowed.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		return allowed.Load()
+	}
+
+	t.Run("First requests share one window", func(t *testing.T) {
+		assert.Equal(t, int64(5), allowedConcurrently("window-first", &database.RateLimitWindow{Requests: 5, WindowMs: 60000}))
+	})
+
+	t.Run("A changed config starts one new window", func(t *testing.T) {
+		allowedConcurrently("window-change", &database.RateLimitWindow{Requests: 2, WindowMs: 60000})
+		assert.Equal(t, int64(4), allowedConcurrently("window-change", &database.RateLimitWindow{Requests: 4, WindowMs: 60000}))
+	})
+}
+
+func TestPruneRateLimitWindows(t *testing.T) {
+	now := time.Now()
+	rateLimitLastPrune.Store(0)
+	expired := &fixedWindow{limit: 1, window: time.Second, start: now.Add(-2 * time.Second)}
+	current := &fixedWindow{limit: 1, window: time.Minute, start: now}
+	rateLimitWindows.Store("prune-expired", expired)
+	rateLimitWindows.Store("prune-current", current)
+	t.Cleanup(func() {
+		rateLimitWindows.Delete("prune-expired")
+		rateLimitWindows.Delete("prune-current")
+	})
+
+	pruneRateLimitWindows(now)
+
+	_, found := rateLimitWindows.Load("prune-expired")
+	assert.False(t, found, "expired windows are removed")
+	_, found = rateLimitWindows.Load("prune-current")
+	assert.True(t, found)
+	_, _, _, live := expired.take(now)
+	assert.False(t, live, "requests holding a pruned window load the new one")
+
+	// Sweeps run at most once per interval
+	rateLimitWindows.Store("prune-expired", expired)
+	pruneRateLimitWindows(now.Add(time.Second))
+	_, found = rateLimitWindows.Load("prune-expired")
+	assert.True(t, found)
+}
+
+func TestMergeRateLimitHeaders(t *testing.T) {
+	limit := func(remaining, retryAfter string) http.Header {
+		h := http.Header{"X-Ratelimit-Remaining": {remaining}}
+		if retryAfter != "" {
+			h.Set("Retry-After", retryAfter)
+		}
+		return h
+	}
+
+	assert.Equal(t, "3", mergeRateLimitHeaders(limit("3", ""), nil).Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "3", mergeRateLimitHeaders(nil, limit("3", "")).Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", mergeRateLimitHeaders(limit("5", ""), limit("1", "")).Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", mergeRateLimitHeaders(limit("1", ""), limit("5", "")).Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "7", mergeRateLimitHeaders(limit("0", ""), limit("0", "7")).Get("Retry-After"))
+}
+
+func TestHandleRequest_EndpointRateLimit(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "endpoint-rate-limit")
+
+	limited, err := database.CreateTestEndpoint(project.ID, "GET", "/limited")
+	require.NoError(t, err)
+	limited.AdvanceConfig = `{"rateLimit": {"requests": 2, "windowMs": 60000, "perClientIp": true}, "limitExceededResponse": {"body": "{\"slow\":\"down\"}"}}`
+	require.NoError(t, database.DB.Save(limited).Error)
+	createTestResponse(t, limited.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+	open, err := database.CreateTestEndpoint(project.ID, "GET", "/open")
+	require.NoError(t, err)
+	createTestResponse(t, open.ID, database.MockResponse{StatusCode: 200, Body: "ok"})
+
+	call := func(path, ip string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/endpoint-rate-limit"+path, nil)
+		req.RemoteAddr = ip + ":4000"
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, path, req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := call("/limited", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "60", resp.Header.Get("X-RateLimit-Reset"))
+
+	call("/limited", "10.0.0.1")
+	resp = call("/limited", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"slow":"down"}`, string(body))
+
+	// Each client IP has its own window, and other endpoints are not limited
+	assert.Equal(t, http.StatusOK, call("/limited", "10.0.0.2").StatusCode)
+	resp = call("/open", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+}
+
+func TestHandleRequest_ProjectRateLimitWindow(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "project-rate-limit")
+	project.AdvanceConfig = `{"rateLimit": {"requests": 1, "windowMs": 60000}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/a")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "a"})
+
+	call := func() *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/project-rate-limit/a", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/a", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, call().StatusCode)
+	resp := call()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"beo-echo/backend/src/database"
+)
+
+// recordMu serializes captures so concurrent identical requests can't both create a response
+var recordMu sync.Mutex
+
+// recordSkippedHeaders are not replayed from a capture: they describe the original transfer,
+// or are set again by beo-echo when the mock is served
+var recordSkippedHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Date":              true,
+}
+
+// recordingEnabled reports whether the project's "record" toggle is on
+func recordingEnabled(project *database.Project) bool {
+	if project == nil || project.AdvanceConfig == "" {
+		return false
+	}
+	config, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	return err == nil && config.Record
+}
+
+// recordProxiedResponse saves an upstream response as a mock response of the endpoint with the
+// request's method and path, creating the endpoint (static mode) when it doesn't exist. Query
+// parameters become query rules, so captures of /search?q=a and /search?q=b replay separately
+// once the project is switched to mock mode; more specific captures get a higher priority. An
+// identical capture (same query, status and body) is not saved twice, even when headers such as
+// a request ID differ. Responses beo-echo generated itself, such as a 502 for an unreachable
+// upstream, are never recorded.
+func (s *MockService) recordProxiedResponse(project *database.Project, method, path string, req *http.Request, resp *http.Response) {
+	if s.Repo == nil || !recordingEnabled(project) || resp == nil || resp.Body == nil {
+		return
+	}
+	if _, generated := resp.Body.(*generatedBody); generated {
+		return
+	}
+
+	body, ok := readRecordedBody(resp)
+	if !ok {
+		return
+	}
+	headers, err := json.Marshal(recordedHeaders(resp.Header))
+	if err != nil {
+		return
+	}
+	rules := recordedQueryRules(req)
+	capture := database.MockResponse{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Headers:    string(headers),
+		Priority:   len(rules),
+		Enabled:    true,
+		Note:       "Recorded from " + requestLine(req),
+		Rules:      rules,
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+
+	endpoint, err := s.Repo.FindEndpointByMethodPath(project.ID, method, path)
+	if err != nil {
+		endpoint = &database.MockEndpoint{
+			ProjectID:    project.ID,
+			Method:       strings.ToUpper(method),
+			Path:         path,
+			Enabled:      true,
+			ResponseMode: "static",
+		}
+		if err := s.Repo.CreateEndpoint(endpoint); err != nil {
+			fmt.Println("Error recording endpoint:", err)
+			return
+		}
+	}
+
+	// Disabled responses count too: a capture the user switched off isn't recorded again
+	existing, err := s.Repo.FindAllResponsesByEndpointID(endpoint.ID)
+	if err != nil {
+		fmt.Println("Error loading recorded responses:", err)
+		return
+	}
+	for _, response := range existing {
+		if sameCapture(response, capture) {
+			return
+		}
+	}
+
+	capture.EndpointID = endpoint.ID
+	if err := s.Repo.CreateResponse(&capture); err != nil {
+		fmt.Println("Error recording response:", err)
+	}
+}
+
+// readRecordedBody reads the upstream body and restores it for the client. Encoded bodies are
+// stored decoded, keeping Content-Encoding so the mock pipeline encodes them again on replay.
+// Bodies that can't be decoded, are streamed or decode past decodedBodyLimit aren't recorded.
+func readRecordedBody(resp *http.Response) (string, bool) {
+	if decoded, ok := resp.Body.(DecodedBody); ok {
+		return string(decoded.DecodedBytes()), !decoded.DecodedTruncated()
+	}
+	if isProxyStream(resp) {
+		return "", false // Too large to hold in memory, see PROXY_BUFFER_MAX_BYTES
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return "", false
+	}
+
+	encodings := parseContentEncodings(resp.Header.Get("Content-Encoding"))
+	decoded, truncated := raw, false
+	for i := len(encodings) - 1; i >= 0; i-- {
+		if !isDecodableEncoding(encodings[i]) {
+			return "", false
+		}
+		if decoded, truncated, err = decodeContentEncoding(encodings[i], decoded, decodedBodyLimit(proxyBufferLimit()), truncated); err != nil || truncated {
+			return "", false
+		}
+	}
+	return string(decoded), true
+}
+
+// recordedHeaders flattens the upstream headers for a mock response, dropping beo-echo's own
+// headers and the transfer-specific ones. Repeated headers are joined with ", ".
+func recordedHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if recordSkippedHeaders[http.CanonicalHeaderKey(name)] || strings.HasPrefix(strings.ToLower(name), "beo-echo") {
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// recordedQueryRules turns each query parameter into an equals rule, ordered by key
+func recordedQueryRules(req *http.Request) []database.MockRule {
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rules := make([]database.MockRule, 0, len(keys))
+	for _, key := range keys {
+		rules = append(rules, database.MockRule{Type: "query", Key: key, Operator: "equals", Value: query.Get(key)})
+	}
+	return rules
+}
+
+// sameCapture reports whether a stored response already holds the capture: same status, body and query rules
+func sameCapture(response, capture database.MockResponse) bool {
+	if response.StatusCode != capture.StatusCode || response.Body != capture.Body {
+		return false
+	}
+	if len(response.Rules) != len(capture.Rules) {
+		return false
+	}
+
+	rules := make(map[database.MockRule]bool, len(response.Rules))
+	for _, rule := range response.Rules {
+		rules[database.MockRule{Type: rule.Type, Key: rule.Key, Operator: rule.Operator, Value: rule.Value}] = true
+	}
+	for _, rule := range capture.Rules {
+		if !rules[rule] {
+			return false
+		}
+	}
+	return true
+}
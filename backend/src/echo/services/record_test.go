@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestRecordProxiedResponse(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "record-test")
+
+	var calls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", r.URL.RawQuery+"-"+string(rune('0'+calls)))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(gzipBytes(t, []byte(`{"q":"`+r.URL.Query().Get("q")+`"}`)))
+	}))
+	defer upstream.Close()
+
+	project.Mode = database.ModeForwarder
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+	project.AdvanceConfig = `{"record": true}`
+
+	forward := func(target string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := service.handleForwarderMode(context.Background(), project, http.MethodGet, req.URL.Path, req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// The client still receives the upstream bytes
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		assert.NotEmpty(t, body)
+	}
+
+	forward("/search?q=books")
+	forward("/search?q=books") // identical capture, only the request ID differs
+	forward("/search?q=music")
+	forward("/search")
+
+	endpoint, err := service.Repo.FindEndpointByMethodPath(project.ID, http.MethodGet, "/search")
+	require.NoError(t, err)
+	assert.Equal(t, "static", endpoint.ResponseMode)
+
+	responses, err := service.Repo.FindResponsesByEndpointID(endpoint.ID)
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+	for _, response := range responses {
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Contains(t, response.Headers, `"Content-Encoding":"gzip"`)
+		assert.NotContains(t, response.Headers, "Beo-Echo")
+	}
+
+	t.Run("Captures replay offline in mock mode", func(t *testing.T) {
+		upstream.Close()
+		project.Mode = database.ModeMock
+
+		replay := func(target string) string {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			resp, err, _, _ := service.handleMockMode(context.Background(), project, http.MethodGet, req.URL.Path, req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			decoded, _, err := decodeContentEncoding("gzip", body, defaultProxyBufferLimit, false)
+			require.NoError(t, err)
+			return string(decoded)
+		}
+
+		assert.Equal(t, `{"q":"books"}`, replay("/search?q=books"))
+		assert.Equal(t, `{"q":"music"}`, replay("/search?q=music"))
+		assert.Equal(t, `{"q":""}`, replay("/search"))
+	})
+}
+
+func TestRecordProxiedResponse_DisabledDuplicate(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "record-dup-test")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	project.Mode = database.ModeForwarder
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+	project.AdvanceConfig = `{"record": true}`
+
+	forward := func() {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		resp, err := service.handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Given - A recorded response the user disabled
+	forward()
+	endpoint, err := service.Repo.FindEndpointByMethodPath(project.ID, http.MethodGet, "/users")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(&database.MockResponse{}).Where("endpoint_id = ?", endpoint.ID).Update("enabled", false).Error)
+
+	// When - The same response is proxied again
+	forward()
+
+	// Then - It isn't recorded a second time
+	responses, err := service.Repo.FindAllResponsesByEndpointID(endpoint.ID)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	assert.False(t, responses[0].Enabled)
+}
+
+func TestRecordProxiedResponse_Disabled(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "record-off-test")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	resp, err := service.handleForwarderMode(context.Background(), project, http.MethodGet, "/users", req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = service.Repo.FindEndpointByMethodPath(project.ID, http.MethodGet, "/users")
+	assert.Error(t, err)
+}
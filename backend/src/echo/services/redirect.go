@@ -0,0 +1,58 @@
+package services
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"beo-echo/backend/src/database"
+)
+
+// redirectHopParam is the query parameter used to count redirect hops between mock endpoints.
+// Clients don't send custom headers when following a Location, so the counter travels in the URL.
+const redirectHopParam = "beo-echo-redirect-hop"
+
+// maxRedirectHops is the number of mock-to-mock redirects allowed before the chain is treated as a loop
+const maxRedirectHops = 10
+
+// prepareRedirectResponse rewrites the Location of a redirect response so the next hop is counted.
+// Returns the project's loop-detected response when the chain exceeds maxRedirectHops.
+// Non-redirect responses are returned unchanged.
+func prepareRedirectResponse(project *database.Project, response *database.MockResponse, req *http.Request) (*database.MockResponse, *http.Response) {
+	if response == nil || response.RedirectURL == "" {
+		return response, nil
+	}
+
+	hop := 0
+	if req != nil && req.URL != nil {
+		if value, err := strconv.Atoi(req.URL.Query().Get(redirectHopParam)); err == nil && value > 0 {
+			hop = value
+		}
+	}
+
+	if hop >= maxRedirectHops {
+		return nil, createLoopDetectedResponse(project, "Redirect loop detected: too many redirects")
+	}
+
+	redirect := *response
+	redirect.RedirectURL = withRedirectHop(response.RedirectURL, hop+1, req)
+	return &redirect, nil
+}
+
+// withRedirectHop appends the hop counter to locations that point back at this mock server.
+// External locations are left untouched so the counter doesn't leak to other services.
+func withRedirectHop(location string, hop int, req *http.Request) string {
+	target, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+
+	if target.Host != "" && (req == nil || target.Host != req.Host) {
+		return location
+	}
+
+	query := target.Query()
+	query.Set(redirectHopParam, strconv.Itoa(hop))
+	target.RawQuery = query.Encode()
+	return target.String()
+}
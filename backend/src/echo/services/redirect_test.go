@@ -0,0 +1,62 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_Redirect(t *testing.T) {
+	// Given - Redirect response without an explicit 3xx status
+	mockResp := database.MockResponse{
+		StatusCode:  200,
+		RedirectURL: "/orders/next",
+	}
+
+	// When - Create HTTP response
+	resp, err := createMockResponse(mockResp)
+
+	// Then - Status defaults to 302 and Location is set
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/orders/next", resp.Header.Get("Location"))
+}
+
+func TestPrepareRedirectResponse(t *testing.T) {
+	t.Run("First hop is counted on relative location", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://mock.local/start", nil)
+		response := &database.MockResponse{StatusCode: 301, RedirectURL: "/step-2?a=1"}
+
+		prepared, loopResp := prepareRedirectResponse(nil, response, req)
+
+		require.Nil(t, loopResp)
+		assert.Equal(t, "/step-2?a=1&beo-echo-redirect-hop=1", prepared.RedirectURL)
+		assert.Equal(t, "/step-2?a=1", response.RedirectURL, "original response must not be modified")
+	})
+
+	t.Run("External location is left untouched", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://mock.local/start", nil)
+		response := &database.MockResponse{RedirectURL: "https://example.com/login"}
+
+		prepared, loopResp := prepareRedirectResponse(nil, response, req)
+
+		require.Nil(t, loopResp)
+		assert.Equal(t, "https://example.com/login", prepared.RedirectURL)
+	})
+
+	t.Run("Too many hops returns loop detected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://mock.local/step?beo-echo-redirect-hop=10", nil)
+		response := &database.MockResponse{RedirectURL: "/step"}
+
+		prepared, loopResp := prepareRedirectResponse(nil, response, req)
+
+		assert.Nil(t, prepared)
+		require.NotNil(t, loopResp)
+		assert.Equal(t, http.StatusLoopDetected, loopResp.StatusCode)
+	})
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// requestBodyKey stores the request body cache in the request context
+type requestBodyKey struct{}
+
+// requestBodyCache holds a request body read once, and its JSON and form decodings parsed on first use,
+// so every rule evaluated for the request shares them instead of draining req.Body again
+type requestBodyCache struct {
+	bytes []byte
+	err   error
+
+	jsonOnce sync.Once
+	json     interface{}
+	jsonErr  error
+
+	formOnce sync.Once
+	form     *requestForm
+}
+
+// errRequestBodyTooLarge reports a request body over MAX_REQUEST_BODY_BYTES
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// maxRequestBodyBytes returns MAX_REQUEST_BODY_BYTES, 0 when bodies are unlimited
+func maxRequestBodyBytes() int64 {
+	if database.DB == nil {
+		return 0
+	}
+	limit, err := systemConfig.GetSystemConfigWithType[int](systemConfig.MAX_REQUEST_BODY_BYTES)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return int64(limit)
+}
+
+// readRequestBody reads the body of req, at most limit bytes of it (all when limit is 0). A
+// larger body, by Content-Length or once read past the limit, returns errRequestBodyTooLarge;
+// req.Body then still yields the whole body, including the bytes already read.
+func readRequestBody(req *http.Request, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		bodyBytes, err := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		return bodyBytes, err
+	}
+	if req.ContentLength > limit {
+		return nil, errRequestBodyTooLarge
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err == nil && int64(len(bodyBytes)) > limit {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(bodyBytes), req.Body), req.Body}
+		return nil, errRequestBodyTooLarge
+	}
+	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	return bodyBytes, err
+}
+
+// withRequestBody reads the body once and returns a request whose context carries the cache.
+// req.Body is restored so proxying and later reads still see the full body.
+func withRequestBody(req *http.Request) *http.Request {
+	if req == nil {
+		return nil
+	}
+
+	cache := &requestBodyCache{}
+	if req.Body != nil && req.Body != http.NoBody {
+		cache.bytes, cache.err = readRequestBody(req, maxRequestBodyBytes())
+	}
+	return req.WithContext(context.WithValue(req.Context(), requestBodyKey{}, cache))
+}
+
+// requestBodyTooLarge reports whether the cached body of req is over MAX_REQUEST_BODY_BYTES
+func requestBodyTooLarge(req *http.Request) bool {
+	cache, ok := req.Context().Value(requestBodyKey{}).(*requestBodyCache)
+	return ok && errors.Is(cache.err, errRequestBodyTooLarge)
+}
+
+// requestBodyBytes returns the request body, from the cache when withRequestBody ran and by
+// reading and restoring req.Body otherwise. A missing body is empty, not an error.
+func requestBodyBytes(req *http.Request) ([]byte, error) {
+	if cache, ok := req.Context().Value(requestBodyKey{}).(*requestBodyCache); ok {
+		return cache.bytes, cache.err
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	bodyBytes, err := readRequestBody(req, maxRequestBodyBytes())
+	if err != nil {
+		return nil, err
+	}
+	return bodyBytes, nil
+}
+
+// requestBodyJSON returns the decoded JSON body, parsed at most once per cached request
+func requestBodyJSON(req *http.Request) (interface{}, error) {
+	cache, ok := req.Context().Value(requestBodyKey{}).(*requestBodyCache)
+	if !ok {
+		bodyBytes, err := requestBodyBytes(req)
+		if err != nil {
+			return nil, err
+		}
+		var data interface{}
+		err = json.Unmarshal(bodyBytes, &data)
+		return data, err
+	}
+
+	cache.jsonOnce.Do(func() {
+		if cache.err != nil {
+			cache.jsonErr = cache.err
+			return
+		}
+		cache.jsonErr = json.Unmarshal(cache.bytes, &cache.json)
+	})
+	return cache.json, cache.jsonErr
+}
+
+// requestBodyObject returns the decoded body when it is a JSON object
+func requestBodyObject(req *http.Request) (map[string]interface{}, bool) {
+	data, err := requestBodyJSON(req)
+	if err != nil {
+		return nil, false
+	}
+	object, ok := data.(map[string]interface{})
+	return object, ok
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+// countingBody counts how often the body is drained to EOF
+type countingBody struct {
+	io.Reader
+	drained int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.drained++
+	}
+	return n, err
+}
+
+func (b *countingBody) Close() error { return nil }
+
+func TestWithRequestBody(t *testing.T) {
+	body := &countingBody{Reader: strings.NewReader(`{"user":{"id":"42","role":"admin"},"items":[{"sku":"A"}]}`)}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Body = body
+
+	req = withRequestBody(req)
+	response := database.MockResponse{Rules: []database.MockRule{
+		{Type: "body", Key: "user.id", Operator: "equals", Value: "42"},
+		{Type: "body", Key: "user.role", Operator: "equals", Value: "admin"},
+		{Type: "body", Key: "items[0].sku", Operator: "equals", Value: "A"},
+		{Type: "body", Operator: "not_empty"},
+		{Type: "body_hash", Operator: "equals", Value: "0000"},
+	}}
+
+	matchesRules(response, req)
+	assert.Equal(t, 1, body.drained, "the body is read once for all rules")
+
+	first, err := requestBodyJSON(req)
+	require.NoError(t, err)
+	second, _ := requestBodyJSON(req)
+	assert.Equal(t, first, second)
+
+	restored, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(restored), `"role":"admin"`, "req.Body stays readable for proxying")
+
+	t.Run("Transformed requests get their own cache", func(t *testing.T) {
+		endpoint := &database.MockEndpoint{AdvanceConfig: `{"bodyTransforms": [{"type": "unwrap", "field": "payload"}]}`}
+		req := withRequestBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"payload":"{\"id\":\"7\"}"}`)))
+
+		matchReq := transformRequestBody(endpoint, req)
+		assert.True(t, matchBodyRule(database.MockRule{Type: "body", Key: "id", Operator: "equals", Value: "7"}, matchReq))
+		assert.False(t, matchBodyRule(database.MockRule{Type: "body", Key: "id", Operator: "equals", Value: "7"}, req))
+	})
+
+	t.Run("Requests without a body", func(t *testing.T) {
+		req := withRequestBody(httptest.NewRequest(http.MethodGet, "/", nil))
+		bodyBytes, err := requestBodyBytes(req)
+		require.NoError(t, err)
+		assert.Empty(t, bodyBytes)
+		assert.True(t, isRequestBodyEmpty(req))
+	})
+}
+
+func TestReadRequestBody_Limit(t *testing.T) {
+	chunked := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.ContentLength = -1
+		return req
+	}
+
+	t.Run("Bodies within the limit are restored", func(t *testing.T) {
+		req := chunked("0123456789")
+		body, err := readRequestBody(req, 10)
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", string(body))
+		restored, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "0123456789", string(restored))
+	})
+
+	t.Run("Larger bodies are refused but kept whole", func(t *testing.T) {
+		req := chunked("0123456789abc")
+		_, err := readRequestBody(req, 10)
+		assert.ErrorIs(t, err, errRequestBodyTooLarge)
+		restored, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "0123456789abc", string(restored))
+	})
+
+	t.Run("Content-Length is checked before reading", func(t *testing.T) {
+		body := &countingBody{Reader: strings.NewReader("0123456789abc")}
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.ContentLength = 13
+		_, err := readRequestBody(req, 10)
+		assert.ErrorIs(t, err, errRequestBodyTooLarge)
+		assert.Equal(t, 0, body.drained)
+	})
+
+	t.Run("Zero is unlimited", func(t *testing.T) {
+		body, err := readRequestBody(chunked(strings.Repeat("x", 1000)), 0)
+		require.NoError(t, err)
+		assert.Len(t, body, 1000)
+	})
+}
+
+func TestHandleRequest_MaxRequestBodyBytes(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "body-limit")
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.MAX_REQUEST_BODY_BYTES, "16"))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.MAX_REQUEST_BODY_BYTES, "10485760") })
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/upload")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 201, Body: "stored"})
+
+	call := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/body-limit/upload", strings.NewReader(body))
+		req.ContentLength = -1
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodPost, "/upload", req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusCreated, call(`{"name": "ok"}`).StatusCode)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, call(`{"name": "far too long for the limit"}`).StatusCode)
+
+	t.Run("Proxied bodies are limited too", func(t *testing.T) {
+		forwarded := false
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forwarded = true
+		}))
+		defer upstream.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 17)))
+		resp, err := executeProxyRequest(context.Background(), upstream.URL, http.MethodPost, "/upload", "", req, proxyOptionsFor(project))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.False(t, forwarded)
+	})
+}
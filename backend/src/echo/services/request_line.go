@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// requestPathKey stores the project-relative request path in the request context
+type requestPathKey struct{}
+
+// withRequestPath returns a request whose context carries the path relative to the project
+// (alias prefix removed), as used for endpoint matching
+func withRequestPath(req *http.Request, path string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), requestPathKey{}, path))
+}
+
+// requestPath returns the path stored by withRequestPath, falling back to the URL path
+func requestPath(req *http.Request) string {
+	if path, ok := req.Context().Value(requestPathKey{}).(string); ok {
+		return path
+	}
+	return req.URL.Path
+}
+
+// requestLine reconstructs the request target as "METHOD /path?query", with the query kept
+// in the order the client sent it
+func requestLine(req *http.Request) string {
+	line := strings.ToUpper(req.Method) + " " + requestPath(req)
+	if req.URL.RawQuery != "" {
+		line += "?" + req.URL.RawQuery
+	}
+	return line
+}
+
+// matchRequestLineRule compares the reconstructed request line with the rule value,
+// e.g. equals "GET /search?q=foo" or contains "?q=foo"
+func matchRequestLineRule(rule database.MockRule, req *http.Request) bool {
+	return matchRuleValue(rule.Operator, requestLine(req), rule.Value)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchRequestLineRule(t *testing.T) {
+	req := withRequestPath(httptest.NewRequest(http.MethodGet, "/shop/search?q=foo&page=2", nil), "/search")
+
+	assert.Equal(t, "GET /search?q=foo&page=2", requestLine(req))
+	assert.True(t, matchRequestLineRule(database.MockRule{Operator: "equals", Value: "GET /search?q=foo&page=2"}, req))
+	assert.True(t, matchRequestLineRule(database.MockRule{Operator: "contains", Value: "GET /search?q=foo"}, req))
+	assert.False(t, matchRequestLineRule(database.MockRule{Operator: "contains", Value: "POST /search"}, req))
+
+	t.Run("Falls back to the URL path without a stored path", func(t *testing.T) {
+		plain := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+		assert.Equal(t, "DELETE /items/1", requestLine(plain))
+	})
+}
+
+func TestHandleRequest_RequestLineRule(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "request-line")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/search")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "results"})
+	foo := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "foo results", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: foo.ID, Type: "request_line", Operator: "equals", Value: "GET /search?q=foo"}).Error)
+
+	call := func(target string) string {
+		req := httptest.NewRequest(http.MethodGet, "/request-line"+target, nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/request-line/search", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, "foo results", call("/search?q=foo"))
+	assert.Equal(t, "results", call("/search?q=bar"))
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"beo-echo/backend/src/database"
+)
+
+// Global request counters per endpoint (endpointID -> *int64)
+var endpointRequestCounts sync.Map
+
+// requestSequenceKey stores the endpoint request number in the request context
+type requestSequenceKey struct{}
+
+// withRequestSequence counts the request against the endpoint and returns a request whose
+// context carries its 1-based arrival number for nth_request rules
+func withRequestSequence(endpointID string, req *http.Request) *http.Request {
+	value, _ := endpointRequestCounts.LoadOrStore(endpointID, new(int64))
+	sequence := atomic.AddInt64(value.(*int64), 1)
+	return req.WithContext(context.WithValue(req.Context(), requestSequenceKey{}, sequence))
+}
+
+// requestSequence returns the arrival number stored by withRequestSequence, 0 if none
+func requestSequence(req *http.Request) int64 {
+	sequence, _ := req.Context().Value(requestSequenceKey{}).(int64)
+	return sequence
+}
+
+// matchNthRequestRule compares the request's arrival number at the endpoint with the rule value,
+// e.g. equals "3" matches only the third request since the last reset
+func matchNthRequestRule(rule database.MockRule, req *http.Request) bool {
+	sequence := requestSequence(req)
+	if sequence == 0 {
+		return false
+	}
+	return matchRuleValue(rule.Operator, strconv.FormatInt(sequence, 10), rule.Value)
+}
+
+// matchCallIndexRule compares the 0-based index of the call to the endpoint with the rule value,
+// e.g. lt "3" matches the first three calls and gte "3" every later one (polling an async job)
+func matchCallIndexRule(rule database.MockRule, req *http.Request) bool {
+	sequence := requestSequence(req)
+	if sequence == 0 {
+		return false
+	}
+	return matchRuleValue(rule.Operator, strconv.FormatInt(sequence-1, 10), rule.Value)
+}
+
+// ResetEndpointState clears the per-endpoint runtime state: the request counter used by
+// nth_request and call_index rules, the round-robin position, the shuffle order and the
+// sequence position. Counters otherwise live until the server restarts.
+func ResetEndpointState(endpointID string) {
+	endpointRequestCounts.Delete(endpointID)
+	resetRoundRobinState(endpointID)
+	endpointShuffleStates.Delete(endpointID)
+	endpointSequenceStates.Delete(endpointID)
+}
+
+// SeedEndpointCallCount sets how many calls the endpoint has already received, so the next
+// call has call_index count (nth_request count+1)
+func SeedEndpointCallCount(endpointID string, count int64) {
+	value, _ := endpointRequestCounts.LoadOrStore(endpointID, new(int64))
+	atomic.StoreInt64(value.(*int64), count)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_NthRequestRule(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "nth-request")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/orders")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "regular"})
+	third := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 503, Body: "third", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: third.ID, Type: "nth_request", Operator: "equals", Value: "3"}).Error)
+
+	call := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/nth-request/orders", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/orders", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, []string{"regular", "regular", "third", "regular"}, []string{call(), call(), call(), call()})
+
+	// Reset starts counting again from the first request
+	ResetEndpointState(endpoint.ID)
+	assert.Equal(t, []string{"regular", "regular", "third"}, []string{call(), call(), call()})
+}
+
+func TestMatchNthRequestRule_WithoutSequence(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	rule := database.MockRule{Type: "nth_request", Operator: "equals", Value: "1"}
+
+	assert.False(t, matchNthRequestRule(rule, req), "requests not counted by an endpoint never match")
+	assert.True(t, matchNthRequestRule(rule, withRequestSequence("endpoint-nth", req)))
+}
+
+func TestHandleRequest_CallIndexRule(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "call-index")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/jobs/1")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 202, Body: "pending"})
+	complete := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "complete", Priority: 10})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: complete.ID, Type: "call_index", Operator: "gte", Value: "2"}).Error)
+
+	call := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/call-index/jobs/1", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/jobs/1", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, []string{"pending", "pending", "complete", "complete"}, []string{call(), call(), call(), call()})
+
+	t.Run("Reset starts polling over", func(t *testing.T) {
+		ResetEndpointState(endpoint.ID)
+		assert.Equal(t, "pending", call())
+	})
+
+	t.Run("Seeded counter skips earlier calls", func(t *testing.T) {
+		ResetEndpointState(endpoint.ID)
+		SeedEndpointCallCount(endpoint.ID, 2)
+		assert.Equal(t, "complete", call())
+	})
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// responseBuild is the state a mock response carries through the pipeline stages
+type responseBuild struct {
+	mock    database.MockResponse           // Selected response; stages may rewrite its Body
+	config  *database.AdvanceConfigResponse // Parsed advance config, empty when unset or invalid
+	headers map[string]string               // Configured headers
+	body    string                          // Body after templating and transforms, before encoding
+	payload []byte                          // Bytes sent to the client
+	resp    *http.Response                  // Set by the assemble stage
+
+	file     *os.File // Body file streamed as-is, set by the file stage
+	fileSize int64
+}
+
+// responseStage is one step of building a response. Each stage reads its own options from
+// the build's advance config and does nothing when they are not set.
+type responseStage struct {
+	name string
+	run  func(b *responseBuild) error
+}
+
+// defaultResponsePipeline turns a selected mock response into an HTTP response. Selection happens
+// before it (selectResponseWithEndpoint); throttling and delivery after it (respondWithDelay and
+// the mock handler). New response features plug in as a stage here rather than in createMockResponse.
+var defaultResponsePipeline = []responseStage{
+	{name: "file", run: fileStage},
+	{name: "template", run: templateStage},
+	{name: "transform", run: transformStage},
+	{name: "encode", run: encodeStage},
+	{name: "assemble", run: assembleStage},
+	{name: "fault", run: faultStage},
+}
+
+// runResponsePipeline runs the stages in order over the mock response
+func runResponsePipeline(stages []responseStage, mockResp database.MockResponse) (*http.Response, error) {
+	build := newResponseBuild(mockResp)
+	for _, stage := range stages {
+		if err := stage.run(build); err != nil {
+			return nil, fmt.Errorf("response %s stage: %w", stage.name, err)
+		}
+	}
+	if build.resp == nil {
+		return nil, fmt.Errorf("response pipeline has no assemble stage")
+	}
+	return build.resp, nil
+}
+
+// newResponseBuild parses the response's headers and advance config once for every stage
+func newResponseBuild(mockResp database.MockResponse) *responseBuild {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(mockResp.Headers), &headers); err != nil {
+		fmt.Println("Error unmarshalling headers:", err)
+		headers = make(map[string]string)
+	}
+
+	config, err := database.ParseResponseAdvanceConfig(mockResp.AdvanceConfig)
+	if err != nil {
+		config = &database.AdvanceConfigResponse{}
+	}
+
+	return &responseBuild{mock: mockResp, config: config, headers: headers, body: mockResp.Body}
+}
+
+// header returns a configured header value, matching the name case-insensitively
+func (b *responseBuild) header(name string) string {
+	for key, value := range b.headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// deleteHeader removes a configured header, matching the name case-insensitively
+func (b *responseBuild) deleteHeader(name string) {
+	for key := range b.headers {
+		if strings.EqualFold(key, name) {
+			delete(b.headers, key)
+		}
+	}
+}
+
+// templateStage produces the body: generated from bodySchema when set, otherwise the configured body
+func templateStage(b *responseBuild) error {
+	if generated, ok := generateSchemaBody(b.config.BodySchema); ok {
+		b.body = generated
+	}
+	return nil
+}
+
+// transformStage re-formats JSON bodies, then applies the malformed-body faults
+func transformStage(b *responseBuild) error {
+	b.body = formatJSONBody(b.body, b.config.JSONFormat)
+	b.body = malformBody(b.body, b.config)
+	return nil
+}
+
+// encodeStage compresses the body with the configured Content-Encoding list, in order.
+// A list with an unsupported encoding leaves the body as-is and drops the header, so the
+// response never claims an encoding that wasn't applied.
+func encodeStage(b *responseBuild) error {
+	if b.file != nil {
+		return nil
+	}
+	encodings := parseContentEncodings(strings.ToLower(b.header("Content-Encoding")))
+	encoded, ok, err := applyContentEncodings(encodings, []byte(b.body))
+	if err != nil {
+		return err
+	}
+	if ok {
+		b.payload = encoded
+	} else {
+		b.payload = []byte(b.body)
+		if len(encodings) > 0 {
+			fmt.Println("Unsupported Content-Encoding, sending the body unencoded:", b.header("Content-Encoding"))
+			b.deleteHeader("Content-Encoding")
+		}
+	}
+	return nil
+}
+
+// assembleStage builds the HTTP response: status, headers, Date, transfer encoding, cookies
+// and the redirect Location
+func assembleStage(b *responseBuild) error {
+	resp := &http.Response{
+		StatusCode:    b.mock.StatusCode,
+		Body:          io.NopCloser(bytes.NewReader(b.payload)),
+		Header:        make(http.Header),
+		ContentLength: int64(len(b.payload)),
+	}
+	if b.file != nil {
+		resp.Body = b.file
+		resp.ContentLength = b.fileSize
+	}
+
+	for key, value := range b.headers {
+		resp.Header.Set(key, value)
+	}
+
+	// Date header, optionally skewed for clients validating freshness
+	setResponseDate(resp, b.config.DateOffsetSeconds)
+
+	// A declared chunked transfer encoding is sent without a Content-Length
+	if strings.EqualFold(strings.TrimSpace(resp.Header.Get("Transfer-Encoding")), "chunked") {
+		resp.TransferEncoding = []string{"chunked"}
+		resp.ContentLength = -1
+		resp.Header.Del("Content-Length")
+	}
+
+	// Structured cookies become one Set-Cookie header each
+	cookies, err := database.ParseResponseCookies(b.mock.Cookies)
+	if err != nil {
+		fmt.Println("Error parsing cookies:", err)
+	}
+	for _, cookie := range cookies {
+		if value := cookie.HTTPCookie().String(); value != "" {
+			resp.Header.Add("Set-Cookie", value)
+		}
+	}
+
+	// Redirect responses always carry the configured Location
+	if b.mock.RedirectURL != "" {
+		if resp.StatusCode < 300 || resp.StatusCode > 399 {
+			resp.StatusCode = http.StatusFound
+		}
+		resp.Header.Set("Location", b.mock.RedirectURL)
+	}
+
+	b.resp = resp
+	return nil
+}
+
+// faultStage applies fault injection that needs the assembled response
+func faultStage(b *responseBuild) error {
+	// A body that doesn't match its Content-Encoding
+	return injectCompressionFault(b.config.CompressionFault, b.resp, []byte(b.body))
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestRunResponsePipeline(t *testing.T) {
+	mockResp := database.MockResponse{StatusCode: 201, Body: `{"id": 1}`, Headers: `{"X-Trace": "abc"}`, AdvanceConfig: `{"jsonFormat": "minify"}`}
+
+	t.Run("Stages run in order", func(t *testing.T) {
+		var order []string
+		record := func(name string) responseStage {
+			return responseStage{name: name, run: func(b *responseBuild) error {
+				order = append(order, name)
+				return nil
+			}}
+		}
+
+		stages := []responseStage{record("first"), {name: "transform", run: transformStage}, {name: "encode", run: encodeStage}, {name: "assemble", run: assembleStage}, record("last")}
+		resp, err := runResponsePipeline(stages, mockResp)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"first", "last"}, order)
+		assert.Equal(t, 201, resp.StatusCode)
+		assert.Equal(t, "abc", resp.Header.Get("X-Trace"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"id":1}`, string(body))
+	})
+
+	t.Run("Stage errors name the stage", func(t *testing.T) {
+		failing := responseStage{name: "encode", run: func(b *responseBuild) error { return errors.New("boom") }}
+
+		_, err := runResponsePipeline([]responseStage{failing}, mockResp)
+		assert.EqualError(t, err, "response encode stage: boom")
+	})
+
+	t.Run("A pipeline without assemble is rejected", func(t *testing.T) {
+		_, err := runResponsePipeline([]responseStage{{name: "transform", run: transformStage}}, mockResp)
+		assert.Error(t, err)
+	})
+}
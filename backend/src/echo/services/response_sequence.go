@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"beo-echo/backend/src/database"
+)
+
+// sequenceState is the position of an endpoint in its one-shot response sequence
+type sequenceState struct {
+	mu   sync.Mutex
+	next int // index of the next response to serve
+}
+
+// Global sequence state per endpoint (endpointID -> *sequenceState)
+var endpointSequenceStates sync.Map
+
+// sequencePolicyKey stores the endpoint's sequenceExhausted policy in the request context
+type sequencePolicyKey struct{}
+
+// withSequencePolicy returns a request whose context carries what sequence mode does once the
+// endpoint's responses are used up
+func withSequencePolicy(endpoint *database.MockEndpoint, req *http.Request) *http.Request {
+	if endpoint == nil || endpoint.AdvanceConfig == "" {
+		return req
+	}
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil || config.SequenceExhausted == "" {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), sequencePolicyKey{}, config.SequenceExhausted))
+}
+
+// sequencePolicy returns the policy stored by withSequencePolicy, repeat_last by default
+func sequencePolicy(req *http.Request) string {
+	if req != nil {
+		if policy, ok := req.Context().Value(sequencePolicyKey{}).(string); ok {
+			return policy
+		}
+	}
+	return database.SequenceExhaustedRepeatLast
+}
+
+// getNextSequenceResponse serves the responses once each in priority order. Unlike round robin it
+// does not wrap: once exhausted it keeps serving the last response, or a 410 Gone when the
+// endpoint's sequenceExhausted policy is "gone". Safe for concurrent requests.
+func getNextSequenceResponse(endpointID string, responses []database.MockResponse, req *http.Request) *database.MockResponse {
+	if len(responses) == 0 {
+		return nil
+	}
+
+	// Work on a priority-sorted copy so positions are stable between requests
+	sortedResponses := make([]database.MockResponse, len(responses))
+	copy(sortedResponses, responses)
+	sortByPriority(sortedResponses)
+
+	val, _ := endpointSequenceStates.LoadOrStore(endpointID, &sequenceState{})
+	state := val.(*sequenceState)
+
+	state.mu.Lock()
+	index := state.next
+	if index < len(sortedResponses) {
+		state.next++
+	}
+	state.mu.Unlock()
+
+	if index < len(sortedResponses) {
+		return &sortedResponses[index]
+	}
+	if sequencePolicy(req) == database.SequenceExhaustedGone {
+		return &database.MockResponse{
+			EndpointID: endpointID,
+			StatusCode: http.StatusGone,
+			Headers:    `{"Content-Type":"application/json"}`,
+			Body:       `{"error":true,"message":"Response sequence exhausted"}`,
+		}
+	}
+	return &sortedResponses[len(sortedResponses)-1]
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestGetNextSequenceResponse(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "c", Priority: 1},
+		{ID: "a", Priority: 3},
+		{ID: "b", Priority: 2},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	t.Run("Plays in priority order then repeats the last", func(t *testing.T) {
+		t.Cleanup(func() { ResetEndpointState("sequence-repeat") })
+
+		var served []string
+		for i := 0; i < 5; i++ {
+			served = append(served, getNextSequenceResponse("sequence-repeat", responses, req).ID)
+		}
+		assert.Equal(t, []string{"a", "b", "c", "c", "c"}, served)
+	})
+
+	t.Run("Gone policy answers 410 after the last response", func(t *testing.T) {
+		t.Cleanup(func() { ResetEndpointState("sequence-gone") })
+
+		endpoint := &database.MockEndpoint{AdvanceConfig: `{"sequenceExhausted": "gone"}`}
+		goneReq := withSequencePolicy(endpoint, req)
+		for _, id := range []string{"a", "b", "c"} {
+			assert.Equal(t, id, getNextSequenceResponse("sequence-gone", responses, goneReq).ID)
+		}
+		exhausted := getNextSequenceResponse("sequence-gone", responses, goneReq)
+		assert.Equal(t, http.StatusGone, exhausted.StatusCode)
+	})
+
+	t.Run("Reset restarts the sequence", func(t *testing.T) {
+		t.Cleanup(func() { ResetEndpointState("sequence-reset") })
+
+		getNextSequenceResponse("sequence-reset", responses, req)
+		getNextSequenceResponse("sequence-reset", responses, req)
+		ResetEndpointState("sequence-reset")
+		assert.Equal(t, "a", getNextSequenceResponse("sequence-reset", responses, req).ID)
+	})
+}
+
+func TestHandleRequest_SequenceMode(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "sequence-mode")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/checkout")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Updates(map[string]interface{}{
+		"response_mode":  "sequence",
+		"advance_config": `{"sequenceExhausted": "gone"}`,
+	}).Error)
+	t.Cleanup(func() { ResetEndpointState(endpoint.ID) })
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 202, Body: "pending", Priority: 2})
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "done", Priority: 1})
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/sequence-mode/checkout", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodPost, "/checkout", req)
+		require.NoError(t, err)
+		io.Copy(io.Discard, resp.Body)
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{http.StatusAccepted, http.StatusOK, http.StatusGone}, statuses)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"beo-echo/backend/src/database"
+)
+
+// templatePlaceholderFunc is appended to every printing action of a response template, so
+// missing map keys and nil values print as the templatePlaceholder instead of "<no value>"
+const templatePlaceholderFunc = "_placeholder"
+
+// responseTemplateData is the context templated responses render with, e.g.
+// {{.Path.id}}, {{.Query.page}}, {{index .Headers "X-Request-Id"}} or {{.Body.user.name}}
+type responseTemplateData struct {
+	Method       string
+	URL          string            // Project-relative path with the query string
+	Path         map[string]string // Path parameters captured by :name / {name} segments of the endpoint path
+	Query        map[string]string // First value of each query parameter
+	Headers      map[string]string // First value of each request header, by canonical name
+	Body         interface{}       // Parsed JSON body, an empty object when it isn't JSON
+	RawBody      string            // Body as sent
+	MatchedRules []matchedRuleView // Rules that selected the response, e.g. {{range .MatchedRules}}{{.Key}}{{end}}
+
+	projectID string         // Scopes the fixture and counter functions
+	session   *sessionScope  // Session read and written by the session functions, nil without one
+	random    templateRandom // Source of the fake data helpers
+}
+
+// renderResponseTemplate renders the body and redirect URL of a response flagged "templated"
+// with Go text/template. {{fixture.<key>}} placeholders of the body work as {{fixture "<key>"}}.
+// Unresolved variables render as the response's templatePlaceholder (empty by default). A template that fails to parse or execute leaves the text unchanged.
+func renderResponseTemplate(endpoint *database.MockEndpoint, response *database.MockResponse, req *http.Request) *database.MockResponse {
+	if response == nil || req == nil {
+		return response
+	}
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err != nil || !config.Templated {
+		return response
+	}
+
+	data := newResponseTemplateData(endpoint, response, req)
+	rendered := *response
+	rendered.Body = executeResponseTemplate(fixturePlaceholdersToCalls(response.Body), data, config.TemplatePlaceholder)
+	rendered.RedirectURL = executeResponseTemplate(response.RedirectURL, data, config.TemplatePlaceholder)
+	return &rendered
+}
+
+// newResponseTemplateData collects the request values exposed to templates
+func newResponseTemplateData(endpoint *database.MockEndpoint, response *database.MockResponse, req *http.Request) responseTemplateData {
+	data := responseTemplateData{
+		Method:    req.Method,
+		URL:       strings.TrimPrefix(requestLine(req), strings.ToUpper(req.Method)+" "),
+		Path:      pathParams(req, endpoint),
+		Query:     map[string]string{},
+		Headers:   map[string]string{},
+		Body:      map[string]interface{}{},
+		projectID: fixtureProject(req),
+		session:   requestSession(req),
+		random:    templateRandom{seeded: seededRequestRand(req)},
+	}
+
+	for key, values := range req.URL.Query() {
+		if len(values) > 0 {
+			data.Query[key] = values[0]
+		}
+	}
+	for key, values := range req.Header {
+		if len(values) > 0 {
+			data.Headers[key] = values[0]
+		}
+	}
+	if bodyBytes, err := requestBodyBytes(req); err == nil {
+		data.RawBody = string(bodyBytes)
+		if body, err := requestBodyJSON(req); err == nil {
+			data.Body = body
+		}
+	}
+	data.MatchedRules = matchedRuleViews(*response, req)
+	return data
+}
+
+// executeResponseTemplate renders text, returning it unchanged when it is not a valid template
+func executeResponseTemplate(text string, data responseTemplateData, placeholder string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	funcs := responseTemplateFuncs(data)
+	funcs[templatePlaceholderFunc] = func(value interface{}) interface{} {
+		if value == nil {
+			return placeholder
+		}
+		return value
+	}
+	tmpl, err := template.New("response").Funcs(funcs).Parse(text)
+	if err != nil {
+		fmt.Println("Error parsing response template:", err)
+		return text
+	}
+	pipePlaceholders(tmpl.Tree, tmpl.Tree.Root)
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		fmt.Println("Error rendering response template:", err)
+		return text
+	}
+	return out.String()
+}
+
+// pipePlaceholders pipes the printed value of every action through templatePlaceholderFunc.
+// Only values are replaced, never the rendered text, so request data containing "<no value>"
+// comes back as sent.
+func pipePlaceholders(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			pipePlaceholders(tree, child)
+		}
+	case *parse.ActionNode:
+		// Assignments like {{$x := .Body}} print nothing
+		if len(n.Pipe.Decl) == 0 {
+			identifier := parse.NewIdentifier(templatePlaceholderFunc).SetTree(tree).SetPos(n.Pos)
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos, Args: []parse.Node{identifier}})
+		}
+	case *parse.IfNode:
+		pipePlaceholders(tree, n.List)
+		pipePlaceholders(tree, n.ElseList)
+	case *parse.RangeNode:
+		pipePlaceholders(tree, n.List)
+		pipePlaceholders(tree, n.ElseList)
+	case *parse.WithNode:
+		pipePlaceholders(tree, n.List)
+		pipePlaceholders(tree, n.ElseList)
+	}
+}
+
+// responseTemplateFuncs are the helper functions available in response templates: the request
+// helpers below plus the fake data helpers of fakerTemplateFuncs
+func responseTemplateFuncs(data responseTemplateData) template.FuncMap {
+	funcs := fakerTemplateFuncs(data)
+	// fixture reads a value from the project's fixture store: {{fixture "cart"}}
+	funcs["fixture"] = func(key string) string {
+		value, _, _ := GetFixture(data.projectID, key)
+		return value
+	}
+	// session reads a value of the request's session: {{session "status"}}
+	funcs["session"] = func(name string) string { return data.session.get(name) }
+	// sessionSet writes a value of the request's session and renders nothing:
+	// {{sessionSet "status" "active"}} or {{sessionSet "user" .Body.name}}
+	funcs["sessionSet"] = func(name string, value interface{}) string {
+		data.session.set(name, stringifyJSONValue(value))
+		return ""
+	}
+	// json encodes a value, e.g. {{json .Body.items}}
+	funcs["json"] = func(value interface{}) string { return stringifyJSONValue(value) }
+	return funcs
+}
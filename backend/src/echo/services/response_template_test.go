@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestRenderResponseTemplate(t *testing.T) {
+	endpoint := &database.MockEndpoint{Path: "/users/:id"}
+	newReq := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/users/42?page=2", strings.NewReader(body))
+		req.Header.Set("X-Request-Id", "req-1")
+		return withRequestPath(req, "/users/42")
+	}
+	render := func(body, config string, req *http.Request) string {
+		response := &database.MockResponse{Body: body, AdvanceConfig: config}
+		return renderResponseTemplate(endpoint, response, req).Body
+	}
+
+	t.Run("Request values", func(t *testing.T) {
+		body := `{"id":"{{.Path.id}}","page":"{{.Query.page}}","trace":"{{index .Headers "X-Request-Id"}}","name":"{{.Body.user.name}}","url":"{{.URL}}","method":"{{.Method}}"}`
+		rendered := render(body, `{"templated": true}`, newReq(`{"user":{"name":"Ada"}}`))
+		assert.JSONEq(t, `{"id":"42","page":"2","trace":"req-1","name":"Ada","url":"/users/42?page=2","method":"POST"}`, rendered)
+	})
+
+	t.Run("Unresolved variables render empty or as the placeholder", func(t *testing.T) {
+		body := `{{.Query.missing}}|{{.Body.nope}}|{{.Path.other}}|{{.Body.user.name}}`
+		assert.Equal(t, "|||", render(body, `{"templated": true}`, newReq(`{}`)))
+		assert.Equal(t, "?|?|?|?", render(body, `{"templated": true, "templatePlaceholder": "?"}`, newReq(`not json`)))
+	})
+
+	t.Run("Helpers and matched rules", func(t *testing.T) {
+		body := `{{json .Body.items}} {{range .MatchedRules}}{{.Key}}{{end}}`
+		req := newReq(`{"items":[1,2]}`)
+		response := &database.MockResponse{
+			Body:          body,
+			AdvanceConfig: `{"templated": true}`,
+			Rules:         []database.MockRule{{Type: "query", Key: "page", Operator: "equals", Value: "2"}},
+		}
+		assert.Equal(t, "[1,2] page", renderResponseTemplate(endpoint, response, req).Body)
+	})
+
+	t.Run("Request values are output, never template source", func(t *testing.T) {
+		body := `{"note":"{{.Request}} {{index .Headers \"X-Request-Id\"}} <no value>"}`
+		rendered := render(`{{.Body.note}}|{{.RawBody}}`, `{"templated": true, "templatePlaceholder": "?"}`, newReq(body))
+		assert.Equal(t, `{{.Request}} {{index .Headers "X-Request-Id"}} <no value>|`+body, rendered)
+	})
+
+	t.Run("Redirect URL is rendered too", func(t *testing.T) {
+		response := &database.MockResponse{RedirectURL: "/profiles/{{.Path.id}}", AdvanceConfig: `{"templated": true}`}
+		assert.Equal(t, "/profiles/42", renderResponseTemplate(endpoint, response, newReq("")).RedirectURL)
+	})
+
+	t.Run("Untemplated and invalid templates are left as is", func(t *testing.T) {
+		assert.Equal(t, "{{.Path.id}}", render("{{.Path.id}}", "", newReq("")))
+		assert.Equal(t, "{{.Path.id", render("{{.Path.id", `{"templated": true}`, newReq("")))
+	})
+}
+
+func TestHandleRequest_TemplatedResponse(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "templated")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/orders/:orderId")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"orderId":"{{.Path.orderId}}","currency":"{{.Query.currency}}"}`,
+		AdvanceConfig: `{"templated": true}`,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/templated/orders/ord-7?currency=IDR", nil)
+	resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/templated/orders/ord-7", req)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"orderId":"ord-7","currency":"IDR"}`, string(body))
+}
+
+func TestHandleRequest_TemplatedResponseEchoesRequestText(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "templated-echo")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "POST", "/notes")
+	require.NoError(t, err)
+	response := createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		Body:          `{{.Body.note}}|{{range .MatchedRules}}{{.Value}}{{end}}|{{fixture.note}}`,
+		AdvanceConfig: `{"templated": true, "fixtureSet": {"note": "{{request.body.note}}"}}`,
+	})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: response.ID, Type: "body", Key: "note", Operator: "contains", Value: "{{.Request}}"}).Error)
+
+	req := httptest.NewRequest(http.MethodPost, "/templated-echo/notes", strings.NewReader(`{"note": "{{.Request}}"}`))
+	resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodPost, "/notes", req)
+	require.NoError(t, err)
+	assert.True(t, matched)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "{{.Request}}|{{.Request}}|{{.Request}}", string(body), "body, matched rule and fixture values come back verbatim")
+}
+
+func TestHandleRequest_TemplatedPathParams(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "path-params")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/users/{userId}/orders/:orderId")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{
+		StatusCode:    200,
+		Body:          `{"user":"{{.Path.userId}}","order":"{{.Path.orderId}}"}`,
+		AdvanceConfig: `{"templated": true}`,
+	})
+	static, err := database.CreateTestEndpoint(project.ID, "GET", "/users/me/orders/latest")
+	require.NoError(t, err)
+	createTestResponse(t, static.ID, database.MockResponse{StatusCode: 200, Body: `{"static":true}`})
+
+	get := func(path string) string {
+		req := httptest.NewRequest(http.MethodGet, "/path-params"+path, nil)
+		resp, err, _, _, matched := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, path, req)
+		require.NoError(t, err)
+		assert.True(t, matched)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.JSONEq(t, `{"user":"u-1","order":"ord-9"}`, get("/users/u-1/orders/ord-9"))
+	assert.JSONEq(t, `{"static":true}`, get("/users/me/orders/latest"))
+}
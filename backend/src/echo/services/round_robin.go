@@ -1,16 +1,23 @@
 package services
 
 import (
-	"beo-echo/backend/src/database"
 	"sync"
 	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
 )
 
-// endpointState holds round-robin state for each endpoint
-// NOTE: This version is intentionally not thread-safe — acceptable for mock/testing use where race conditions are tolerable.
+// endpointState holds round-robin state for each endpoint. The mutex makes advancing the
+// position atomic, so concurrent requests never receive the same slot twice.
 type endpointState struct {
-	lastIndex int   // index of the last response served
-	lastUsed  int64 // Unix timestamp of last usage
+	mu       sync.Mutex
+	counter  int64 // requests served so far
+	lastUsed int64 // Unix timestamp of last usage
 }
 
 // Global state map for round-robin selection per endpoint
@@ -19,7 +26,8 @@ var endpointStates sync.Map
 // State timeout for lazy cleanup (1 hour)
 const stateTimeout = int64(3600)
 
-// getNextRoundRobinResponse implements simple round-robin selection per endpoint
+// getNextRoundRobinResponse implements simple round-robin selection per endpoint.
+// With ROUND_ROBIN_PERSISTENT on, the counter lives in the database instead of memory.
 func getNextRoundRobinResponse(endpointID string, responses []database.MockResponse) database.MockResponse {
 	if len(responses) == 0 {
 		// This should not happen as we check for empty responses elsewhere,
@@ -34,32 +42,77 @@ func getNextRoundRobinResponse(endpointID string, responses []database.MockRespo
 	// Sort by priority (higher priority first)
 	sortByPriority(sortedResponses)
 
-	now := time.Now().Unix()
+	var counter int64
+	if roundRobinPersistent() {
+		persisted, err := nextPersistedRoundRobinCounter(endpointID)
+		if err != nil {
+			// The in-memory counter keeps this replica rotating, out of step with the others
+			log.Error().Err(err).Str("endpoint_id", endpointID).Msg("failed to advance persisted round-robin counter, using the in-memory one")
+			counter = nextRoundRobinCounter(endpointID)
+		} else {
+			counter = persisted
+		}
+	} else {
+		counter = nextRoundRobinCounter(endpointID)
+	}
 
-	// Load or initialize endpoint state
-	val, _ := endpointStates.LoadOrStore(endpointID, &endpointState{
-		lastIndex: -1, // Start with -1 so first request gets index 0
-		lastUsed:  now,
-	})
-	state := val.(*endpointState)
+	// cleanup stale endpoints
+	cleanupStaleEndpoints()
+
+	// Counters start at 1, so the first request gets index 0
+	return sortedResponses[(counter-1)%int64(len(sortedResponses))]
+}
 
-	// Update last used time
-	state.lastUsed = now
+// nextRoundRobinCounter advances the in-memory counter of the endpoint and returns it
+func nextRoundRobinCounter(endpointID string) int64 {
+	val, _ := endpointStates.LoadOrStore(endpointID, &endpointState{})
+	state := val.(*endpointState)
 
-	// Get next index (round-robin)
-	nextIndex := state.lastIndex + 1
-	if nextIndex >= len(sortedResponses) {
-		nextIndex = 0 // Wrap around
-	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.counter++
+	state.lastUsed = time.Now().Unix()
+	return state.counter
+}
 
-	// Update state with the new index
-	state.lastIndex = nextIndex
+// nextPersistedRoundRobinCounter increments the endpoint's stored counter and returns the new
+// value. The upsert and read share a transaction so replicas on one database never collide.
+func nextPersistedRoundRobinCounter(endpointID string) (int64, error) {
+	var counter int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		state := database.RoundRobinState{EndpointID: endpointID, Counter: 1}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "endpoint_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				// Qualified, as Postgres also has EXCLUDED.counter in scope here
+				"counter":    gorm.Expr("round_robin_states.counter + 1"),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&state).Error; err != nil {
+			return err
+		}
+		return tx.Model(&database.RoundRobinState{}).
+			Where("endpoint_id = ?", endpointID).
+			Pluck("counter", &counter).Error
+	})
+	return counter, err
+}
 
-	// cleanup stale endpoints
-	cleanupStaleEndpoints()
+// roundRobinPersistent reports whether ROUND_ROBIN_PERSISTENT is on
+func roundRobinPersistent() bool {
+	if database.DB == nil {
+		return false
+	}
+	enabled, err := systemConfig.GetSystemConfigWithType[bool](systemConfig.ROUND_ROBIN_PERSISTENT)
+	return err == nil && enabled
+}
 
-	// Return the selected response from sorted array
-	return sortedResponses[nextIndex]
+// resetRoundRobinState clears the endpoint's in-memory and persisted round-robin position
+func resetRoundRobinState(endpointID string) {
+	endpointStates.Delete(endpointID)
+	if database.DB != nil {
+		database.DB.Where("endpoint_id = ?", endpointID).Delete(&database.RoundRobinState{})
+	}
 }
 
 // cleanupStaleEndpoints removes entries from endpointStates that haven't been used within stateTimeout
@@ -67,7 +120,10 @@ func cleanupStaleEndpoints() {
 	now := time.Now().Unix()
 	endpointStates.Range(func(key, value any) bool {
 		state := value.(*endpointState)
-		if now-state.lastUsed > stateTimeout {
+		state.mu.Lock()
+		stale := now-state.lastUsed > stateTimeout
+		state.mu.Unlock()
+		if stale {
 			endpointStates.Delete(key)
 		}
 		return true
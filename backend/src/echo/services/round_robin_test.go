@@ -2,6 +2,7 @@ package services
 
 import (
 	"beo-echo/backend/src/database"
+	systemConfig "beo-echo/backend/src/systemConfigs"
 	"sync"
 	"testing"
 )
@@ -216,3 +217,75 @@ func TestRoundRobinSequentialCalls(t *testing.T) {
 		}
 	})
 }
+
+func TestRoundRobinConcurrentCalls(t *testing.T) {
+	// Clear any existing state before testing
+	endpointStates = sync.Map{}
+
+	endpointID := "concurrent-endpoint"
+	responses := []database.MockResponse{
+		{Body: "1", Priority: 3},
+		{Body: "2", Priority: 2},
+		{Body: "3", Priority: 1},
+	}
+
+	const goroutines = 50
+	const callsPerGoroutine = 60
+
+	var mu sync.Mutex
+	counts := map[string]int{}
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := map[string]int{}
+			for i := 0; i < callsPerGoroutine; i++ {
+				local[getNextRoundRobinResponse(endpointID, responses).Body]++
+			}
+			mu.Lock()
+			for body, count := range local {
+				counts[body] += count
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Every slot is handed out exactly once, so the distribution is perfectly even
+	expected := goroutines * callsPerGoroutine / len(responses)
+	for _, response := range responses {
+		if counts[response.Body] != expected {
+			t.Errorf("Response %s: expected %d calls, got %d", response.Body, expected, counts[response.Body])
+		}
+	}
+}
+
+func TestRoundRobinPersistent(t *testing.T) {
+	database.SetupTestEnvironment(t)
+	if err := systemConfig.SetSystemConfig(systemConfig.ROUND_ROBIN_PERSISTENT, "true"); err != nil {
+		t.Fatalf("enable persistence: %v", err)
+	}
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.ROUND_ROBIN_PERSISTENT, "false") })
+
+	endpointID := "persistent-endpoint"
+	responses := []database.MockResponse{
+		{Body: "1", Priority: 2},
+		{Body: "2", Priority: 1},
+	}
+
+	if body := getNextRoundRobinResponse(endpointID, responses).Body; body != "1" {
+		t.Errorf("Call 1: expected body '1', got '%s'", body)
+	}
+
+	// A restart (or another replica) has no in-memory state but continues from the database
+	endpointStates = sync.Map{}
+	if body := getNextRoundRobinResponse(endpointID, responses).Body; body != "2" {
+		t.Errorf("Call 2 after restart: expected body '2', got '%s'", body)
+	}
+
+	ResetEndpointState(endpointID)
+	if body := getNextRoundRobinResponse(endpointID, responses).Body; body != "1" {
+		t.Errorf("Call after reset: expected body '1', got '%s'", body)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchesRules_RuleLogic(t *testing.T) {
+	rules := []database.MockRule{
+		{Type: "header", Key: "X-Beta", Operator: "equals", Value: "1"},
+		{Type: "query", Key: "beta", Operator: "equals", Value: "1"},
+	}
+	request := func(header, query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/features?beta="+query, nil)
+		req.Header.Set("X-Beta", header)
+		return req
+	}
+
+	t.Run("All is the default", func(t *testing.T) {
+		for _, logic := range []string{"", "all", "ALL"} {
+			response := database.MockResponse{Rules: rules, RuleLogic: logic}
+			assert.True(t, matchesRules(response, request("1", "1")))
+			assert.False(t, matchesRules(response, request("1", "0")), "logic %q", logic)
+			assert.False(t, matchesRules(response, request("0", "1")), "logic %q", logic)
+		}
+	})
+
+	t.Run("Any matches when one rule passes", func(t *testing.T) {
+		response := database.MockResponse{Rules: rules, RuleLogic: "any"}
+		assert.True(t, matchesRules(response, request("1", "0")))
+		assert.True(t, matchesRules(response, request("0", "1")))
+		assert.True(t, matchesRules(response, request("1", "1")))
+		assert.False(t, matchesRules(response, request("0", "0")))
+	})
+
+	t.Run("Rules checked elsewhere don't make any match", func(t *testing.T) {
+		response := database.MockResponse{RuleLogic: "any", Rules: append([]database.MockRule{{Type: "path", Key: "id", Operator: "equals", Value: "7"}}, rules...)}
+		assert.False(t, matchesRules(response, request("0", "0")))
+
+		pathOnly := database.MockResponse{RuleLogic: "any", Rules: []database.MockRule{{Type: "path", Key: "id", Operator: "equals", Value: "7"}}}
+		assert.True(t, matchesRules(pathOnly, request("0", "0")))
+	})
+
+	t.Run("Matched rules only list the passing ones", func(t *testing.T) {
+		response := database.MockResponse{Rules: rules, RuleLogic: "any"}
+		assert.Equal(t, rules[1:], matchedRules(response, request("0", "1")))
+	})
+}
+
+func TestHandleRequest_AnyRuleLogic(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "rule-logic")
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/features")
+	require.NoError(t, err)
+	require.NoError(t, database.DB.Model(endpoint).Update("response_mode", "static").Error)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "stable"})
+	beta := createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "beta", Priority: 10, RuleLogic: database.RuleLogicAny})
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: beta.ID, Type: "header", Key: "X-Beta", Operator: "equals", Value: "1"}).Error)
+	require.NoError(t, database.DB.Create(&database.MockRule{ResponseID: beta.ID, Type: "query", Key: "beta", Operator: "equals", Value: "1"}).Error)
+
+	call := func(target string, headers map[string]string) string {
+		req := httptest.NewRequest(http.MethodGet, "/rule-logic"+target, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, target, req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, "beta", call("/features", map[string]string{"X-Beta": "1"}))
+	assert.Equal(t, "beta", call("/features?beta=1", nil))
+	assert.Equal(t, "stable", call("/features", nil))
+}
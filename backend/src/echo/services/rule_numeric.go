@@ -0,0 +1,32 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+)
+
+// matchRuleNumber compares actual with expected as numbers for the gt, gte, lt and lte operators.
+// Either side failing to parse as a float means no match.
+func matchRuleNumber(operator, actual, expected string) bool {
+	actualNumber, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	if err != nil {
+		return false
+	}
+	expectedNumber, err := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+	if err != nil {
+		return false
+	}
+
+	switch operator {
+	case "gt":
+		return actualNumber > expectedNumber
+	case "gte":
+		return actualNumber >= expectedNumber
+	case "lt":
+		return actualNumber < expectedNumber
+	case "lte":
+		return actualNumber <= expectedNumber
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"regexp"
+	"sync"
+)
+
+// compiledRuleRegexes caches compiled rule patterns (pattern -> *regexp.Regexp, nil when invalid)
+// so each pattern is compiled once instead of on every request
+var compiledRuleRegexes sync.Map
+
+// ruleRegex returns the compiled pattern, nil when it doesn't compile
+func ruleRegex(pattern string) *regexp.Regexp {
+	if cached, ok := compiledRuleRegexes.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		compiled = nil
+	}
+	compiledRuleRegexes.Store(pattern, compiled)
+	return compiled
+}
+
+// matchRuleRegex reports whether actual matches the pattern; invalid patterns never match
+func matchRuleRegex(pattern, actual string) bool {
+	compiled := ruleRegex(pattern)
+	return compiled != nil && compiled.MatchString(actual)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// scenarioHeader names the scenario a request runs under, e.g. "beo-echo-scenario: checkout-failure"
+const scenarioHeader = "beo-echo-scenario"
+
+// requestScenario returns the scenario requested by the client, "" when none
+func requestScenario(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	return strings.TrimSpace(req.Header.Get(scenarioHeader))
+}
+
+// filterResponsesByScenario narrows responses to the requested scenario. Responses tagged with
+// the scenario win; endpoints without any for it (or requests without the header) use the
+// untagged responses, so one header switches every endpoint that takes part in the scenario.
+func filterResponsesByScenario(responses []database.MockResponse, scenario string) []database.MockResponse {
+	return filterResponsesByTag(responses, responseScenarios, scenario)
+}
+
+// responseScenarios returns the scenario tags of a response
+func responseScenarios(response database.MockResponse) []string {
+	if response.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err != nil {
+		return nil
+	}
+	return config.Scenarios
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_ScenarioHeader(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "scenario")
+
+	cart, err := database.CreateTestEndpoint(project.ID, "GET", "/cart")
+	require.NoError(t, err)
+	createTestResponse(t, cart.ID, database.MockResponse{StatusCode: 200, Body: "cart"})
+
+	checkout, err := database.CreateTestEndpoint(project.ID, "POST", "/checkout")
+	require.NoError(t, err)
+	createTestResponse(t, checkout.ID, database.MockResponse{StatusCode: 200, Body: "paid"})
+	createTestResponse(t, checkout.ID, database.MockResponse{StatusCode: 402, Body: "declined", AdvanceConfig: `{"scenarios": ["checkout-failure"]}`})
+
+	call := func(method, path, scenario string) (int, string) {
+		req := httptest.NewRequest(method, "/scenario"+path, nil)
+		if scenario != "" {
+			req.Header.Set("beo-echo-scenario", scenario)
+		}
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, method, path, req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := call(http.MethodPost, "/checkout", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "paid", body, "scenario responses are not served without the header")
+
+	status, body = call(http.MethodPost, "/checkout", "Checkout-Failure")
+	assert.Equal(t, http.StatusPaymentRequired, status)
+	assert.Equal(t, "declined", body)
+
+	_, body = call(http.MethodGet, "/cart", "checkout-failure")
+	assert.Equal(t, "cart", body, "endpoints outside the scenario keep their default responses")
+
+	outage, err := database.CreateTestEndpoint(project.ID, "GET", "/status")
+	require.NoError(t, err)
+	createTestResponse(t, outage.ID, database.MockResponse{StatusCode: 503, Body: "down", AdvanceConfig: `{"scenarios": ["outage"]}`})
+
+	status, body = call(http.MethodGet, "/status", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotEqual(t, "down", body, "scenario-only endpoints don't serve the scenario without the header")
+}
+
+func TestHandleProxyMode_ScenarioHeader(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "scenario-proxy")
+
+	var forwarded http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+		w.Write([]byte("upstream"))
+	}))
+	defer upstream.Close()
+	project.ActiveProxy = &database.ProxyTarget{URL: upstream.URL}
+
+	checkout, err := database.CreateTestEndpoint(project.ID, "POST", "/checkout")
+	require.NoError(t, err)
+	createTestResponse(t, checkout.ID, database.MockResponse{StatusCode: 402, Body: "declined", AdvanceConfig: `{"scenarios": ["checkout-failure"]}`})
+
+	call := func(path string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("beo-echo-scenario", "checkout-failure")
+		resp, _, err := service.handleProxyMode(context.Background(), project, http.MethodPost, path, req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := call("/checkout")
+	assert.Equal(t, http.StatusPaymentRequired, status)
+	assert.Equal(t, "declined", body)
+
+	status, body = call("/orders")
+	assert.Equal(t, http.StatusOK, status, "scenario-tagged requests aren't proxy loops")
+	assert.Equal(t, "upstream", body)
+	assert.Empty(t, forwarded.Get("beo-echo-scenario"), "control headers aren't forwarded")
+	assert.Equal(t, "true", forwarded.Get("beo-echo-loop-detect"))
+
+	t.Run("Forwarded requests coming back are loops", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("beo-echo-loop-detect", "true")
+		resp, _, err := service.handleProxyMode(context.Background(), project, http.MethodPost, "/orders", req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusLoopDetected, resp.StatusCode)
+	})
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"time"
+	_ "time/tzdata" // Schedules name IANA zones; embed them for hosts without a zoneinfo database
+
+	"beo-echo/backend/src/database"
+)
+
+// responseSchedule returns the schedule of a response, nil when it is always selectable
+func responseSchedule(response database.MockResponse) *database.ResponseSchedule {
+	if response.AdvanceConfig == "" {
+		return nil
+	}
+	config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig)
+	if err != nil {
+		return nil
+	}
+	return config.Schedule
+}
+
+// filterResponsesBySchedule drops responses scheduled outside of now. When a scheduled response
+// is in its window only the scheduled ones are kept, so a maintenance response replaces the
+// regular ones while it lasts.
+func filterResponsesBySchedule(responses []database.MockResponse, now time.Time) []database.MockResponse {
+	var scheduled, unscheduled []database.MockResponse
+	for _, response := range responses {
+		schedule := responseSchedule(response)
+		switch {
+		case schedule == nil:
+			unscheduled = append(unscheduled, response)
+		case scheduleCovers(schedule, now):
+			scheduled = append(scheduled, response)
+		}
+	}
+
+	if len(scheduled) > 0 {
+		return scheduled
+	}
+	return unscheduled
+}
+
+// scheduleCovers reports whether now falls between the schedule's start and end and inside one
+// of its daily windows. Windows are read in the schedule's timezone, UTC by default, so they
+// follow that zone's daylight saving changes.
+func scheduleCovers(schedule *database.ResponseSchedule, now time.Time) bool {
+	if start, err := time.Parse(time.RFC3339, schedule.Start); err == nil && now.Before(start) {
+		return false
+	}
+	if end, err := time.Parse(time.RFC3339, schedule.End); err == nil && !now.Before(end) {
+		return false
+	}
+	if len(schedule.Windows) == 0 {
+		return true
+	}
+
+	location := time.UTC
+	if schedule.Timezone != "" {
+		loaded, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			return false
+		}
+		location = loaded
+	}
+	local := now.In(location)
+	for _, window := range schedule.Windows {
+		if windowCovers(window, local) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowCovers reports whether local is inside the daily window. For windows wrapping past
+// midnight the early-morning part belongs to the window that started the day before.
+func windowCovers(window database.TimeWindow, local time.Time) bool {
+	from, errFrom := time.Parse("15:04", window.From)
+	to, errTo := time.Parse("15:04", window.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	fromMinute, toMinute := from.Hour()*60+from.Minute(), to.Hour()*60+to.Minute()
+
+	startDay := local.Weekday()
+	switch {
+	case fromMinute < toMinute:
+		if minute < fromMinute || minute >= toMinute {
+			return false
+		}
+	case minute >= fromMinute:
+	case minute < toMinute:
+		startDay = (startDay + 6) % 7
+	default:
+		return false
+	}
+
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if weekday, ok := database.ParseWeekday(day); ok && weekday == startDay {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestScheduleCovers(t *testing.T) {
+	at := func(value string) time.Time {
+		now, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return now
+	}
+
+	t.Run("Start and end", func(t *testing.T) {
+		schedule := &database.ResponseSchedule{Start: "2025-03-01T00:00:00Z", End: "2025-03-02T00:00:00Z"}
+		assert.False(t, scheduleCovers(schedule, at("2025-02-28T23:59:59Z")))
+		assert.True(t, scheduleCovers(schedule, at("2025-03-01T00:00:00Z")))
+		assert.False(t, scheduleCovers(schedule, at("2025-03-02T00:00:00Z")), "end is exclusive")
+	})
+
+	t.Run("Windows are read in the timezone", func(t *testing.T) {
+		// 09:00-17:00 in Jakarta is 02:00-10:00 UTC
+		schedule := &database.ResponseSchedule{Timezone: "Asia/Jakarta", Windows: []database.TimeWindow{{From: "09:00", To: "17:00"}}}
+		assert.True(t, scheduleCovers(schedule, at("2025-03-03T02:00:00Z")))
+		assert.False(t, scheduleCovers(schedule, at("2025-03-03T10:00:00Z")))
+		assert.False(t, scheduleCovers(schedule, at("2025-03-03T12:00:00Z")))
+		assert.True(t, scheduleCovers(&database.ResponseSchedule{Windows: schedule.Windows}, at("2025-03-03T12:00:00Z")), "UTC by default")
+	})
+
+	t.Run("Windows wrapping midnight belong to the day they start", func(t *testing.T) {
+		// Saturday night maintenance, 2025-03-08 is a Saturday
+		schedule := &database.ResponseSchedule{Windows: []database.TimeWindow{{From: "22:00", To: "02:00", Days: []string{"sat"}}}}
+		assert.False(t, scheduleCovers(schedule, at("2025-03-08T21:59:00Z")))
+		assert.True(t, scheduleCovers(schedule, at("2025-03-08T23:30:00Z")))
+		assert.True(t, scheduleCovers(schedule, at("2025-03-09T01:59:00Z")), "Sunday morning is still Saturday's window")
+		assert.False(t, scheduleCovers(schedule, at("2025-03-09T02:00:00Z")))
+		assert.False(t, scheduleCovers(schedule, at("2025-03-09T23:00:00Z")), "Sunday night is not scheduled")
+	})
+}
+
+func TestHandleRequest_ScheduledMaintenance(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "scheduled")
+
+	original := nowFunc
+	t.Cleanup(func() { nowFunc = original })
+
+	endpoint, err := database.CreateTestEndpoint(project.ID, "GET", "/orders")
+	require.NoError(t, err)
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 200, Body: "orders"})
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 503, Body: "maintenance",
+		AdvanceConfig: `{"schedule": {"timezone": "Asia/Jakarta", "windows": [{"from": "01:00", "to": "03:00"}]}}`})
+	createTestResponse(t, endpoint.ID, database.MockResponse{StatusCode: 410, Body: "retired",
+		AdvanceConfig: `{"schedule": {"start": "2030-01-01T00:00:00Z"}}`})
+
+	call := func(now string) (int, string) {
+		nowFunc = func() time.Time {
+			parsed, _ := time.Parse(time.RFC3339, now)
+			return parsed
+		}
+		req := httptest.NewRequest(http.MethodGet, "/scheduled/orders", nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, "/orders", req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	for i := 0; i < 10; i++ {
+		status, body := call("2025-03-03T12:00:00Z")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "orders", body)
+
+		status, body = call("2025-03-03T01:30:00+07:00")
+		assert.Equal(t, http.StatusServiceUnavailable, status, "the maintenance window replaces the regular response")
+		assert.Equal(t, "maintenance", body)
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// maxSchemaDepth bounds nesting (and $ref recursion) when generating a body from a schema
+const maxSchemaDepth = 8
+
+// schemaWords is the vocabulary for generated free-form strings
+var schemaWords = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet"}
+
+// generateSchemaBody returns a freshly generated JSON body for a bodySchema; ok is false when
+// the schema is empty or not an object, and the configured body is used
+func generateSchemaBody(bodySchema json.RawMessage) (body string, ok bool) {
+	if len(bodySchema) == 0 {
+		return "", false
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(bodySchema, &schema); err != nil {
+		return "", false
+	}
+	instance := (&schemaGenerator{root: schema}).generate(schema, 0)
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// schemaGenerator synthesizes an instance of a JSON Schema using the global random sources.
+// Supported: type (or a list of types), const, enum, examples/example, properties, items,
+// minItems/maxItems, minLength/maxLength, minimum/maximum, common string formats, allOf, anyOf,
+// oneOf and local $ref to #/definitions or #/$defs.
+type schemaGenerator struct {
+	root map[string]interface{}
+}
+
+func (g *schemaGenerator) generate(schema map[string]interface{}, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if resolved := g.resolveRef(ref); resolved != nil {
+			return g.generate(resolved, depth+1)
+		}
+		return nil
+	}
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[randomIntn(len(enum))]
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[randomIntn(len(examples))]
+	}
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		return g.generate(mergeSchemas(allOf), depth+1)
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[keyword].([]interface{}); ok && len(options) > 0 {
+			if option, ok := options[randomIntn(len(options))].(map[string]interface{}); ok {
+				return g.generate(option, depth+1)
+			}
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return g.generateObject(schema, depth)
+	case "array":
+		return g.generateArray(schema, depth)
+	case "string":
+		return generateSchemaString(schema)
+	case "integer":
+		minimum, maximum := schemaRange(schema, 0, 1000)
+		span := int(math.Floor(maximum) - math.Ceil(minimum))
+		if span < 0 {
+			span = 0
+		}
+		return int64(math.Ceil(minimum)) + int64(randomIntn(span+1))
+	case "number":
+		minimum, maximum := schemaRange(schema, 0, 1000)
+		return math.Round((minimum+randomFloat64()*(maximum-minimum))*100) / 100
+	case "boolean":
+		return randomIntn(2) == 1
+	default:
+		return nil
+	}
+}
+
+func (g *schemaGenerator) generateObject(schema map[string]interface{}, depth int) map[string]interface{} {
+	object := make(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		if propertySchema, ok := property.(map[string]interface{}); ok {
+			object[name] = g.generate(propertySchema, depth+1)
+		}
+	}
+	return object
+}
+
+func (g *schemaGenerator) generateArray(schema map[string]interface{}, depth int) []interface{} {
+	minItems := schemaInt(schema, "minItems", 1)
+	maxItems := schemaInt(schema, "maxItems", minItems+2)
+	if maxItems < minItems {
+		maxItems = minItems
+	}
+	count := minItems + randomIntn(maxItems-minItems+1)
+
+	items, _ := schema["items"].(map[string]interface{})
+	array := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		array = append(array, g.generate(items, depth+1))
+	}
+	return array
+}
+
+// resolveRef looks up a local reference such as "#/definitions/User" or "#/$defs/User"
+func (g *schemaGenerator) resolveRef(ref string) map[string]interface{} {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+	var current interface{} = g.root
+	for _, part := range strings.Split(path, "/") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+	}
+	resolved, _ := current.(map[string]interface{})
+	return resolved
+}
+
+// generateSchemaString produces a string honouring format, then minLength/maxLength
+func generateSchemaString(schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "email":
+		return fmt.Sprintf("%s.%s@example.com", schemaWords[randomIntn(len(schemaWords))], schemaWords[randomIntn(len(schemaWords))])
+	case "uuid":
+		return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x", randomIntn(1<<30), randomIntn(1<<16), randomIntn(1<<12), 0x8000|randomIntn(1<<14), randomIntn(1<<30))
+	case "date-time":
+		return time.Now().UTC().Add(-time.Duration(randomIntn(365*24)) * time.Hour).Format(time.RFC3339)
+	case "date":
+		return time.Now().UTC().AddDate(0, 0, -randomIntn(365)).Format("2006-01-02")
+	case "uri", "url":
+		return "https://example.com/" + schemaWords[randomIntn(len(schemaWords))]
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+randomIntn(254))
+	case "hostname":
+		return schemaWords[randomIntn(len(schemaWords))] + ".example.com"
+	}
+
+	minLength := schemaInt(schema, "minLength", 0)
+	maxLength := schemaInt(schema, "maxLength", 0)
+	value := schemaWords[randomIntn(len(schemaWords))]
+	for len(value) < minLength {
+		value += schemaWords[randomIntn(len(schemaWords))]
+	}
+	if maxLength > 0 && len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	return value
+}
+
+// schemaType returns the schema's type, the first non-null one when a list is given.
+// Untyped schemas with properties or items are treated as objects or arrays.
+func schemaType(schema map[string]interface{}) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []interface{}:
+		for _, entry := range value {
+			if name, ok := entry.(string); ok && name != "null" {
+				return name
+			}
+		}
+		return "null"
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// schemaRange returns minimum and maximum, falling back to the defaults (or minimum+1000)
+func schemaRange(schema map[string]interface{}, defaultMin, defaultMax float64) (float64, float64) {
+	minimum, hasMin := schema["minimum"].(float64)
+	maximum, hasMax := schema["maximum"].(float64)
+	if !hasMin {
+		minimum = defaultMin
+		if hasMax && maximum < minimum {
+			minimum = maximum - (defaultMax - defaultMin)
+		}
+	}
+	if !hasMax || maximum < minimum {
+		maximum = minimum + (defaultMax - defaultMin)
+	}
+	return minimum, maximum
+}
+
+func schemaInt(schema map[string]interface{}, keyword string, fallback int) int {
+	if value, ok := schema[keyword].(float64); ok && value >= 0 {
+		return int(value)
+	}
+	return fallback
+}
+
+// mergeSchemas combines allOf members into one schema; properties are merged, other keywords
+// from later members win
+func mergeSchemas(members []interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	properties := make(map[string]interface{})
+	for _, member := range members {
+		schema, ok := member.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range schema {
+			if key == "properties" {
+				if memberProperties, ok := value.(map[string]interface{}); ok {
+					for name, property := range memberProperties {
+						properties[name] = property
+					}
+				}
+				continue
+			}
+			merged[key] = value
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+		if _, ok := merged["type"]; !ok {
+			merged["type"] = "object"
+		}
+	}
+	return merged
+}
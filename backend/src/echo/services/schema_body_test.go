@@ -0,0 +1,71 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestCreateMockResponse_BodySchema(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"email": {"type": "string", "format": "email"},
+			"age": {"type": "integer", "minimum": 18, "maximum": 65},
+			"status": {"enum": ["active", "suspended"]},
+			"score": {"type": "number", "minimum": 0, "maximum": 1},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 4}, "minItems": 2, "maxItems": 2},
+			"manager": {"$ref": "#/$defs/person"},
+			"verified": {"type": ["boolean", "null"]}
+		},
+		"$defs": {
+			"person": {"type": "object", "properties": {"name": {"const": "Ada"}}}
+		}
+	}`
+
+	for i := 0; i < 20; i++ {
+		resp, err := createMockResponse(database.MockResponse{
+			StatusCode:    200,
+			Body:          "ignored",
+			AdvanceConfig: `{"bodySchema": ` + schema + `}`,
+		})
+		require.NoError(t, err)
+
+		data, _ := io.ReadAll(resp.Body)
+		var body struct {
+			ID       string            `json:"id"`
+			Email    string            `json:"email"`
+			Age      int               `json:"age"`
+			Status   string            `json:"status"`
+			Score    float64           `json:"score"`
+			Tags     []string          `json:"tags"`
+			Manager  map[string]string `json:"manager"`
+			Verified *bool             `json:"verified"`
+		}
+		require.NoError(t, json.Unmarshal(data, &body), string(data))
+
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), body.ID)
+		assert.Contains(t, body.Email, "@example.com")
+		assert.GreaterOrEqual(t, body.Age, 18)
+		assert.LessOrEqual(t, body.Age, 65)
+		assert.Contains(t, []string{"active", "suspended"}, body.Status)
+		assert.GreaterOrEqual(t, body.Score, 0.0)
+		assert.LessOrEqual(t, body.Score, 1.0)
+		require.Len(t, body.Tags, 2)
+		assert.LessOrEqual(t, len(body.Tags[0]), 4)
+		assert.Equal(t, "Ada", body.Manager["name"])
+		assert.NotNil(t, body.Verified)
+	}
+}
+
+func TestParseResponseAdvanceConfig_InvalidBodySchema(t *testing.T) {
+	_, err := database.ParseResponseAdvanceConfig(`{"bodySchema": ["not", "an", "object"]}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// defaultSessionTTL is how long an idle session keeps its values when ttlSeconds is not set
+const defaultSessionTTL = 30 * time.Minute
+
+// sessionEntry holds the values of one session
+type sessionEntry struct {
+	values    map[string]string
+	expiresAt time.Time
+}
+
+// sessionStore holds the sessions of one project (session key -> values)
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionEntry
+}
+
+// Global session stores per project (projectID -> *sessionStore)
+var projectSessionStores sync.Map
+
+// sessionScopeKey stores the request's *sessionScope in the request context
+type sessionScopeKey struct{}
+
+// sessionScope is the session a request belongs to
+type sessionScope struct {
+	projectID string
+	key       string
+	ttl       time.Duration
+}
+
+// SessionInfo describes a stored session for the admin API
+type SessionInfo struct {
+	Key       string            `json:"key"`
+	Values    map[string]string `json:"values"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// withSession returns a request whose context carries its session when the project has a
+// session config and the request sends the configured header or cookie
+func withSession(req *http.Request, projectID string, config *database.SessionConfig) *http.Request {
+	if config == nil {
+		return req
+	}
+	key := sessionKeyFrom(req, config)
+	if key == "" {
+		return req
+	}
+
+	ttl := defaultSessionTTL
+	if config.TTLSeconds > 0 {
+		ttl = time.Duration(config.TTLSeconds) * time.Second
+	}
+	scope := &sessionScope{projectID: projectID, key: key, ttl: ttl}
+	return req.WithContext(context.WithValue(req.Context(), sessionScopeKey{}, scope))
+}
+
+// sessionKeyFrom extracts the session key from the configured header, then the cookie
+func sessionKeyFrom(req *http.Request, config *database.SessionConfig) string {
+	if config.Header != "" {
+		if key := strings.TrimSpace(req.Header.Get(config.Header)); key != "" {
+			return key
+		}
+	}
+	if config.Cookie != "" {
+		if cookie, err := req.Cookie(config.Cookie); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// requestSession returns the session stored by withSession, nil when the request has none
+func requestSession(req *http.Request) *sessionScope {
+	if req == nil {
+		return nil
+	}
+	scope, _ := req.Context().Value(sessionScopeKey{}).(*sessionScope)
+	return scope
+}
+
+// projectSessions returns the project's session store, creating it on first use
+func projectSessions(projectID string) *sessionStore {
+	value, _ := projectSessionStores.LoadOrStore(projectID, &sessionStore{sessions: map[string]*sessionEntry{}})
+	return value.(*sessionStore)
+}
+
+// get returns the session's value for name, "" when missing or expired
+func (s *sessionScope) get(name string) string {
+	if s == nil {
+		return ""
+	}
+	store := projectSessions(s.projectID)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry, ok := store.sessions[s.key]
+	if !ok || !nowFunc().Before(entry.expiresAt) {
+		return ""
+	}
+	return entry.values[name]
+}
+
+// set stores the value for name and extends the session's lifetime by its TTL. Expired
+// sessions of the project are evicted on every write.
+func (s *sessionScope) set(name, value string) {
+	if s == nil {
+		return
+	}
+	store := projectSessions(s.projectID)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := nowFunc()
+	for key, entry := range store.sessions {
+		if !now.Before(entry.expiresAt) {
+			delete(store.sessions, key)
+		}
+	}
+
+	entry, ok := store.sessions[s.key]
+	if !ok {
+		entry = &sessionEntry{values: map[string]string{}}
+		store.sessions[s.key] = entry
+	}
+	entry.values[name] = value
+	entry.expiresAt = now.Add(s.ttl)
+}
+
+// ListSessions returns the project's live sessions ordered by key
+func ListSessions(projectID string) []SessionInfo {
+	store := projectSessions(projectID)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := nowFunc()
+	sessions := make([]SessionInfo, 0, len(store.sessions))
+	for key, entry := range store.sessions {
+		if !now.Before(entry.expiresAt) {
+			continue
+		}
+		values := make(map[string]string, len(entry.values))
+		for name, value := range entry.values {
+			values[name] = value
+		}
+		sessions = append(sessions, SessionInfo{Key: key, Values: values, ExpiresAt: entry.expiresAt})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Key < sessions[j].Key })
+	return sessions
+}
+
+// DeleteSession drops one session of the project; deleting a missing session is not an error
+func DeleteSession(projectID, key string) {
+	store := projectSessions(projectID)
+	store.mu.Lock()
+	delete(store.sessions, key)
+	store.mu.Unlock()
+}
+
+// ResetSessions drops every session of the project. Sessions are in memory only, so a
+// restart resets them too.
+func ResetSessions(projectID string) {
+	projectSessionStores.Delete(projectID)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestHandleRequest_SessionStore(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "session-store")
+	project.AdvanceConfig = `{"session": {"header": "X-Session-Id", "cookie": "sid", "ttlSeconds": 60}}`
+	require.NoError(t, database.DB.Save(project).Error)
+	t.Cleanup(func() { ResetSessions(project.ID) })
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+
+	activate, err := database.CreateTestEndpoint(project.ID, "POST", "/tokens/activate")
+	require.NoError(t, err)
+	createTestResponse(t, activate.ID, database.MockResponse{StatusCode: 201, Body: `{{sessionSet "status" "active"}}{{sessionSet "user" .Body.user}}{"ok":true}`, AdvanceConfig: `{"templated": true}`})
+	status, err := database.CreateTestEndpoint(project.ID, "GET", "/tokens/status")
+	require.NoError(t, err)
+	createTestResponse(t, status.ID, database.MockResponse{StatusCode: 200, Body: `{"status":"{{session "status"}}","user":"{{session "user"}}"}`, AdvanceConfig: `{"templated": true}`})
+
+	call := func(method, path, body string, setup func(*http.Request)) string {
+		req := httptest.NewRequest(method, "/session-store"+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if setup != nil {
+			setup(req)
+		}
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, method, path, req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(respBody)
+	}
+	withHeader := func(key string) func(*http.Request) {
+		return func(req *http.Request) { req.Header.Set("X-Session-Id", key) }
+	}
+
+	assert.Equal(t, `{"ok":true}`, call(http.MethodPost, "/tokens/activate", `{"user":"ada"}`, withHeader("token-1")))
+	assert.JSONEq(t, `{"status":"active","user":"ada"}`, call(http.MethodGet, "/tokens/status", "", withHeader("token-1")))
+
+	t.Run("Sessions are isolated", func(t *testing.T) {
+		assert.JSONEq(t, `{"status":"","user":""}`, call(http.MethodGet, "/tokens/status", "", withHeader("token-2")))
+		assert.JSONEq(t, `{"status":"","user":""}`, call(http.MethodGet, "/tokens/status", "", nil), "requests without a session key have no state")
+	})
+
+	t.Run("Cookie identifies the session when the header is absent", func(t *testing.T) {
+		withCookie := func(req *http.Request) { req.AddCookie(&http.Cookie{Name: "sid", Value: "token-1"}) }
+		assert.JSONEq(t, `{"status":"active","user":"ada"}`, call(http.MethodGet, "/tokens/status", "", withCookie))
+	})
+
+	t.Run("Listed for the admin API", func(t *testing.T) {
+		sessions := ListSessions(project.ID)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "token-1", sessions[0].Key)
+		assert.Equal(t, map[string]string{"status": "active", "user": "ada"}, sessions[0].Values)
+		assert.Equal(t, now.Add(time.Minute), sessions[0].ExpiresAt)
+	})
+
+	t.Run("Idle sessions expire after the TTL", func(t *testing.T) {
+		now = now.Add(61 * time.Second)
+		assert.JSONEq(t, `{"status":"","user":""}`, call(http.MethodGet, "/tokens/status", "", withHeader("token-1")))
+		assert.Empty(t, ListSessions(project.ID))
+	})
+
+	t.Run("Reset drops all sessions", func(t *testing.T) {
+		call(http.MethodPost, "/tokens/activate", `{"user":"grace"}`, withHeader("token-3"))
+		ResetSessions(project.ID)
+		assert.JSONEq(t, `{"status":"","user":""}`, call(http.MethodGet, "/tokens/status", "", withHeader("token-3")))
+	})
+}
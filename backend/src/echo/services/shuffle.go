@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sync"
+
+	"beo-echo/backend/src/database"
+)
+
+// shuffleState is the shuffled serving order of an endpoint's responses
+type shuffleState struct {
+	mu    sync.Mutex
+	order []int // permutation of response indexes
+	next  int   // position in order of the next response to serve
+}
+
+// Global shuffle state per endpoint (endpointID -> *shuffleState)
+var endpointShuffleStates sync.Map
+
+// getNextShuffleResponse serves every response exactly once in a random order, then reshuffles.
+// The order is drawn from the injectable randomIntn source and is safe for concurrent requests.
+func getNextShuffleResponse(endpointID string, responses []database.MockResponse) database.MockResponse {
+	if len(responses) == 0 {
+		return database.MockResponse{}
+	}
+
+	// Work on a priority-sorted copy so indexes are stable between requests
+	sortedResponses := make([]database.MockResponse, len(responses))
+	copy(sortedResponses, responses)
+	sortByPriority(sortedResponses)
+
+	val, _ := endpointShuffleStates.LoadOrStore(endpointID, &shuffleState{})
+	state := val.(*shuffleState)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	// Reshuffle when the cycle is exhausted or the candidate set changed size
+	if state.next >= len(state.order) || len(state.order) != len(sortedResponses) {
+		state.order = shuffledIndexes(len(sortedResponses))
+		state.next = 0
+	}
+
+	selected := sortedResponses[state.order[state.next]]
+	state.next++
+	return selected
+}
+
+// shuffledIndexes returns a Fisher-Yates permutation of 0..n-1
+func shuffledIndexes(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := randomIntn(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestGetNextShuffleResponse(t *testing.T) {
+	responses := []database.MockResponse{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+
+	t.Run("Each cycle serves every response exactly once", func(t *testing.T) {
+		endpointID := "endpoint-shuffle-cycle"
+		defer ResetEndpointState(endpointID)
+
+		for cycle := 0; cycle < 3; cycle++ {
+			var served []string
+			for i := 0; i < len(responses); i++ {
+				served = append(served, getNextShuffleResponse(endpointID, responses).ID)
+			}
+			sort.Strings(served)
+			assert.Equal(t, []string{"a", "b", "c", "d"}, served)
+		}
+	})
+
+	t.Run("Order comes from the injectable source", func(t *testing.T) {
+		endpointID := "endpoint-shuffle-stub"
+		defer ResetEndpointState(endpointID)
+		// Always swapping with index 0 rotates the identity order: b, c, d, a
+		stubRandomIntn(t, func(n int) int { return 0 })
+
+		var served []string
+		for i := 0; i < len(responses); i++ {
+			served = append(served, getNextShuffleResponse(endpointID, responses).ID)
+		}
+		assert.Equal(t, []string{"b", "c", "d", "a"}, served)
+	})
+
+	t.Run("Concurrent requests still complete full cycles", func(t *testing.T) {
+		endpointID := "endpoint-shuffle-concurrent"
+		defer ResetEndpointState(endpointID)
+
+		const cycles = 50
+		var mu sync.Mutex
+		counts := map[string]int{}
+		var wg sync.WaitGroup
+		for i := 0; i < cycles*len(responses); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id := getNextShuffleResponse(endpointID, responses).ID
+				mu.Lock()
+				counts[id]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, counts, len(responses))
+		for id, count := range counts {
+			assert.Equal(t, cycles, count, "response %s", id)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// sseBody produces the events of an SSE stream one read at a time, waiting each event's delay
+// first, so the mock handler flushes every event as it is due
+type sseBody struct {
+	ctx     context.Context
+	stream  *database.SSEStream
+	next    int
+	started bool
+	pending bytes.Buffer
+}
+
+func (b *sseBody) Read(p []byte) (int, error) {
+	if b.pending.Len() == 0 {
+		if err := b.fill(); err != nil {
+			return 0, err
+		}
+	}
+	return b.pending.Read(p)
+}
+
+// fill queues the retry field on the first read, then the next event once its delay passed.
+// A cancelled context (client gone) ends the stream with the context error.
+func (b *sseBody) fill() error {
+	if !b.started {
+		b.started = true
+		if b.stream.RetryMs > 0 {
+			fmt.Fprintf(&b.pending, "retry: %d\n\n", b.stream.RetryMs)
+			return nil
+		}
+	}
+	if b.next >= len(b.stream.Events) {
+		return io.EOF
+	}
+
+	event := b.stream.Events[b.next]
+	b.next++
+	if event.DelayMs > 0 {
+		timer := time.NewTimer(time.Duration(event.DelayMs) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		case <-timer.C:
+		}
+	} else if err := b.ctx.Err(); err != nil {
+		return err
+	}
+
+	writeSSEEvent(&b.pending, event)
+	return nil
+}
+
+func (b *sseBody) Close() error { return nil }
+
+// Streaming marks sseBody as a StreamingBody so each event is flushed on its own
+func (b *sseBody) Streaming() {}
+
+// writeSSEEvent formats one event in the text/event-stream format
+func writeSSEEvent(buf *bytes.Buffer, event database.SSEEvent) {
+	if event.ID != "" {
+		fmt.Fprintf(buf, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(buf, "event: %s\n", event.Event)
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(event.Data, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+}
+
+// createStreamOrMockResponse builds the SSE stream of a response with an sse config, the gRPC
+// stream of a grpcStream config answering a server-streaming call, or the regular mock response
+// otherwise
+func createStreamOrMockResponse(ctx context.Context, response database.MockResponse, config *database.AdvanceConfigResponse) (*http.Response, error) {
+	if config != nil && config.GRPCStream != nil {
+		if output, ok := grpcStreamOutput(ctx); ok {
+			return createGRPCStreamResponse(ctx, response, config.GRPCStream, output)
+		}
+	}
+	if config != nil && config.SSE != nil {
+		return createSSEResponse(ctx, response, config.SSE)
+	}
+	return createMockResponse(response)
+}
+
+// createSSEResponse builds the response headers through createMockResponse and replaces the
+// body with the event stream. Clients and proxies must not buffer or cache the stream.
+func createSSEResponse(ctx context.Context, response database.MockResponse, stream *database.SSEStream) (*http.Response, error) {
+	response.Body = ""
+	response.RedirectURL = ""
+	resp, err := createMockResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	resp.Header.Set("Content-Type", "text/event-stream")
+	resp.Header.Set("Cache-Control", "no-cache")
+	resp.Header.Set("X-Accel-Buffering", "no")
+	resp.Header.Del("Content-Length")
+	resp.Header.Del("Content-Encoding")
+	resp.ContentLength = -1
+	resp.Body = &sseBody{ctx: ctx, stream: stream}
+	return resp, nil
+}
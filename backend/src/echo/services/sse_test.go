@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestRespondWithDelay_SSEStream(t *testing.T) {
+	service := &MockService{}
+	project := &database.Project{}
+	endpoint := &database.MockEndpoint{}
+
+	t.Run("Streams every event in the event-stream format", func(t *testing.T) {
+		response := &database.MockResponse{
+			StatusCode: 200,
+			Body:       "ignored",
+			Headers:    `{"X-Stream": "jobs"}`,
+			AdvanceConfig: `{"sse": {"retryMs": 3000, "events": [
+				{"event": "progress", "data": "{\"progress\":50}", "id": "1"},
+				{"data": "line one\nline two", "delayMs": 30}
+			]}}`,
+		}
+
+		start := time.Now()
+		resp, err := service.respondWithDelay(context.Background(), project, endpoint, response)
+		require.NoError(t, err)
+
+		_, streaming := resp.Body.(StreamingBody)
+		assert.True(t, streaming, "events are flushed as they are produced")
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "jobs", resp.Header.Get("X-Stream"))
+		assert.Empty(t, resp.Header.Get("Content-Length"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "retry: 3000\n\nid: 1\nevent: progress\ndata: {\"progress\":50}\n\ndata: line one\ndata: line two\n\n", string(body))
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+
+	t.Run("Client disconnect stops the stream", func(t *testing.T) {
+		response := &database.MockResponse{
+			StatusCode:    200,
+			AdvanceConfig: `{"sse": {"events": [{"data": "first"}, {"data": "never", "delayMs": 10000}]}}`,
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		resp, err := service.respondWithDelay(ctx, project, endpoint, response)
+		require.NoError(t, err)
+
+		buf := make([]byte, 1024)
+		n, err := resp.Body.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "data: first\n\n", string(buf[:n]))
+
+		time.AfterFunc(20*time.Millisecond, cancel)
+		start := time.Now()
+		_, err = resp.Body.Read(buf)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestCreateStreamOrMockResponse_WithoutSSE(t *testing.T) {
+	resp, err := createStreamOrMockResponse(context.Background(), database.MockResponse{StatusCode: http.StatusCreated, Body: "plain"}, &database.AdvanceConfigResponse{})
+
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "plain", string(body))
+}
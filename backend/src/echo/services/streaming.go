@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// StreamingBody is implemented by response bodies that must reach the client as they are produced.
+// The mock handler sends the status and headers before reading such a body and flushes after every chunk.
+type StreamingBody interface {
+	io.ReadCloser
+	Streaming()
+}
+
+// delayedBody holds back the first read of the body until the delay has passed,
+// giving clients headers immediately and the body later
+type delayedBody struct {
+	ctx    context.Context
+	body   io.ReadCloser
+	delay  time.Duration
+	waited bool
+}
+
+// newDelayedBody wraps body so reading starts only after delay; a cancelled ctx aborts the wait
+func newDelayedBody(ctx context.Context, body io.ReadCloser, delay time.Duration) *delayedBody {
+	return &delayedBody{ctx: ctx, body: body, delay: delay}
+}
+
+func (d *delayedBody) Read(p []byte) (int, error) {
+	if !d.waited {
+		d.waited = true
+		timer := time.NewTimer(d.delay)
+		defer timer.Stop()
+
+		select {
+		case <-d.ctx.Done():
+			return 0, d.ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return d.body.Read(p)
+}
+
+func (d *delayedBody) Close() error {
+	return d.body.Close()
+}
+
+// Streaming marks delayedBody as a StreamingBody so headers are flushed before the wait
+func (d *delayedBody) Streaming() {}
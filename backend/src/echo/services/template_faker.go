@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Global template counters per project and name ("projectID\x00name" -> *int64)
+var templateCounters sync.Map
+
+var (
+	fakeFirstNames = []string{"Ada", "Budi", "Citra", "Dewi", "Ethan", "Fatima", "Grace", "Hiro", "Intan", "Jonas", "Kirana", "Liam", "Maya", "Nadia", "Omar", "Putri"}
+	fakeLastNames  = []string{"Santoso", "Wijaya", "Lovelace", "Hopper", "Tanaka", "Rahman", "Smith", "Nugroho", "Garcia", "Kusuma", "Müller", "Pratama"}
+)
+
+// templateTimeLayouts are the layout names accepted by {{now}}; other values are used as Go layouts
+var templateTimeLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC822":      time.RFC822,
+	"Kitchen":     time.Kitchen,
+	"DateOnly":    time.DateOnly,
+	"DateTime":    time.DateTime,
+}
+
+// templateRandom draws from the request's seeded source (beo-echo-random-seed) when present,
+// otherwise from the global randomIntn source, so rendered fake data is reproducible in tests
+type templateRandom struct {
+	seeded *rand.Rand
+}
+
+func (r templateRandom) intn(n int) int {
+	if r.seeded != nil {
+		return r.seeded.Intn(n)
+	}
+	return randomIntn(n)
+}
+
+// Read fills p with random bytes so uuid generation uses the same source
+func (r templateRandom) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r.intn(256))
+	}
+	return len(p), nil
+}
+
+// fakerTemplateFuncs are the dynamic data helpers of response templates:
+//
+//	{{uuid}}                 random UUID v4
+//	{{now}} {{now "unix"}}   current time: RFC3339 by default, a layout name, "unix", "unixMilli" or a Go layout
+//	{{randInt 1 100}}        random integer in [min, max]
+//	{{fakeName}}             random "First Last" name; {{fakeEmail}} a matching address
+//	{{counter "orders"}}     per-project counter incremented on every render, starting at 1
+func fakerTemplateFuncs(data responseTemplateData) template.FuncMap {
+	random := data.random
+	return template.FuncMap{
+		"uuid": func() string {
+			id, err := uuid.NewRandomFromReader(random)
+			if err != nil {
+				return ""
+			}
+			return id.String()
+		},
+		"now": func(layout ...string) string {
+			now := nowFunc().UTC()
+			if len(layout) == 0 {
+				return now.Format(time.RFC3339)
+			}
+			switch layout[0] {
+			case "unix":
+				return fmt.Sprint(now.Unix())
+			case "unixMilli":
+				return fmt.Sprint(now.UnixMilli())
+			}
+			if named, ok := templateTimeLayouts[layout[0]]; ok {
+				return now.Format(named)
+			}
+			return now.Format(layout[0])
+		},
+		"randInt": func(min, max int) int {
+			if max < min {
+				min, max = max, min
+			}
+			return min + random.intn(max-min+1)
+		},
+		"fakeName": func() string {
+			return fakeFirstNames[random.intn(len(fakeFirstNames))] + " " + fakeLastNames[random.intn(len(fakeLastNames))]
+		},
+		"fakeEmail": func() string {
+			first := strings.ToLower(fakeFirstNames[random.intn(len(fakeFirstNames))])
+			return fmt.Sprintf("%s%d@example.com", first, random.intn(1000))
+		},
+		"counter": func(name string) int64 {
+			value, _ := templateCounters.LoadOrStore(data.projectID+"\x00"+name, new(int64))
+			return atomic.AddInt64(value.(*int64), 1)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestFakerTemplateFuncs(t *testing.T) {
+	render := func(body string) string {
+		req := withFixtureProject(httptest.NewRequest(http.MethodGet, "/", nil), "faker-project")
+		response := &database.MockResponse{Body: body, AdvanceConfig: `{"templated": true}`}
+		return renderResponseTemplate(nil, response, req).Body
+	}
+
+	t.Run("Seeded sources render the same data", func(t *testing.T) {
+		body := `{{uuid}} {{randInt 1 100}} {{fakeName}} {{fakeEmail}}`
+		stubRandomIntn(t, rand.New(rand.NewSource(7)).Intn)
+		first := render(body)
+		stubRandomIntn(t, rand.New(rand.NewSource(7)).Intn)
+		assert.Equal(t, first, render(body))
+
+		stubRandomIntn(t, rand.New(rand.NewSource(8)).Intn)
+		assert.NotEqual(t, first, render(body))
+	})
+
+	t.Run("Formats", func(t *testing.T) {
+		stubNow(t, time.Date(2024, 5, 17, 10, 30, 0, 0, time.UTC))
+
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), render(`{{uuid}}`))
+		assert.Equal(t, "2024-05-17T10:30:00Z", render(`{{now}}`))
+		assert.Equal(t, "2024-05-17T10:30:00Z", render(`{{now "RFC3339"}}`))
+		assert.Equal(t, "1715941800", render(`{{now "unix"}}`))
+		assert.Equal(t, "17/05/2024", render(`{{now "02/01/2006"}}`))
+		assert.Regexp(t, `^\S+ \S+$`, render(`{{fakeName}}`))
+		assert.Regexp(t, `^[a-z]+\d+@example\.com$`, render(`{{fakeEmail}}`))
+	})
+
+	t.Run("randInt stays within bounds", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			value, err := strconv.Atoi(render(`{{randInt 5 7}}`))
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, value, 5)
+			assert.LessOrEqual(t, value, 7)
+		}
+	})
+
+	t.Run("Counters increment per name", func(t *testing.T) {
+		assert.Equal(t, "1 2 1", render(`{{counter "faker-a"}} {{counter "faker-a"}} {{counter "faker-b"}}`))
+		assert.Equal(t, "3", render(`{{counter "faker-a"}}`))
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// tokenBucket is a request quota refilled continuously at rate tokens per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// Global token buckets per limiter key (see rateLimitKey -> *tokenBucket)
+var tokenBuckets sync.Map
+
+// take refills the bucket for the time elapsed since the last call and consumes one token.
+// Returns the whole tokens left and whether the request is allowed.
+func (b *tokenBucket) take(now time.Time) (remaining int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.burst), b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return 0, false
+	}
+	b.tokens--
+	return int(b.tokens), true
+}
+
+// loadTokenBucket returns the bucket of the key, starting a full one when the key has none
+// yet or its rate/burst configuration changed. Concurrent callers always share one bucket.
+func loadTokenBucket(key string, config *database.TokenBucketConfig, now time.Time) *tokenBucket {
+	if value, ok := tokenBuckets.Load(key); ok && value.(*tokenBucket).configuredAs(config) {
+		return value.(*tokenBucket)
+	}
+
+	fresh := &tokenBucket{rate: config.Rate, burst: config.Burst, tokens: float64(config.Burst), last: now}
+	for {
+		value, loaded := tokenBuckets.LoadOrStore(key, fresh)
+		if !loaded {
+			return fresh
+		}
+		bucket := value.(*tokenBucket)
+		if bucket.configuredAs(config) {
+			return bucket
+		}
+		// Only one caller replaces an outdated bucket; the others retry and find the new one
+		if tokenBuckets.CompareAndSwap(key, bucket, fresh) {
+			return fresh
+		}
+	}
+}
+
+// configuredAs reports whether the bucket was started with the config's rate and burst
+func (b *tokenBucket) configuredAs(config *database.TokenBucketConfig) bool {
+	return b.rate == config.Rate && b.burst == config.Burst
+}
+
+// takeToken consumes a token from the key's quota and returns the quota headers to attach to
+// the response. The headers are nil when no token bucket is configured.
+func takeToken(key string, config *database.TokenBucketConfig) (headers http.Header, ok bool) {
+	if config == nil || config.Burst <= 0 {
+		return nil, true
+	}
+
+	now := time.Now()
+	remaining, ok := loadTokenBucket(key, config, now).take(now)
+
+	headers = make(http.Header)
+	headers.Set("X-RateLimit-Limit", strconv.Itoa(config.Burst))
+	headers.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if !ok && config.Rate > 0 {
+		headers.Set("Retry-After", strconv.Itoa(int(math.Ceil(1/config.Rate))))
+	}
+	return headers, ok
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	start := time.Now()
+	bucket := &tokenBucket{rate: 2, burst: 3, tokens: 3, last: start}
+
+	for expected := 2; expected >= 0; expected-- {
+		remaining, ok := bucket.take(start)
+		require.True(t, ok)
+		assert.Equal(t, expected, remaining)
+	}
+
+	_, ok := bucket.take(start)
+	assert.False(t, ok, "empty bucket rejects")
+
+	// Half a second at 2 tokens/s refills one token
+	remaining, ok := bucket.take(start.Add(500 * time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	// Refill never exceeds the burst
+	remaining, ok = bucket.take(start.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, 2, remaining)
+}
+
+func TestTakeToken_Concurrent(t *testing.T) {
+	allowedConcurrently := func(key string, config *database.TokenBucketConfig) int64 {
+		t.Cleanup(func() { tokenBuckets.Delete(key) })
+		var allowed atomic.Int64
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				if _, ok := takeToken(key, config); ok {
+					allowed.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		return allowed.Load()
+	}
+
+	t.Run("First requests share one bucket", func(t *testing.T) {
+		assert.Equal(t, int64(5), allowedConcurrently("concurrent-first", &database.TokenBucketConfig{Rate: 0.001, Burst: 5}))
+	})
+
+	t.Run("A changed config starts one new bucket", func(t *testing.T) {
+		allowedConcurrently("concurrent-change", &database.TokenBucketConfig{Rate: 0.001, Burst: 2})
+		assert.Equal(t, int64(4), allowedConcurrently("concurrent-change", &database.TokenBucketConfig{Rate: 0.001, Burst: 4}))
+	})
+}
+
+func TestHandleRequest_ProjectTokenBucket(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "token-bucket")
+	project.AdvanceConfig = `{"tokenBucket": {"rate": 0.5, "burst": 2}}`
+	require.NoError(t, database.DB.Save(project).Error)
+
+	first, err := database.CreateTestEndpoint(project.ID, "GET", "/a")
+	require.NoError(t, err)
+	createTestResponse(t, first.ID, database.MockResponse{StatusCode: 200, Body: "a"})
+	second, err := database.CreateTestEndpoint(project.ID, "GET", "/b")
+	require.NoError(t, err)
+	createTestResponse(t, second.ID, database.MockResponse{StatusCode: 200, Body: "b"})
+
+	call := func(path string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/token-bucket"+path, nil)
+		resp, err, _, _, _ := service.HandleRequest(context.Background(), project.Alias, http.MethodGet, path, req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := call("/a")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Remaining"))
+
+	// The quota is shared across endpoints
+	resp = call("/b")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+
+	resp = call("/a")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+}
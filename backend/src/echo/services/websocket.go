@@ -0,0 +1,410 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"beo-echo/backend/src/database"
+)
+
+// webSocketGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept (RFC 6455)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// webSocketMaxMessageSize caps a client message, fragments included
+const webSocketMaxMessageSize = 1 << 20
+
+// webSocketCloseWait is how long to wait for the client's close frame after sending ours
+const webSocketCloseWait = 5 * time.Second
+
+// WebSocket opcodes
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WebSocket close codes sent by the mock server
+const (
+	wsCloseNormal        = 1000
+	wsCloseProtocolError = 1002
+	wsCloseTooBig        = 1009
+)
+
+// errWebSocketProtocol is returned for frames that break RFC 6455
+var errWebSocketProtocol = errors.New("websocket protocol error")
+
+// errWebSocketTooBig is returned for client messages above webSocketMaxMessageSize
+var errWebSocketTooBig = errors.New("websocket message too big")
+
+// WebSocketMock is an endpoint that answers WebSocket upgrades
+type WebSocketMock struct {
+	ProjectID  string
+	Mode       database.ProjectMode
+	EndpointID string
+	Config     *database.WebSocketConfig
+}
+
+// wsFrame is one WebSocket frame with its payload unmasked
+type wsFrame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+// wsConn is an upgraded connection. Writes are serialized so pings, pongs and messages sent
+// from different goroutines don't interleave.
+type wsConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	writeMu   sync.Mutex
+	closeSent bool
+}
+
+// IsWebSocketUpgrade reports whether the request asks to switch to the WebSocket protocol
+func IsWebSocketUpgrade(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		headerHasToken(req.Header, "Connection", "upgrade") &&
+		strings.EqualFold(req.Header.Get("Upgrade"), "websocket")
+}
+
+// headerHasToken reports whether a comma-separated header contains the token, ignoring case
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindWebSocketMock returns the enabled endpoint matching GET path when it has a webSocket
+// config. Projects that are disabled have no WebSocket endpoints.
+func (s *MockService) FindWebSocketMock(alias, path string) (*WebSocketMock, bool) {
+	project, err := s.Repo.FindProjectByAlias(alias)
+	if err != nil || project.Mode == database.ModeDisabled {
+		return nil, false
+	}
+	path = strings.TrimPrefix(path, "/"+project.Alias)
+	projectConfig, err := database.ParseProjectAdvanceConfig(project.AdvanceConfig)
+	if err == nil && projectConfig.NormalizePaths {
+		path = normalizeRequestPath(path)
+	}
+
+	endpoint, err := s.findEndpoint(project, http.MethodGet, path)
+	if err != nil || !endpoint.Enabled || endpoint.AdvanceConfig == "" {
+		return nil, false
+	}
+	config, err := database.ParseEndpointAdvanceConfig(endpoint.AdvanceConfig)
+	if err != nil || config.WebSocket == nil {
+		return nil, false
+	}
+	return &WebSocketMock{ProjectID: project.ID, Mode: project.Mode, EndpointID: endpoint.ID, Config: config.WebSocket}, true
+}
+
+// ServeWebSocket completes the upgrade handshake and runs the mock until either side closes
+// or ctx is done. Only handshake failures are returned; the response has not been written then.
+func ServeWebSocket(ctx context.Context, w http.ResponseWriter, req *http.Request, config *database.WebSocketConfig) error {
+	conn, err := acceptWebSocket(w, req)
+	if err != nil {
+		return err
+	}
+	defer conn.conn.Close()
+
+	serveWebSocketMock(ctx, conn, config)
+	return nil
+}
+
+// acceptWebSocket validates the upgrade request, takes over the connection and sends the
+// 101 Switching Protocols answer
+func acceptWebSocket(w http.ResponseWriter, req *http.Request) (*wsConn, error) {
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errors.New("unsupported Sec-WebSocket-Version, expected 13")
+	}
+	key := strings.TrimSpace(req.Header.Get("Sec-WebSocket-Key"))
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be upgraded")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	if _, err := rw.WriteString(handshake); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+// serveWebSocketMock sends the configured messages, answers pings and echoes client messages
+// in echo mode. A script closes with its close code once the last message is sent; in echo
+// mode the connection stays open until the client closes it.
+func serveWebSocketMock(ctx context.Context, conn *wsConn, config *database.WebSocketConfig) {
+	echo := config.Mode != database.WebSocketModeScript
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.readLoop(echo)
+	}()
+
+	if config.PingIntervalMs > 0 {
+		ticker := time.NewTicker(time.Duration(config.PingIntervalMs) * time.Millisecond)
+		defer ticker.Stop()
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if conn.writeFrame(wsOpPing, nil) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for _, message := range config.Messages {
+		if message.DelayMs > 0 {
+			timer := time.NewTimer(time.Duration(message.DelayMs) * time.Millisecond)
+			select {
+			case <-done:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				conn.writeClose(wsCloseNormal, "")
+				return
+			case <-timer.C:
+			}
+		}
+		opcode, payload := webSocketMessagePayload(message)
+		if conn.writeFrame(opcode, payload) != nil {
+			return
+		}
+	}
+
+	if echo {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			conn.writeClose(wsCloseNormal, "")
+		}
+		return
+	}
+
+	code := config.CloseCode
+	if code == 0 {
+		code = wsCloseNormal
+	}
+	conn.writeClose(code, config.CloseReason)
+	select {
+	case <-done:
+	case <-time.After(webSocketCloseWait):
+	}
+}
+
+// webSocketMessagePayload returns the opcode and bytes of a scripted message
+func webSocketMessagePayload(message database.WebSocketMessage) (byte, []byte) {
+	if message.Type == "binary" {
+		data, _ := base64.StdEncoding.DecodeString(message.Data)
+		return wsOpBinary, data
+	}
+	return wsOpText, []byte(message.Data)
+}
+
+// readLoop reads client frames until the connection closes: pings get pongs, a close frame is
+// answered with the same code, and in echo mode every message is sent back with its opcode
+func (c *wsConn) readLoop(echo bool) {
+	var message []byte
+	var messageOpcode byte
+
+	for {
+		frame, err := readWebSocketFrame(c.reader, true)
+		if err != nil {
+			switch {
+			case errors.Is(err, errWebSocketTooBig):
+				c.writeClose(wsCloseTooBig, "")
+			case errors.Is(err, errWebSocketProtocol):
+				c.writeClose(wsCloseProtocolError, "")
+			}
+			return
+		}
+
+		switch frame.opcode {
+		case wsOpPing:
+			c.writeFrame(wsOpPong, frame.payload)
+		case wsOpPong:
+		case wsOpClose:
+			code, reason := wsCloseNormal, ""
+			if len(frame.payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(frame.payload))
+				reason = string(frame.payload[2:])
+			}
+			c.writeClose(code, reason)
+			return
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			if frame.opcode == wsOpContinuation {
+				if messageOpcode == 0 {
+					c.writeClose(wsCloseProtocolError, "")
+					return
+				}
+			} else {
+				if messageOpcode != 0 {
+					c.writeClose(wsCloseProtocolError, "")
+					return
+				}
+				messageOpcode = frame.opcode
+			}
+			message = append(message, frame.payload...)
+			if len(message) > webSocketMaxMessageSize {
+				c.writeClose(wsCloseTooBig, "")
+				return
+			}
+			if frame.fin {
+				if echo && c.writeFrame(messageOpcode, message) != nil {
+					return
+				}
+				message, messageOpcode = nil, 0
+			}
+		default:
+			c.writeClose(wsCloseProtocolError, "")
+			return
+		}
+	}
+}
+
+// writeFrame sends one unfragmented frame; nothing is sent after the close frame
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if opcode == wsOpClose {
+		c.closeSent = true
+	}
+	_, err := c.conn.Write(encodeWebSocketFrame(opcode, payload, nil))
+	return err
+}
+
+// writeClose sends a close frame with the code and reason, once
+func (c *wsConn) writeClose(code int, reason string) {
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+	c.writeFrame(wsOpClose, payload)
+}
+
+// readWebSocketFrame reads one frame. Frames sent by clients must be masked (RFC 6455 5.1).
+func readWebSocketFrame(r io.Reader, requireMask bool) (wsFrame, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return wsFrame{}, err
+	}
+	frame := wsFrame{fin: header[0]&0x80 != 0, opcode: header[0] & 0x0F}
+	if header[0]&0x70 != 0 {
+		return wsFrame{}, fmt.Errorf("%w: reserved bits set", errWebSocketProtocol)
+	}
+	masked := header[1]&0x80 != 0
+	if requireMask && !masked {
+		return wsFrame{}, fmt.Errorf("%w: unmasked client frame", errWebSocketProtocol)
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if frame.opcode >= wsOpClose && (length > 125 || !frame.fin) {
+		return wsFrame{}, fmt.Errorf("%w: invalid control frame", errWebSocketProtocol)
+	}
+	if length > webSocketMaxMessageSize {
+		return wsFrame{}, errWebSocketTooBig
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return wsFrame{}, err
+		}
+	}
+	frame.payload = make([]byte, length)
+	if _, err := io.ReadFull(r, frame.payload); err != nil {
+		return wsFrame{}, err
+	}
+	if masked {
+		for i := range frame.payload {
+			frame.payload[i] ^= mask[i%4]
+		}
+	}
+	return frame, nil
+}
+
+// encodeWebSocketFrame builds a final frame. Server frames are sent unmasked (mask nil);
+// clients pass their masking key.
+func encodeWebSocketFrame(opcode byte, payload []byte, mask []byte) []byte {
+	frame := []byte{0x80 | opcode}
+	maskBit := byte(0)
+	if mask != nil {
+		maskBit = 0x80
+	}
+
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, maskBit|126, byte(length>>8), byte(length))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if mask == nil {
+		return append(frame, payload...)
+	}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+// wsTestClient is a minimal RFC 6455 client sending masked frames
+type wsTestClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWebSocket upgrades a connection to path on the test server
+func dialWebSocket(t *testing.T, server *httptest.Server, path string) *wsTestClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write([]byte("GET " + path + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"), "accept key from RFC 6455 1.3")
+	return &wsTestClient{conn: conn, reader: reader}
+}
+
+func (c *wsTestClient) send(t *testing.T, opcode byte, payload []byte) {
+	_, err := c.conn.Write(encodeWebSocketFrame(opcode, payload, []byte{1, 2, 3, 4}))
+	require.NoError(t, err)
+}
+
+func (c *wsTestClient) read(t *testing.T) wsFrame {
+	frame, err := readWebSocketFrame(c.reader, false)
+	require.NoError(t, err)
+	return frame
+}
+
+// newWebSocketTestServer serves the project's WebSocket mocks the way the mock handler does
+func newWebSocketTestServer(t *testing.T, service *MockService, alias string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mock, ok := service.FindWebSocketMock(alias, req.URL.Path)
+		if !IsWebSocketUpgrade(req) || !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if err := ServeWebSocket(context.Background(), w, req, mock.Config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServeWebSocket(t *testing.T) {
+	service, project := setupHandleRequestTest(t, "ws-mock")
+	_, err := database.CreateTestEndpointWithConfig(project.ID, "GET", "/echo",
+		`{"webSocket": {"mode": "echo", "messages": [{"data": "welcome"}]}}`)
+	require.NoError(t, err)
+	_, err = database.CreateTestEndpointWithConfig(project.ID, "GET", "/script",
+		`{"webSocket": {"mode": "script", "messages": [{"data": "one"}, {"type": "binary", "data": "AQI=", "delayMs": 10}], "closeCode": 4000, "closeReason": "done"}}`)
+	require.NoError(t, err)
+	_, err = database.CreateTestEndpoint(project.ID, "GET", "/plain")
+	require.NoError(t, err)
+
+	server := newWebSocketTestServer(t, service, project.Alias)
+
+	t.Run("Echo mode greets then echoes messages", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/echo")
+
+		frame := client.read(t)
+		assert.Equal(t, byte(wsOpText), frame.opcode)
+		assert.Equal(t, "welcome", string(frame.payload))
+
+		client.send(t, wsOpText, []byte("hello"))
+		frame = client.read(t)
+		assert.Equal(t, byte(wsOpText), frame.opcode)
+		assert.Equal(t, "hello", string(frame.payload))
+
+		client.send(t, wsOpPing, []byte("are you there"))
+		frame = client.read(t)
+		assert.Equal(t, byte(wsOpPong), frame.opcode)
+		assert.Equal(t, "are you there", string(frame.payload))
+
+		client.send(t, wsOpClose, []byte{0x03, 0xE8})
+		frame = client.read(t)
+		assert.Equal(t, byte(wsOpClose), frame.opcode)
+		assert.Equal(t, 1000, int(binary.BigEndian.Uint16(frame.payload)))
+	})
+
+	t.Run("Script mode sends its messages and closes", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/script")
+
+		assert.Equal(t, "one", string(client.read(t).payload))
+		frame := client.read(t)
+		assert.Equal(t, byte(wsOpBinary), frame.opcode)
+		assert.Equal(t, []byte{1, 2}, frame.payload)
+
+		frame = client.read(t)
+		require.Equal(t, byte(wsOpClose), frame.opcode)
+		assert.Equal(t, 4000, int(binary.BigEndian.Uint16(frame.payload)))
+		assert.Equal(t, "done", string(frame.payload[2:]))
+	})
+
+	t.Run("Unmasked client frames are a protocol error", func(t *testing.T) {
+		client := dialWebSocket(t, server, "/echo")
+		client.read(t)
+
+		_, err := client.conn.Write(encodeWebSocketFrame(wsOpText, []byte("hello"), nil))
+		require.NoError(t, err)
+		frame := client.read(t)
+		require.Equal(t, byte(wsOpClose), frame.opcode)
+		assert.Equal(t, wsCloseProtocolError, int(binary.BigEndian.Uint16(frame.payload)))
+	})
+
+	t.Run("Endpoints without webSocket config are not WebSocket mocks", func(t *testing.T) {
+		_, ok := service.FindWebSocketMock(project.Alias, "/plain")
+		assert.False(t, ok)
+	})
+}
+
+func TestIsWebSocketUpgrade(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	assert.True(t, IsWebSocketUpgrade(req))
+
+	req.Header.Set("Upgrade", "h2c")
+	assert.False(t, IsWebSocketUpgrade(req))
+}
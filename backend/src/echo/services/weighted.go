@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// selectWeightedResponse picks a response with probability proportional to its weight.
+// Weights come from each response's advance config and are evaluated against the request.
+// An unset weight counts as 1 and a negative one as 0; zero-weight responses are never picked
+// unless every weight is zero, in which case the choice falls back to uniform random.
+func selectWeightedResponse(responses []database.MockResponse, req *http.Request) *database.MockResponse {
+	weights := make([]float64, len(responses))
+	total := 0.0
+	for i, response := range responses {
+		expression := ""
+		if config, err := database.ParseResponseAdvanceConfig(response.AdvanceConfig); err == nil {
+			expression = config.Weight
+		}
+		weights[i] = evaluateWeight(expression, req)
+		total += weights[i]
+	}
+
+	if total <= 0 {
+		return &responses[pickRandomIndex(len(responses), req)]
+	}
+
+	target := pickRandomFloat(req) * total
+	for i, weight := range weights {
+		if target < weight {
+			return &responses[i]
+		}
+		target -= weight
+	}
+	return &responses[len(responses)-1]
+}
+
+// weightExpression is a parsed weight: a static value, or a condition choosing between two values
+type weightExpression struct {
+	condition  string
+	thenWeight float64
+	elseWeight float64
+}
+
+// parseWeightExpression parses the weight syntax. Supported forms:
+//
+//	"5"                                   static weight
+//	"query.debug ? 10 : 1"                10 when the query param is present, else 1
+//	"header.X-Tier == \"gold\" ? 8 : 2"   compare a header value (== or !=)
+//	"body.user.role != \"admin\" ? 3"     JSON body field (dot notation); else branch defaults to 0
+//
+// An empty expression weighs 1.
+func parseWeightExpression(expression string) (weightExpression, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return weightExpression{thenWeight: 1}, nil
+	}
+	if weight, err := strconv.ParseFloat(expression, 64); err == nil {
+		return weightExpression{thenWeight: weight}, nil
+	}
+
+	condition, branches, ok := strings.Cut(expression, "?")
+	if !ok {
+		return weightExpression{}, fmt.Errorf("weight %q must be a number or \"<condition> ? <weight> : <weight>\"", expression)
+	}
+	condition = strings.TrimSpace(condition)
+	if _, key, found := strings.Cut(condition, "."); !found || key == "" {
+		return weightExpression{}, fmt.Errorf("weight condition %q must reference query.<name>, header.<name> or body.<path>", condition)
+	}
+
+	parsed := weightExpression{condition: condition}
+	thenPart, elsePart, hasElse := strings.Cut(branches, ":")
+	var err error
+	if parsed.thenWeight, err = strconv.ParseFloat(strings.TrimSpace(thenPart), 64); err != nil {
+		return weightExpression{}, fmt.Errorf("weight %q has a non-numeric value", expression)
+	}
+	if hasElse {
+		if parsed.elseWeight, err = strconv.ParseFloat(strings.TrimSpace(elsePart), 64); err != nil {
+			return weightExpression{}, fmt.Errorf("weight %q has a non-numeric value", expression)
+		}
+	}
+	return parsed, nil
+}
+
+// evaluateWeight evaluates a weight expression against the request.
+// Invalid expressions and negative results weigh 0.
+func evaluateWeight(expression string, req *http.Request) float64 {
+	parsed, err := parseWeightExpression(expression)
+	if err != nil {
+		return 0
+	}
+	if parsed.condition == "" || evaluateWeightCondition(parsed.condition, req) {
+		return nonNegativeWeight(parsed.thenWeight)
+	}
+	return nonNegativeWeight(parsed.elseWeight)
+}
+
+// evaluateWeightCondition checks "<source>.<key>" for presence, or compares it with == / !=
+func evaluateWeightCondition(condition string, req *http.Request) bool {
+	operator := ""
+	reference, expected := condition, ""
+	for _, op := range []string{"!=", "=="} {
+		if left, right, found := strings.Cut(condition, op); found {
+			operator, reference, expected = op, strings.TrimSpace(left), strings.TrimSpace(right)
+			break
+		}
+	}
+	expected = strings.Trim(expected, `"'`)
+
+	actual, present := lookupRequestValue(reference, req)
+	switch operator {
+	case "==":
+		return present && actual == expected
+	case "!=":
+		return actual != expected
+	default:
+		return present
+	}
+}
+
+// lookupRequestValue resolves "query.<name>", "header.<name>" or "body.<path>" against the request
+func lookupRequestValue(reference string, req *http.Request) (string, bool) {
+	if req == nil {
+		return "", false
+	}
+	source, key, ok := strings.Cut(reference, ".")
+	if !ok || key == "" {
+		return "", false
+	}
+
+	switch strings.ToLower(source) {
+	case "query":
+		values := req.URL.Query()
+		_, present := values[key]
+		return values.Get(key), present
+	case "header":
+		values := req.Header.Values(key)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case "body":
+		if req.Body == nil {
+			return "", false
+		}
+		bodyData, ok := requestBodyObject(req)
+		if !ok {
+			return "", false
+		}
+		value := getNestedValue(bodyData, key)
+		return value, value != ""
+	default:
+		return "", false
+	}
+}
+
+func nonNegativeWeight(weight float64) float64 {
+	if weight < 0 {
+		return 0
+	}
+	return weight
+}
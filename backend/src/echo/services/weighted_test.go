@@ -0,0 +1,128 @@
+package services
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestEvaluateWeight(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/orders?debug=1", strings.NewReader(`{"user":{"role":"admin"}}`))
+	req.Header.Set("X-Tier", "gold")
+
+	tests := []struct {
+		expression string
+		expected   float64
+	}{
+		{"", 1},
+		{"2.5", 2.5},
+		{"-3", 0},
+		{"query.debug ? 10 : 1", 10},
+		{"query.missing ? 10 : 1", 1},
+		{`header.X-Tier == "gold" ? 8 : 2`, 8},
+		{`header.X-Tier != "gold" ? 8 : 2`, 2},
+		{`body.user.role == "admin" ? 4`, 4},
+		{`body.user.role == "guest" ? 4`, 0},
+		{"query.debug ? lots : 1", 0},
+		{"not an expression", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			assert.Equal(t, tt.expected, evaluateWeight(tt.expression, req))
+		})
+	}
+}
+
+func TestSelectWeightedResponse(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "normal", AdvanceConfig: `{"weight": "1"}`},
+		{ID: "debug", AdvanceConfig: `{"weight": "query.debug ? 3 : 0"}`},
+	}
+
+	t.Run("Weights are normalized against the total", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders?debug=1", nil)
+
+		// Total weight is 4: [0, 0.25) selects normal, [0.25, 1) selects debug
+		stubRandomFloat64(t, 0.2)
+		assert.Equal(t, "normal", selectWeightedResponse(responses, req).ID)
+		stubRandomFloat64(t, 0.3)
+		assert.Equal(t, "debug", selectWeightedResponse(responses, req).ID)
+	})
+
+	t.Run("Zero weight responses are never picked", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+
+		stubRandomFloat64(t, 0.99)
+		assert.Equal(t, "normal", selectWeightedResponse(responses, req).ID)
+	})
+
+	t.Run("Weighted mode is selected by the endpoint response mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders?debug=1", nil)
+
+		stubRandomFloat64(t, 0.9)
+		selected := selectResponseWithEndpoint("endpoint-weighted", responses, "weighted", req)
+		require.NotNil(t, selected)
+		assert.Equal(t, "debug", selected.ID)
+	})
+}
+
+func TestSelectWeightedResponse_Distribution(t *testing.T) {
+	responses := []database.MockResponse{
+		{ID: "success", AdvanceConfig: `{"weight": "9"}`},
+		{ID: "failure", AdvanceConfig: `{"weight": "1"}`},
+		{ID: "disabled", AdvanceConfig: `{"weight": "0"}`},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+
+	source := rand.New(rand.NewSource(42))
+	original := randomFloat64
+	randomFloat64 = source.Float64
+	t.Cleanup(func() { randomFloat64 = original })
+
+	const iterations = 20000
+	counts := map[string]int{}
+	for i := 0; i < iterations; i++ {
+		counts[selectWeightedResponse(responses, req).ID]++
+	}
+
+	assert.InDelta(t, 0.9, float64(counts["success"])/iterations, 0.02)
+	assert.InDelta(t, 0.1, float64(counts["failure"])/iterations, 0.02)
+	assert.Zero(t, counts["disabled"])
+
+	t.Run("Unset weights count as 1", func(t *testing.T) {
+		counts := map[string]int{}
+		unset := []database.MockResponse{{ID: "a"}, {ID: "b", AdvanceConfig: `{"weight": "3"}`}}
+		for i := 0; i < iterations; i++ {
+			counts[selectWeightedResponse(unset, req).ID]++
+		}
+		assert.InDelta(t, 0.25, float64(counts["a"])/iterations, 0.02)
+	})
+
+	t.Run("All-zero weights fall back to uniform", func(t *testing.T) {
+		original := randomIntn
+		randomIntn = source.Intn
+		t.Cleanup(func() { randomIntn = original })
+
+		counts := map[string]int{}
+		zero := []database.MockResponse{{ID: "a", AdvanceConfig: `{"weight": "0"}`}, {ID: "b", AdvanceConfig: `{"weight": "0"}`}}
+		for i := 0; i < iterations; i++ {
+			counts[selectWeightedResponse(zero, req).ID]++
+		}
+		assert.InDelta(t, 0.5, float64(counts["a"])/iterations, 0.02)
+	})
+}
+
+// stubRandomFloat64 makes the global float source return value for the rest of the test
+func stubRandomFloat64(t *testing.T, value float64) {
+	original := randomFloat64
+	randomFloat64 = func() float64 { return value }
+	t.Cleanup(func() { randomFloat64 = original })
+}
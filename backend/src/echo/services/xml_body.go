@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"beo-echo/backend/src/database"
+)
+
+// xmlNode is a parsed XML element. Names are local names: namespace prefixes are dropped so
+// SOAP paths like /Envelope/Body/GetUser match whatever prefix the client used.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	children []*xmlNode
+	text     strings.Builder // Character data of the element and its descendants
+}
+
+// xmlPathStep is one segment of a parsed XPath-like expression
+type xmlPathStep struct {
+	name       string // Element local name or "*"
+	index      int    // 1-based position among matching siblings, 0 for all
+	descendant bool   // Preceded by "//": match at any depth
+}
+
+// parseXMLBody decodes the body into a tree rooted at a synthetic document node
+func parseXMLBody(body []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	document := &xmlNode{}
+	stack := []*xmlNode{document}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			// Every open element collects the text of its whole subtree, in document order
+			for _, node := range stack[1:] {
+				node.text.Write(t)
+			}
+		}
+	}
+
+	if len(document.children) == 0 {
+		return nil, errors.New("XML body has no root element")
+	}
+	return document, nil
+}
+
+// parseXMLPath parses the supported XPath subset: absolute (/a/b) or relative (a/b) child steps,
+// "//" for descendants, "*" for any element, [n] 1-based positions, and a trailing @attr to
+// select an attribute instead of the element text
+func parseXMLPath(path string) ([]xmlPathStep, string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, "", errors.New("XPath is empty")
+	}
+
+	var steps []xmlPathStep
+	attribute := ""
+	descendant := false
+	for i, part := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if part == "" {
+			// An empty segment comes from "//"
+			if i == 0 && !strings.HasPrefix(path, "/") {
+				return nil, "", fmt.Errorf("invalid XPath %q", path)
+			}
+			descendant = true
+			continue
+		}
+		if strings.HasPrefix(part, "@") {
+			attribute = strings.TrimPrefix(part, "@")
+			break
+		}
+
+		step := xmlPathStep{name: part, descendant: descendant}
+		descendant = false
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, "", fmt.Errorf("unclosed bracket in XPath %q", path)
+			}
+			index, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || index < 1 {
+				return nil, "", fmt.Errorf("invalid position in XPath %q", path)
+			}
+			step.name, step.index = part[:open], index
+		}
+		if colon := strings.IndexByte(step.name, ':'); colon >= 0 {
+			step.name = step.name[colon+1:]
+		}
+		steps = append(steps, step)
+	}
+	return steps, attribute, nil
+}
+
+// xmlPathValues returns the text (or attribute values) of the nodes the path selects
+func xmlPathValues(document *xmlNode, path string) ([]string, error) {
+	steps, attribute, err := parseXMLPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []*xmlNode{document}
+	for _, step := range steps {
+		var next []*xmlNode
+		for _, node := range current {
+			candidates := node.children
+			if step.descendant {
+				candidates = xmlDescendants(node)
+			}
+			position := 0
+			for _, candidate := range candidates {
+				if step.name != "*" && candidate.name != step.name {
+					continue
+				}
+				position++
+				if step.index == 0 || step.index == position {
+					next = append(next, candidate)
+				}
+			}
+		}
+		current = next
+	}
+
+	var values []string
+	for _, node := range current {
+		if attribute == "" {
+			values = append(values, strings.TrimSpace(node.text.String()))
+		} else if value, ok := node.attrs[attribute]; ok {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// xmlDescendants lists every element below the node in document order
+func xmlDescendants(node *xmlNode) []*xmlNode {
+	var nodes []*xmlNode
+	for _, child := range node.children {
+		nodes = append(nodes, child)
+		nodes = append(nodes, xmlDescendants(child)...)
+	}
+	return nodes
+}
+
+// matchXMLBodyRule evaluates the rule key as an XPath against an XML body and compares the
+// selected node text with matchRuleValue, e.g. key "//GetUserRequest/UserId" equals "42".
+// Bodies that are not valid XML, and paths that select nothing, do not match.
+func matchXMLBodyRule(rule database.MockRule, req *http.Request) bool {
+	bodyBytes, err := requestBodyBytes(req)
+	if err != nil || len(bytes.TrimSpace(bodyBytes)) == 0 {
+		return false
+	}
+
+	document, err := parseXMLBody(bodyBytes)
+	if err != nil {
+		return false
+	}
+
+	values, err := xmlPathValues(document, rule.Key)
+	if err != nil || len(values) == 0 {
+		return false
+	}
+
+	return matchSelectedValues(rule.Operator, values, rule.Value)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"beo-echo/backend/src/database"
+)
+
+func TestMatchXMLBodyRule(t *testing.T) {
+	soap := `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">
+  <soap:Body>
+    <u:GetUserRequest version="2">
+      <u:UserId>42</u:UserId>
+      <u:Roles><u:Role>admin</u:Role><u:Role>billing</u:Role></u:Roles>
+    </u:GetUserRequest>
+  </soap:Body>
+</soap:Envelope>`
+
+	match := func(body, key, operator, value string) bool {
+		req := httptest.NewRequest(http.MethodPost, "/soap", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/xml")
+		return matchXMLBodyRule(database.MockRule{Type: "xml_body", Key: key, Operator: operator, Value: value}, req)
+	}
+
+	t.Run("Absolute path ignores namespace prefixes", func(t *testing.T) {
+		assert.True(t, match(soap, "/Envelope/Body/GetUserRequest/UserId", "equals", "42"))
+		assert.True(t, match(soap, "/soap:Envelope/soap:Body/u:GetUserRequest/u:UserId", "equals", "42"))
+		assert.False(t, match(soap, "/Envelope/Body/GetUserRequest/UserId", "equals", "7"))
+	})
+
+	t.Run("Descendants, positions and attributes", func(t *testing.T) {
+		assert.True(t, match(soap, "//UserId", "equals", "42"))
+		assert.True(t, match(soap, "//Roles/Role[2]", "equals", "billing"))
+		assert.True(t, match(soap, "//Role", "equals", "billing"), "any selected node may match")
+		assert.False(t, match(soap, "//Role", "not_equals", "admin"))
+		assert.True(t, match(soap, "//GetUserRequest/@version", "equals", "2"))
+		assert.True(t, match(soap, "/Envelope/Body/*/UserId", "regex", `^\d+$`))
+	})
+
+	t.Run("Element text includes its children", func(t *testing.T) {
+		assert.True(t, match(soap, "//Roles", "equals", "adminbilling"))
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		assert.False(t, match(soap, "//Missing", "not_equals", "x"), "paths selecting nothing never match")
+		assert.False(t, match(soap, "//Role[9]", "equals", "admin"))
+		assert.False(t, match(`{"UserId":"42"}`, "//UserId", "equals", "42"), "JSON is not XML")
+		assert.False(t, match(`<Envelope><UserId>42</Envelope>`, "//UserId", "equals", "42"), "malformed XML")
+		assert.False(t, match("", "//UserId", "equals", ""))
+		assert.False(t, match(soap, "//Role[x]", "equals", "admin"), "invalid XPath")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"beo-echo/backend/src/database"
 	"beo-echo/backend/src/echo/repositories"
 	"beo-echo/backend/src/logs/services"
+	"errors"
 	"net/http"
 	"strconv"
 	"sync"
@@ -37,7 +38,8 @@ func LogService() *services.LogService {
 	return logService
 }
 
-// GetLogsHandler handles retrieving logs with pagination
+// GetLogsHandler handles retrieving logs with pagination. Optional filters: method, path
+// (substring), status, matched, endpointId, responseId, mode, and since/until (RFC 3339).
 func GetLogsHandler(c *gin.Context) {
 	EnsureLogService()
 	if logService == nil {
@@ -60,8 +62,17 @@ func GetLogsHandler(c *gin.Context) {
 		return
 	}
 
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
 	// Get logs
-	logs, total, err := logService.GetPaginatedLogs(page, pageSize, projectID)
+	logs, total, err := logService.GetPaginatedLogs(page, pageSize, projectID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -78,6 +89,42 @@ func GetLogsHandler(c *gin.Context) {
 	})
 }
 
+// parseLogFilter reads the log listing filters from the query string
+func parseLogFilter(c *gin.Context) (repositories.LogFilter, error) {
+	filter := repositories.LogFilter{
+		Method:     c.Query("method"),
+		Path:       c.Query("path"),
+		EndpointID: c.Query("endpointId"),
+		ResponseID: c.Query("responseId"),
+		Mode:       c.Query("mode"),
+	}
+
+	if value := c.Query("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, errors.New("status must be a number")
+		}
+		filter.Status = status
+	}
+	if value := c.Query("matched"); value != "" {
+		matched, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("matched must be true or false")
+		}
+		filter.Matched = &matched
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, errors.New(name + " must be an RFC 3339 time, e.g. 2026-01-02T15:04:05Z")
+			}
+			*target = parsed
+		}
+	}
+	return filter, nil
+}
+
 // StreamLogsHandler handles streaming logs using Server-Sent Events
 func StreamLogsHandler(c *gin.Context) {
 	EnsureLogService()
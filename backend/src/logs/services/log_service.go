@@ -6,15 +6,22 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
 	"beo-echo/backend/src/database"
 	"beo-echo/backend/src/echo/repositories"
+	systemConfig "beo-echo/backend/src/systemConfigs"
 )
 
+// logPruneInterval is how often the retention limits are applied to a project's saved logs
+const logPruneInterval = 10 * time.Second
+
 // LogService handles log data retrieval and streaming
 type LogService struct {
 	Repo             *repositories.LogRepository
 	subscribers      map[string][]chan database.RequestLog
 	subscribersMutex sync.RWMutex
+	lastPrune        sync.Map // Project ID -> time.Time of the last retention run
 }
 
 // NewLogService creates a new log service
@@ -25,8 +32,8 @@ func NewLogService(repo *repositories.LogRepository) *LogService {
 	}
 }
 
-// GetPaginatedLogs retrieves logs with pagination
-func (s *LogService) GetPaginatedLogs(page, pageSize int, projectID string) ([]database.RequestLog, int64, error) {
+// GetPaginatedLogs retrieves logs matching the filter with pagination
+func (s *LogService) GetPaginatedLogs(page, pageSize int, projectID string, filter repositories.LogFilter) ([]database.RequestLog, int64, error) {
 	// Default to page 1 if invalid
 	if page < 1 {
 		page = 1
@@ -37,7 +44,7 @@ func (s *LogService) GetPaginatedLogs(page, pageSize int, projectID string) ([]d
 		pageSize = 100
 	}
 
-	return s.Repo.GetLogs(page, pageSize, projectID)
+	return s.Repo.GetLogs(page, pageSize, projectID, filter)
 }
 
 // GetLatestLogs retrieves the most recent logs
@@ -126,6 +133,39 @@ func (s *LogService) ClearLogs(projectID string) (int64, error) {
 	return s.Repo.ClearNonBookmarkedLogs(projectID)
 }
 
+// EnforceRetention deletes a project's saved logs beyond the LOG_RETENTION_DAYS and
+// LOG_MAX_ENTRIES_PER_PROJECT limits. It is called after every saved log and runs at most once
+// per logPruneInterval for each project.
+func (s *LogService) EnforceRetention(projectID string) {
+	now := time.Now()
+	if last, ok := s.lastPrune.Load(projectID); ok && now.Sub(last.(time.Time)) < logPruneInterval {
+		return
+	}
+	s.lastPrune.Store(projectID, now)
+
+	if _, err := s.PruneLogs(projectID, now); err != nil {
+		log.Error().Err(err).Str("project_id", projectID).Msg("Failed to apply request log retention")
+	}
+}
+
+// PruneLogs applies the retention limits to a project's saved logs as of now
+func (s *LogService) PruneLogs(projectID string, now time.Time) (int64, error) {
+	retentionDays, err := systemConfig.GetSystemConfigWithType[int](systemConfig.LOG_RETENTION_DAYS)
+	if err != nil {
+		return 0, err
+	}
+	maxEntries, err := systemConfig.GetSystemConfigWithType[int](systemConfig.LOG_MAX_ENTRIES_PER_PROJECT)
+	if err != nil {
+		return 0, err
+	}
+
+	var olderThan time.Time
+	if retentionDays > 0 {
+		olderThan = now.AddDate(0, 0, -retentionDays)
+	}
+	return s.Repo.PruneLogs(projectID, maxEntries, olderThan)
+}
+
 // FormatSSEEvent formats a log as a Server-Sent Event message
 func FormatSSEEvent(log database.RequestLog, eventType string) string {
 	// Serialize the log to JSON
@@ -9,6 +9,7 @@ import (
 	"beo-echo/backend/src/utils"
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,38 +19,65 @@ import (
 	"github.com/andybalholm/brotli"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
 	"github.com/rs/zerolog/log"
 
 	"gorm.io/gorm"
 )
 
-// bodyWriter wraps gin.ResponseWriter to capture the response body
+// bodyWriter wraps gin.ResponseWriter to capture the response body, the first limit bytes of
+// it when limit is positive so streamed responses don't grow the log without bound
 type bodyWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	body  *bytes.Buffer
+	limit int
 }
 
 func (w bodyWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)                  // Copy to buffer
+	captured := b
+	if w.limit > 0 {
+		captured = b[:min(len(b), max(w.limit-w.body.Len(), 0))]
+	}
+	w.body.Write(captured)           // Copy to buffer
 	return w.ResponseWriter.Write(b) // Write as usual
 }
 
+// limitLogBody reads one byte past limit from a decompressing reader, so a small compressed
+// body can't expand without bound while the cap can still tell it was cut. 0 reads everything.
+func limitLogBody(reader io.Reader, limit int) io.Reader {
+	if limit <= 0 {
+		return reader
+	}
+	return io.LimitReader(reader, int64(limit)+1)
+}
+
 // RequestLoggerMiddleware logs each HTTP request and response
 func RequestLoggerMiddleware(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
-		// Clone request body
+		// Clone request body, at most MAX_REQUEST_BODY_BYTES of it; the rest is left unread
+		// so handlers still see (and reject) the whole body
 		var requestBody string
 		if c.Request.Body != nil {
-			bodyBytes, _ := io.ReadAll(c.Request.Body)
+			var source io.Reader = c.Request.Body
+			if limit, err := systemConfig.GetSystemConfigWithType[int](systemConfig.MAX_REQUEST_BODY_BYTES); err == nil && limit > 0 {
+				source = io.LimitReader(c.Request.Body, int64(limit))
+			}
+			bodyBytes, _ := io.ReadAll(source)
 			requestBody = string(bodyBytes)
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(bodyBytes), c.Request.Body), c.Request.Body}
 		}
 
 		// Wrap response writer
 		respBodyBuf := new(bytes.Buffer)
 		bw := &bodyWriter{body: respBodyBuf, ResponseWriter: c.Writer}
+		if limit, err := systemConfig.GetSystemConfigWithType[int](systemConfig.LOG_MAX_BODY_BYTES); err == nil {
+			bw.limit = limit
+		}
 		c.Writer = bw
 
 		// Continue
@@ -63,6 +91,8 @@ func RequestLoggerMiddleware(db *gorm.DB) gin.HandlerFunc {
 		executionMode, _ := c.Get(handler.KeyExecutionMode)
 		matched, _ := c.Get(handler.KeyMatched)
 		path, _ := c.Get(handler.KeyPath)
+		endpointID, _ := c.Get(handler.KeyEndpointID)
+		responseID, _ := c.Get(handler.KeyResponseID)
 
 		// If no project ID, skip logging
 		if projectID == nil || projectID == "" || path == nil {
@@ -76,25 +106,51 @@ func RequestLoggerMiddleware(db *gorm.DB) gin.HandlerFunc {
 		responseBody = respBodyBuf.String()
 		contentEncoding := c.Writer.Header().Get("Content-Encoding")
 
-		if contentEncoding != "" {
+		// Bytes the client got past the captured ones were dropped from the log
+		responseBodyTruncated := bw.limit > 0 && c.Writer.Size() > bw.limit
+		if decoded, ok := c.Get(handler.KeyResponseBody); ok {
+			responseBody = toString(decoded)
+			truncated, _ := c.Get(handler.KeyResponseBodyTruncated)
+			responseBodyTruncated = toBool(truncated)
+		} else if contentEncoding != "" {
 			compressedData := respBodyBuf.Bytes()
 
 			switch strings.ToLower(contentEncoding) {
 			case "gzip":
 				if reader, err := gzip.NewReader(bytes.NewReader(compressedData)); err == nil {
-					if decompressed, err := io.ReadAll(reader); err == nil {
+					if decompressed, err := io.ReadAll(limitLogBody(reader, bw.limit)); err == nil {
 						responseBody = string(decompressed)
 					}
 					reader.Close()
 				}
 			case "br":
 				decompressed := brotli.NewReader(bytes.NewReader(compressedData))
-				if data, err := io.ReadAll(decompressed); err == nil {
+				if data, err := io.ReadAll(limitLogBody(decompressed, bw.limit)); err == nil {
 					responseBody = string(data)
 				}
+			case "deflate":
+				if reader, err := zlib.NewReader(bytes.NewReader(compressedData)); err == nil {
+					if decompressed, err := io.ReadAll(limitLogBody(reader, bw.limit)); err == nil {
+						responseBody = string(decompressed)
+					}
+					reader.Close()
+				}
+			case "zstd":
+				if reader, err := zstd.NewReader(bytes.NewReader(compressedData), zstd.WithDecoderConcurrency(1)); err == nil {
+					if decompressed, err := io.ReadAll(limitLogBody(reader, bw.limit)); err == nil {
+						responseBody = string(decompressed)
+					}
+					reader.Close()
+				}
 			}
 		}
 
+		// Decoded bodies are capped like captured ones, LOG_MAX_BODY_BYTES applies to what is stored
+		if bw.limit > 0 && len(responseBody) > bw.limit {
+			responseBody = responseBody[:bw.limit]
+			responseBodyTruncated = true
+		}
+
 		// Save log with hashed JWTs
 		logEntry := &database.RequestLog{
 			ID:              id,
@@ -110,8 +166,11 @@ func RequestLoggerMiddleware(db *gorm.DB) gin.HandlerFunc {
 			LatencyMS:       int(latency),
 			ExecutionMode:   database.ProjectMode(toString(executionMode)),
 			Matched:         toBool(matched),
+			EndpointID:      toString(endpointID),
+			ResponseID:      toString(responseID),
 			CreatedAt:       time.Now(),
 		}
+		logEntry.ResponseBodyTruncated = responseBodyTruncated
 
 		entry, err := json.Marshal(logEntry)
 		if err != nil {
@@ -144,6 +203,9 @@ func RequestLoggerMiddleware(db *gorm.DB) gin.HandlerFunc {
 				log.Error().Err(err).
 					Str("project_id", toString(projectID)).
 					Msg("Failed to save request log to database")
+			} else if ls := handlerLogs.LogService(); ls != nil {
+				// Keep saved logs within the retention limits
+				ls.EnforceRetention(logEntry.ProjectID)
 			}
 		}
 	}
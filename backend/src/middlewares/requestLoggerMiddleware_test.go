@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"beo-echo/backend/src/database"
+	"beo-echo/backend/src/echo/handler"
+	handlerLogs "beo-echo/backend/src/logs/handlers"
+	systemConfig "beo-echo/backend/src/systemConfigs"
+)
+
+func TestBodyWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("captures at most limit bytes", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		w := bodyWriter{ResponseWriter: c.Writer, body: new(bytes.Buffer), limit: 8}
+
+		for _, chunk := range []string{"01234", "56789", "abcdef"} {
+			n, err := w.Write([]byte(chunk))
+			assert.NoError(t, err)
+			assert.Equal(t, len(chunk), n)
+		}
+
+		assert.Equal(t, "01234567", w.body.String())
+		assert.Equal(t, "0123456789abcdef", recorder.Body.String(), "the client gets the whole body")
+	})
+
+	t.Run("zero captures the whole body", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		w := bodyWriter{ResponseWriter: c.Writer, body: new(bytes.Buffer)}
+
+		w.Write([]byte(strings.Repeat("x", 4096)))
+		assert.Equal(t, 4096, w.body.Len())
+	})
+}
+
+// setupDecodedLogProject serves a forwarder project whose upstream answers a gzip-compressed
+// 64KB body, decoded for request logs, with logs saved and capped at 1024 bytes
+func setupDecodedLogProject(t *testing.T, alias string) (router *gin.Engine, project *database.Project, plain, compressed []byte) {
+	gin.SetMode(gin.TestMode)
+	database.SetupTestEnvironment(t)
+
+	setup, err := database.InitTestWorkspaceWithProject(alias+"@example.com", "Log User", "Log Workspace", "Log Project", alias)
+	require.NoError(t, err)
+	t.Cleanup(setup.Cleanup)
+
+	plain = bytes.Repeat([]byte("a"), 64*1024)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(plain)
+	gz.Close()
+	compressed = buf.Bytes()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	t.Cleanup(upstream.Close)
+
+	target := &database.ProxyTarget{ProjectID: setup.Project.ID, URL: upstream.URL}
+	require.NoError(t, database.DB.Create(target).Error)
+	require.NoError(t, database.DB.Model(setup.Project).Updates(map[string]interface{}{
+		"mode":            database.ModeForwarder,
+		"active_proxy_id": target.ID,
+		"advance_config":  `{"proxyDecodeResponse": true}`,
+	}).Error)
+
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.LOG_MAX_BODY_BYTES, "1024"))
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.AUTO_SAVE_LOGS_IN_DB_ENABLED, "true"))
+	t.Cleanup(func() {
+		systemConfig.SetSystemConfig(systemConfig.LOG_MAX_BODY_BYTES, "1048576")
+		systemConfig.SetSystemConfig(systemConfig.AUTO_SAVE_LOGS_IN_DB_ENABLED, "false")
+		database.DB.Where("project_id = ?", setup.Project.ID).Delete(&database.RequestLog{})
+	})
+
+	handler.InitMockService()
+	handlerLogs.InitLogService()
+	router = gin.New()
+	router.Use(RequestLoggerMiddleware(database.DB))
+	router.Any("/:project/*path", handler.MockRequestHandler)
+	return router, setup.Project, plain, compressed
+}
+
+// requestDecodedLogProject sends a gzip-accepting request and returns what the client received
+func requestDecodedLogProject(router *gin.Engine, path string) []byte {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder.Body.Bytes()
+}
+
+func TestRequestLoggerMiddleware_CapsDecodedBodies(t *testing.T) {
+	router, project, plain, compressed := setupDecodedLogProject(t, "log-cap")
+
+	assert.Equal(t, compressed, requestDecodedLogProject(router, "/log-cap/data"), "the client gets the whole upstream body")
+	var entry database.RequestLog
+	require.NoError(t, database.DB.Where("project_id = ?", project.ID).First(&entry).Error)
+	assert.Equal(t, string(plain[:1024]), entry.ResponseBody, "the decoded body is capped at LOG_MAX_BODY_BYTES")
+	assert.True(t, entry.ResponseBodyTruncated)
+}
+
+func TestRequestLoggerMiddleware_RetentionWithDecodedBodies(t *testing.T) {
+	router, project, _, _ := setupDecodedLogProject(t, "log-retention")
+	require.NoError(t, systemConfig.SetSystemConfig(systemConfig.LOG_MAX_ENTRIES_PER_PROJECT, "2"))
+	t.Cleanup(func() { systemConfig.SetSystemConfig(systemConfig.LOG_MAX_ENTRIES_PER_PROJECT, "10000") })
+
+	for i := 0; i < 4; i++ {
+		requestDecodedLogProject(router, "/log-retention/data")
+	}
+	_, err := handlerLogs.LogService().PruneLogs(project.ID, time.Now())
+	require.NoError(t, err)
+
+	var entries []database.RequestLog
+	require.NoError(t, database.DB.Where("project_id = ?", project.ID).Find(&entries).Error)
+	require.Len(t, entries, 2, "LOG_MAX_ENTRIES_PER_PROJECT applies to decoded logs")
+	for _, entry := range entries {
+		assert.Len(t, entry.ResponseBody, 1024, "every kept row is capped at LOG_MAX_BODY_BYTES")
+		assert.True(t, entry.ResponseBodyTruncated)
+	}
+}
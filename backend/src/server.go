@@ -43,6 +43,8 @@ import (
 func SetupRouter() *gin.Engine {
 	// Create Gin router with default middleware
 	router := gin.Default()
+	// Accept cleartext HTTP/2 (h2c) so gRPC clients can reach mock projects
+	router.UseH2C = true
 
 	// middleware to log requests to the database
 	router.Use(middlewares.RequestLoggerMiddleware(database.DB))
@@ -67,14 +69,22 @@ func SetupRouter() *gin.Engine {
 	})
 
 	// Configure CORS
-	router.Use(cors.New(cors.Config{
+	corsMiddleware := cors.New(cors.Config{
 		AllowOrigins:     []string{lib.CORS_ORIGIN},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "Accept"},
 		ExposeHeaders:    []string{"Content-Range", "X-Content-Range"},
 		AllowCredentials: false, // No need for credentials since we use localStorage
 		// MaxAge:           12 * time.Hour,
-	}))
+	})
+	router.Use(func(c *gin.Context) {
+		// The mock route decides per project, see MockCORSMiddleware
+		if c.FullPath() == handler.MockRoutePath {
+			c.Next()
+			return
+		}
+		corsMiddleware(c)
+	})
 
 	// Rate limiting middleware - DISABLED for now due to stability issues
 	// TODO: Re-enable once rate limiting middleware is stable
@@ -216,12 +226,31 @@ func SetupRouter() *gin.Engine {
 				projectRoutes.GET("/advance-config", project.GetProjectAdvanceConfigHandler)
 				projectRoutes.PUT("/advance-config", project.UpdateProjectAdvanceConfigHandler)
 
+				// Live request metrics
+				projectRoutes.GET("/metrics", project.GetProjectMetricsHandler)
+
+				// Configuration validation
+				projectRoutes.GET("/validate-config", project.ValidateProjectConfigHandler)
+
+				// Fixture store (durable key-value state for mock responses)
+				projectRoutes.GET("/fixtures", project.ListFixturesHandler)
+				projectRoutes.PUT("/fixtures/:key", project.SetFixtureHandler)
+				projectRoutes.PATCH("/fixtures/:key", project.PatchFixtureHandler)
+				projectRoutes.DELETE("/fixtures/:key", project.DeleteFixtureHandler)
+				projectRoutes.DELETE("/fixtures", project.ResetFixturesHandler)
+
+				// Session store (in-memory per-client state for templated responses)
+				projectRoutes.GET("/sessions", project.ListSessionsHandler)
+				projectRoutes.DELETE("/sessions/:key", project.DeleteSessionHandler)
+				projectRoutes.DELETE("/sessions", project.ResetSessionsHandler)
+
 				// Endpoint management
 				projectRoutes.GET("/endpoints", endpoint.ListEndpointsHandler)
 				projectRoutes.POST("/endpoints", endpoint.CreateEndpointHandler)
 				projectRoutes.GET("/endpoints/:id", endpoint.GetEndpointHandler)
 				projectRoutes.PUT("/endpoints/:id", endpoint.UpdateEndpointHandler)
 				projectRoutes.DELETE("/endpoints/:id", endpoint.DeleteEndpointHandler)
+				projectRoutes.POST("/endpoints/:id/reset-state", endpoint.ResetEndpointStateHandler)
 
 				// Response management
 				projectRoutes.GET("/endpoints/:id/responses", response.ListResponsesHandler)
@@ -251,6 +280,7 @@ func SetupRouter() *gin.Engine {
 				projectRoutes.GET("/logs", handlerLogs.GetLogsHandler)
 				projectRoutes.GET("/logs/stream", handlerLogs.StreamLogsHandler)
 				projectRoutes.DELETE("/logs/clear", handlerLogs.ClearLogsHandler)
+				projectRoutes.POST("/logs/:logId/replay", project.ReplayRequestLogHandler)
 
 				// Bookmark Logs management
 				projectRoutes.GET("/logs/bookmark", handlerLogs.GetBookmarksHandler)
@@ -277,7 +307,7 @@ func SetupRouter() *gin.Engine {
 	{
 		// This handler will catch any request that doesn't match the above routes
 		// particularly targeting project-specific mock endpoints
-		mockProjectGroup.Any("/:project/*path", handler.MockRequestHandler)
+		mockProjectGroup.Any(handler.MockRoutePath, handler.MockCORSMiddleware(corsMiddleware), handler.MockRequestHandler)
 	}
 
 	return router
@@ -13,6 +13,9 @@ const (
 	FEATURE_OAUTH_AUTO_REGISTER        = "FEATURE_OAUTH_AUTO_REGISTER" // Controls whether new users can register through OAuth
 
 	AUTO_SAVE_LOGS_IN_DB_ENABLED = "AUTO_SAVE_LOGS_IN_DB_ENABLED" // Enable auto-saving of logs
+	LOG_RETENTION_DAYS           = "LOG_RETENTION_DAYS"           // Days saved request logs are kept (0 = forever)
+	LOG_MAX_ENTRIES_PER_PROJECT  = "LOG_MAX_ENTRIES_PER_PROJECT"  // Saved request logs kept per project (0 = unlimited)
+	LOG_MAX_BODY_BYTES           = "LOG_MAX_BODY_BYTES"           // Response body bytes captured for a request log (0 = whole body)
 
 	// Workspace and Project Limits
 	AUTO_CREATE_WORKSPACE_ON_REGISTER = "AUTO_CREATE_WORKSPACE_ON_REGISTER" // Automatically create a workspace for new users
@@ -25,6 +28,25 @@ const (
 	DEFAULT_RESPONSE_PROJECT_NOT_FOUND      = "This is a default response from Beo Echo mock service."       // Default response when project is not found
 	DEFAULT_RESPONSE_ENDPOINT_NOT_FOUND     = "This is a default response from Beo Echo mock service."       // Default response when endpoint is not found
 	DEFAULT_RESPONSE_NO_RESPONSE_CONFIGURED = "This endpoint exists but no specific response is configured." // Default response when no response is configured
+	DEFAULT_RESPONSE_TEMPLATE_4XX           = "DEFAULT_RESPONSE_TEMPLATE_4XX"                                // Body template shared by all default 4xx responses
+	DEFAULT_RESPONSE_TEMPLATE_5XX           = "DEFAULT_RESPONSE_TEMPLATE_5XX"                                // Body template shared by all default 5xx responses
+	DEFAULT_RESPONSE_NOT_FOUND_STATUS       = "DEFAULT_RESPONSE_NOT_FOUND_STATUS"                            // Status of the default response for unknown projects and endpoints
+
+	// Mock Behaviour Configuration
+	RANDOM_SEED_HEADER_ENABLED = "RANDOM_SEED_HEADER_ENABLED" // Allow the beo-echo-random-seed header to seed random response selection
+	MOCK_ENVIRONMENT           = "MOCK_ENVIRONMENT"           // Environment of this server used to pick environment-tagged responses
+	DEBUG_ROUTES_ENABLED       = "DEBUG_ROUTES_ENABLED"       // Serve the route table on the reserved /__beo-echo path of each project
+	HEALTH_CHECK_PATH          = "HEALTH_CHECK_PATH"          // Path under each project alias that always answers 200, whatever the project mode
+	GEOIP_DATABASE_PATH        = "GEOIP_DATABASE_PATH"        // CSV file mapping networks to country/region codes for geo rules
+	TRUSTED_PROXIES            = "TRUSTED_PROXIES"            // Peers whose X-Forwarded-For / X-Real-IP headers name the client for ip, geo and rate-limit rules
+	ROUND_ROBIN_PERSISTENT     = "ROUND_ROBIN_PERSISTENT"     // Keep round-robin positions in the database, shared across restarts and replicas
+	RESPONSE_FILES_DIR         = "RESPONSE_FILES_DIR"         // Base directory for response body files
+	MAX_REQUEST_BODY_BYTES     = "MAX_REQUEST_BODY_BYTES"     // Largest request body read for rules and proxying; larger ones get 413
+
+	// Proxy Configuration
+	PROXY_TLS_VERIFY       = "PROXY_TLS_VERIFY"       // Verify upstream TLS certificates in proxy/forwarder mode
+	PROXY_CA_CERT_PATHS    = "PROXY_CA_CERT_PATHS"    // PEM CA bundles trusted for upstreams in addition to the system roots
+	PROXY_BUFFER_MAX_BYTES = "PROXY_BUFFER_MAX_BYTES" // Upstream bodies up to this size are buffered, larger ones are streamed to the client
 
 	// Landing Page Configuration
 	LANDING_PAGE_ENABLED = "LANDING_PAGE_ENABLED" // Enable/disable landing page
@@ -78,6 +100,24 @@ var DefaultConfigSettings = map[SystemConfigKey]ConfigSetting{
 		Description: "Automatically persist request logs to database (may affect performance)",
 		Category:    "Logging",
 	},
+	LOG_RETENTION_DAYS: {
+		Type:        TypeNumber,
+		Value:       "30",
+		Description: "Days saved request logs are kept before being deleted; bookmarked logs are kept. 0 keeps them forever",
+		Category:    "Logging",
+	},
+	LOG_MAX_ENTRIES_PER_PROJECT: {
+		Type:        TypeNumber,
+		Value:       "10000",
+		Description: "Saved request logs kept per project, oldest deleted first; bookmarked logs are kept. 0 is unlimited",
+		Category:    "Logging",
+	},
+	LOG_MAX_BODY_BYTES: {
+		Type:        TypeNumber,
+		Value:       "1048576",
+		Description: "Response body bytes kept in a request log; the client still gets the whole body. 0 keeps whole bodies",
+		Category:    "Logging",
+	},
 
 	// Workspace and Project Limits
 	AUTO_CREATE_WORKSPACE_ON_REGISTER: {
@@ -106,6 +146,102 @@ var DefaultConfigSettings = map[SystemConfigKey]ConfigSetting{
 		HideValue:   true,
 	},
 
+	// Default Response Templates
+	DEFAULT_RESPONSE_TEMPLATE_4XX: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Body template for default 4xx responses. Supports {{status}}, {{status_text}} and {{message}}; empty keeps the built-in body",
+		Category:    "Responses",
+	},
+	DEFAULT_RESPONSE_TEMPLATE_5XX: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Body template for default 5xx responses. Supports {{status}}, {{status_text}} and {{message}}; empty keeps the built-in body",
+		Category:    "Responses",
+	},
+	DEFAULT_RESPONSE_NOT_FOUND_STATUS: {
+		Type:        TypeNumber,
+		Value:       "404",
+		Description: "Status code of the default response for unknown projects and endpoints; set 200 for clients relying on the old behaviour. Projects can override it with notFoundResponse",
+		Category:    "Responses",
+	},
+
+	// Mock Behaviour
+	RANDOM_SEED_HEADER_ENABLED: {
+		Type:        TypeBoolean,
+		Value:       "false",
+		Description: "Allow the beo-echo-random-seed request header to seed random response selection; a valid seed takes precedence over the global random source",
+		Category:    "Mock",
+	},
+	GEOIP_DATABASE_PATH: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Path of the GeoIP database used by geo rules: a CSV of network,country[,region] lines (e.g. 203.0.113.0/24,ID,JK); empty disables geo matching",
+		Category:    "Mock",
+	},
+	TRUSTED_PROXIES: {
+		Type:        TypeString,
+		Value:       "*",
+		Description: "Comma-separated IPs or CIDRs of the reverse proxies whose X-Forwarded-For and X-Real-IP headers are trusted to name the client address (e.g. 10.0.0.0/8,127.0.0.1); * trusts every peer, empty trusts none",
+		Category:    "Mock",
+	},
+	ROUND_ROBIN_PERSISTENT: {
+		Type:        TypeBoolean,
+		Value:       "false",
+		Description: "Store round-robin positions in the database so they survive restarts and are shared by replicas using the same database; off keeps them in memory",
+		Category:    "Mock",
+	},
+	RESPONSE_FILES_DIR: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Directory that response body_file_path values are resolved in; paths may not leave it. Empty uses configs/response-files",
+		Category:    "Mock",
+	},
+	MAX_REQUEST_BODY_BYTES: {
+		Type:        TypeNumber,
+		Value:       "10485760",
+		Description: "Largest request body in bytes that mock and proxy requests may send; larger bodies are answered 413 Payload Too Large. 0 is unlimited",
+		Category:    "Mock",
+	},
+	DEBUG_ROUTES_ENABLED: {
+		Type:        TypeBoolean,
+		Value:       "false",
+		Description: "Answer /<alias>/__beo-echo/routes with the project's endpoints, responses and routing (?method=&path=) instead of a mock response",
+		Category:    "Mock",
+	},
+	HEALTH_CHECK_PATH: {
+		Type:        TypeString,
+		Value:       "/__beo-echo/health",
+		Description: "Path under each project alias answered with 200 and a JSON status for load balancer probes, before mode handling and limits; empty disables it",
+		Category:    "Mock",
+	},
+	MOCK_ENVIRONMENT: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Environment name of this server (e.g. dev, staging). Responses tagged with environments are picked by the X-Env header first, then this value",
+		Category:    "Mock",
+	},
+
+	// Proxy Configuration
+	PROXY_TLS_VERIFY: {
+		Type:        TypeBoolean,
+		Value:       "false",
+		Description: "Verify upstream TLS certificates when proxying; off accepts any certificate. Projects can override it with proxyTlsVerify",
+		Category:    "Proxy",
+	},
+	PROXY_CA_CERT_PATHS: {
+		Type:        TypeString,
+		Value:       "",
+		Description: "Comma-separated paths of PEM CA bundles trusted for upstream certificates, in addition to the system roots (used when verification is on)",
+		Category:    "Proxy",
+	},
+	PROXY_BUFFER_MAX_BYTES: {
+		Type:        TypeNumber,
+		Value:       "1048576",
+		Description: "Upstream response bodies up to this many bytes are buffered, so they can be decoded for logs and recorded; larger bodies and event streams are streamed to the client as they arrive. 0 buffers every body",
+		Category:    "Proxy",
+	},
+
 	// Landing Page Configuration
 	LANDING_PAGE_ENABLED: {
 		Type:        TypeBoolean,